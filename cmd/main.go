@@ -20,7 +20,11 @@ func main() {
 	}
 
 	// Инициализация логгера
-	logger.BuildLogger(readConfig.LogLevel)
+	logger.BuildLogger(readConfig.LogLevel,
+		logger.WithEncoding(readConfig.LogEncoding),
+		logger.WithComponentLevels(readConfig.LogComponentLevels),
+		logger.WithSampling(readConfig.LogSamplingInitial, readConfig.LogSamplingThereafter),
+	)
 	applogger := logger.Logger().Named("main")
 	defer func() {
 		if err := applogger.Sync(); err != nil {
@@ -3,6 +3,8 @@ package config
 import (
 	"flag"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 )
@@ -11,16 +13,111 @@ type Config struct {
 	GRPCPort      string `env:"GRPC_PORT" envDefault:"50051"`
 	KuCoinBaseURL string `env:"KUCOIN_BASE_URL" envDefault:"https://api.kucoin.com"`
 
+	// GRPCTLSCertFile и GRPCTLSKeyFile включают TLS gRPC-сервера; файлы перечитываются при изменении
+	GRPCTLSCertFile string `env:"GRPC_TLS_CERT_FILE"`
+	GRPCTLSKeyFile  string `env:"GRPC_TLS_KEY_FILE"`
+	// GRPCTLSClientCAFile - CA для проверки сертификатов клиентов
+	GRPCTLSClientCAFile string `env:"GRPC_TLS_CLIENT_CA_FILE"`
+	// GRPCTLSRequireClientCert включает mTLS: клиент без сертификата отклоняется
+	GRPCTLSRequireClientCert bool          `env:"GRPC_TLS_REQUIRE_CLIENT_CERT" envDefault:"false"`
+	GRPCTLSReloadInterval    time.Duration `env:"GRPC_TLS_RELOAD_INTERVAL" envDefault:"30s"`
+
+	// Exchanges - биржи в порядке приоритета: при ошибке первой используется следующая
+	Exchanges      []string `env:"EXCHANGES" envSeparator:"," envDefault:"kucoin"`
+	BinanceBaseURL string   `env:"BINANCE_BASE_URL" envDefault:"https://api.binance.com"`
+	OKXBaseURL     string   `env:"OKX_BASE_URL" envDefault:"https://www.okx.com"`
+
+	// KuCoinRequestTimeout - таймаут одной попытки запроса к REST API KuCoin
+	KuCoinRequestTimeout time.Duration `env:"KUCOIN_REQUEST_TIMEOUT" envDefault:"3s"`
+	// KuCoinRetryMaxAttempts - число попыток запроса вместе с первой; 1 отключает повторы
+	KuCoinRetryMaxAttempts int           `env:"KUCOIN_RETRY_MAX_ATTEMPTS" envDefault:"3"`
+	KuCoinRetryBaseDelay   time.Duration `env:"KUCOIN_RETRY_BASE_DELAY" envDefault:"100ms"`
+	KuCoinRetryMaxDelay    time.Duration `env:"KUCOIN_RETRY_MAX_DELAY" envDefault:"2s"`
+	// KuCoinBreakerFailures - число отказов подряд, после которого запросы к KuCoin прекращаются; 0 отключает автомат
+	KuCoinBreakerFailures    int           `env:"KUCOIN_BREAKER_FAILURES" envDefault:"5"`
+	KuCoinBreakerOpenTimeout time.Duration `env:"KUCOIN_BREAKER_OPEN_TIMEOUT" envDefault:"30s"`
+	// KuCoinRateLimit - бюджет веса запросов к KuCoin за KuCoinRateLimitWindow (публичная квота); 0 отключает ограничитель
+	KuCoinRateLimit       int           `env:"KUCOIN_RATE_LIMIT" envDefault:"2000"`
+	KuCoinRateLimitWindow time.Duration `env:"KUCOIN_RATE_LIMIT_WINDOW" envDefault:"30s"`
+	// KuCoinRateLimitMaxWait - сколько запрос ждет бюджета, прежде чем будет отклонен с ResourceExhausted
+	KuCoinRateLimitMaxWait time.Duration `env:"KUCOIN_RATE_LIMIT_MAX_WAIT" envDefault:"1s"`
+
+	KuCoinWSEnabled     bool          `env:"KUCOIN_WS_ENABLED" envDefault:"false"`
+	KuCoinWSSymbols     []string      `env:"KUCOIN_WS_SYMBOLS" envSeparator:"," envDefault:"BTC-USDT,ETH-USDT"`
+	KuCoinWSChannel     string        `env:"KUCOIN_WS_CHANNEL" envDefault:"ticker"`
+	KuCoinWSMaxQuoteAge time.Duration `env:"KUCOIN_WS_MAX_QUOTE_AGE" envDefault:"5s"`
+
+	SubscribePollInterval time.Duration `env:"SUBSCRIBE_POLL_INTERVAL" envDefault:"1s"`
+	QuoteCacheMaxAge      time.Duration `env:"QUOTE_CACHE_MAX_AGE" envDefault:"1s"`
+	// StaleFallbackMaxAge - возраст сохраненного курса, которым можно ответить при недоступности биржи; 0 - не отвечать
+	StaleFallbackMaxAge time.Duration `env:"STALE_FALLBACK_MAX_AGE" envDefault:"0s"`
+	BatchConcurrency    int           `env:"BATCH_CONCURRENCY" envDefault:"8"`
+
+	// CrossRateMaxLegs - максимальное число пар в пути кросс-курса; 0 отключает кросс-курсы
+	CrossRateMaxLegs int           `env:"CROSS_RATE_MAX_LEGS" envDefault:"3"`
+	CrossRateHubs    []string      `env:"CROSS_RATE_HUBS" envSeparator:"," envDefault:"USDT,USDC,BTC,ETH"`
+	PairGraphMaxAge  time.Duration `env:"PAIR_GRAPH_MAX_AGE" envDefault:"1h"`
+
+	// SymbolCatalogEnabled включает справочник пар биржи: символы запросов нормализуются и проверяются по нему
+	SymbolCatalogEnabled bool          `env:"SYMBOL_CATALOG_ENABLED" envDefault:"true"`
+	SymbolSyncInterval   time.Duration `env:"SYMBOL_SYNC_INTERVAL" envDefault:"1h"`
+
+	// CollectSymbols - символы для фонового сбора курсов; пустой список отключает сбор
+	CollectSymbols     []string      `env:"COLLECT_SYMBOLS" envSeparator:","`
+	CollectInterval    time.Duration `env:"COLLECT_INTERVAL" envDefault:"10s"`
+	CollectConcurrency int           `env:"COLLECT_CONCURRENCY" envDefault:"4"`
+	CollectJitter      time.Duration `env:"COLLECT_JITTER" envDefault:"1s"`
+
+	// HealthCheckInterval - интервал проверки зависимостей для grpc.health.v1 и HealthCheck
+	HealthCheckInterval time.Duration `env:"HEALTH_CHECK_INTERVAL" envDefault:"10s"`
+	HealthProbeTimeout  time.Duration `env:"HEALTH_PROBE_TIMEOUT" envDefault:"3s"`
+	// HealthCollectorMaxAge - время без сохраненных курсов, после которого сборщик считается отставшим; 0 - три интервала сбора
+	HealthCollectorMaxAge time.Duration `env:"HEALTH_COLLECTOR_MAX_AGE" envDefault:"0s"`
+
+	// ClientQuotaRate - запросов в секунду на клиента и метод; 0 отключает квоты
+	ClientQuotaRate  float64 `env:"CLIENT_QUOTA_RATE" envDefault:"0"`
+	ClientQuotaBurst int     `env:"CLIENT_QUOTA_BURST" envDefault:"20"`
+	// ClientQuotaMethods - квоты отдельных методов вида "GetRates=5:10,SubscribeRates=1:2"
+	ClientQuotaMethods string `env:"CLIENT_QUOTA_METHODS"`
+	// ClientAPIKeyHeader - метаданные с API-ключом клиента для аутентификации и REST-шлюза
+	ClientAPIKeyHeader string `env:"CLIENT_API_KEY_HEADER" envDefault:"x-api-key"`
+
+	// AuthEnabled включает аутентификацию gRPC-вызовов по API-ключу или JWT
+	AuthEnabled bool `env:"AUTH_ENABLED" envDefault:"false"`
+	// AuthAPIKeys - статические ключи вида "name:key=scope1 scope2,name2:key2=scope3"
+	AuthAPIKeys string `env:"AUTH_API_KEYS" redact:"true"`
+	// AuthJWKSFile - файл JWKS с ключами проверки JWT; пусто - JWT не принимаются
+	AuthJWKSFile    string `env:"AUTH_JWKS_FILE"`
+	AuthJWTIssuer   string `env:"AUTH_JWT_ISSUER"`
+	AuthJWTAudience string `env:"AUTH_JWT_AUDIENCE"`
+	// AuthPublicMethods - методы, доступные без аутентификации
+	AuthPublicMethods []string `env:"AUTH_PUBLIC_METHODS" envSeparator:"," envDefault:"HealthCheck,/grpc.health.v1.Health/Check,/grpc.health.v1.Health/Watch"`
+	// AuthMethodScopes - scope, необходимый для вызова метода, вида "GetRates=rates:read"
+	AuthMethodScopes string `env:"AUTH_METHOD_SCOPES" envDefault:"GetRates=rates:read,GetRatesBatch=rates:read,GetOrderBook=rates:read,SubscribeRates=rates:read,ListSymbols=rates:read,GetRateHistory=history:read,GetCandles=history:read"`
+	// AuthDefaultScope требуется для остальных методов, в том числе новых RPC и reflection
+	AuthDefaultScope string `env:"AUTH_DEFAULT_SCOPE" envDefault:"admin"`
+
 	DBHost     string `env:"DB_HOST" envDefault:"localhost"`
 	DBPort     string `env:"DB_PORT"`
 	DBUser     string `env:"DB_USER"`
-	DBPassword string `env:"DB_PASSWORD"`
+	DBPassword string `env:"DB_PASSWORD" redact:"true"`
 	DBName     string `env:"DB_NAME"`
 	DBSSLMode  string `env:"DB_SSL_MODE" envDefault:"disable"`
 
 	LogLevel          string `env:"LOG_LEVEL" envDefault:"DEBUG"`
 	EnableDebugServer bool   `env:"ENABLE_DEBUG_SERVER" envDefault:"true"`
-	DebugServerAddr   string `env:"DEBUG_SERVER_ADDR" envDefault:"0.0.0.0:8182"`
+	// DebugServerAddr по умолчанию доступен только локально: сервер не требует аутентификации
+	DebugServerAddr string `env:"DEBUG_SERVER_ADDR" envDefault:"127.0.0.1:8182"`
+	// DebugLogLevelWritable разрешает менять уровень логирования через служебный сервер
+	DebugLogLevelWritable bool `env:"DEBUG_LOG_LEVEL_WRITABLE" envDefault:"false"`
+	// LogEncoding - формат логов: json или console для локальной разработки
+	LogEncoding string `env:"LOG_ENCODING" envDefault:"json"`
+	// LogComponentLevels - уровни компонентов вида "kucoin=debug,repository=warn"
+	LogComponentLevels string `env:"LOG_COMPONENT_LEVELS"`
+	// LogSamplingInitial и LogSamplingThereafter - в течение секунды пишутся первые
+	// LogSamplingInitial одинаковых записей, затем каждая LogSamplingThereafter-я; 0 отключает выборку
+	LogSamplingInitial    int `env:"LOG_SAMPLING_INITIAL" envDefault:"0"`
+	LogSamplingThereafter int `env:"LOG_SAMPLING_THEREAFTER" envDefault:"100"`
 
 	ServiceName    string `env:"SERVICE_NAME" envDefault:"rate-service"`
 	ServiceVersion string `env:"SERVICE_VERSION" envDefault:"1.0.0"`
@@ -28,9 +125,30 @@ type Config struct {
 
 	EnableTracing bool   `env:"ENABLE_TRACING" envDefault:"true"`
 	OTLPEndpoint  string `env:"OTLP_ENDPOINT" envDefault:"localhost:4317"`
+	// OTLPInsecure отключает TLS при подключении к коллектору трассировки
+	OTLPInsecure   bool   `env:"OTLP_INSECURE" envDefault:"false"`
+	OTLPCAFile     string `env:"OTLP_CA_FILE"`
+	OTLPCertFile   string `env:"OTLP_CERT_FILE"`
+	OTLPKeyFile    string `env:"OTLP_KEY_FILE"`
+	OTLPServerName string `env:"OTLP_SERVER_NAME"`
+	// TracingExporter - otlp-grpc, otlp-http, stdout или file (в TracingFilePath)
+	TracingExporter string `env:"TRACING_EXPORTER" envDefault:"otlp-grpc"`
+	TracingFilePath string `env:"TRACING_FILE_PATH" envDefault:"traces.json"`
+	// TracingSamplingRatio - доля записываемых корневых трасс; дочерние следуют решению родителя
+	TracingSamplingRatio float64 `env:"TRACING_SAMPLING_RATIO" envDefault:"1"`
 
 	EnableMetrics   bool   `env:"ENABLE_METRICS" envDefault:"true"`
 	MetricsHTTPAddr string `env:"METRICS_HTTP_ADDR" envDefault:"0.0.0.0:9090"`
+
+	// GatewayEnabled включает REST/JSON-шлюз к gRPC-методам и описание OpenAPI
+	GatewayEnabled  bool   `env:"GATEWAY_ENABLED" envDefault:"true"`
+	GatewayHTTPAddr string `env:"GATEWAY_HTTP_ADDR" envDefault:"0.0.0.0:8080"`
+	// GatewayGRPCCAFile и GatewayGRPCServerName - проверка сертификата gRPC-сервера шлюзом, если включен TLS
+	GatewayGRPCCAFile     string `env:"GATEWAY_GRPC_CA_FILE"`
+	GatewayGRPCServerName string `env:"GATEWAY_GRPC_SERVER_NAME"`
+	// GatewayGRPCCertFile и GatewayGRPCKeyFile - сертификат шлюза, если gRPC-сервер требует mTLS
+	GatewayGRPCCertFile string `env:"GATEWAY_GRPC_CERT_FILE"`
+	GatewayGRPCKeyFile  string `env:"GATEWAY_GRPC_KEY_FILE"`
 }
 
 func ReadConfig() (*Config, error) {
@@ -49,15 +167,22 @@ func ReadConfig() (*Config, error) {
 	flag.StringVar(&config.DBName, "db-name", config.DBName, "Database name")
 	flag.StringVar(&config.DBSSLMode, "db-sslmode", config.DBSSLMode, "Database SSL mode")
 	flag.StringVar(&config.KuCoinBaseURL, "kucoin-base-url", config.KuCoinBaseURL, "KuCoin API base URL")
+	flag.BoolVar(&config.KuCoinWSEnabled, "kucoin-ws-enabled", config.KuCoinWSEnabled, "Enable KuCoin WebSocket market data feed")
 
 	flag.BoolVar(&config.EnableTracing, "enable-tracing",
 		config.EnableTracing, "Enable OpenTelemetry tracing")
 	flag.StringVar(&config.OTLPEndpoint, "otlp-endpoint",
 		config.OTLPEndpoint, "OpenTelemetry collector endpoint")
+	flag.StringVar(&config.TracingExporter, "tracing-exporter",
+		config.TracingExporter, "Trace exporter: otlp-grpc, otlp-http, stdout or file")
+	flag.Float64Var(&config.TracingSamplingRatio, "tracing-sampling-ratio",
+		config.TracingSamplingRatio, "Share of root traces to sample, from 0 to 1")
 	flag.BoolVar(&config.EnableMetrics, "enable-metrics",
 		config.EnableMetrics, "Enable Prometheus metrics")
 	flag.StringVar(&config.MetricsHTTPAddr, "metrics-http-addr",
 		config.MetricsHTTPAddr, "Prometheus metrics HTTP server address")
+	flag.StringVar(&config.GatewayHTTPAddr, "gateway-http-addr",
+		config.GatewayHTTPAddr, "REST gateway HTTP server address")
 
 	flag.Parse()
 
@@ -70,3 +195,37 @@ func (c *Config) GetDBConnString() string {
 
 	return connStr
 }
+
+// redactedValue заменяет значения секретов в Redacted
+const redactedValue = "[REDACTED]"
+
+// Redacted возвращает действующие настройки по именам переменных окружения.
+// Значения полей с тегом redact:"true" (пароли, ключи) скрываются.
+func (c *Config) Redacted() map[string]interface{} {
+	value := reflect.ValueOf(*c)
+	fields := value.Type()
+
+	result := make(map[string]interface{}, fields.NumField())
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		name := field.Tag.Get("env")
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldValue := value.Field(i)
+		switch {
+		case field.Tag.Get("redact") == "true":
+			if !fieldValue.IsZero() {
+				result[name] = redactedValue
+			} else {
+				result[name] = ""
+			}
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			result[name] = fieldValue.Interface().(time.Duration).String()
+		default:
+			result[name] = fieldValue.Interface()
+		}
+	}
+	return result
+}
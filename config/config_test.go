@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Redacted(t *testing.T) {
+	// Arrange
+	config := &Config{
+		GRPCPort:             "50051",
+		DBPassword:           "db-secret",
+		AuthAPIKeys:          "reader:key-secret=rates:read",
+		KuCoinRequestTimeout: 3 * time.Second,
+		CollectSymbols:       []string{"BTC-USDT"},
+	}
+
+	// Act
+	redacted := config.Redacted()
+
+	// Assert
+	assert.Equal(t, "50051", redacted["GRPC_PORT"])
+	assert.Equal(t, "[REDACTED]", redacted["DB_PASSWORD"])
+	assert.Equal(t, "[REDACTED]", redacted["AUTH_API_KEYS"])
+	assert.Equal(t, "3s", redacted["KUCOIN_REQUEST_TIMEOUT"])
+	assert.Equal(t, []string{"BTC-USDT"}, redacted["COLLECT_SYMBOLS"])
+	assert.NotContains(t, redacted, "DBPassword")
+}
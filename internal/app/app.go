@@ -2,33 +2,57 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/config"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/catalog"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/collector"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/debug"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange/binance"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange/kucoin"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange/okx"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/handler/gateway"
 	grpcServer "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/handler/grpc"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/health"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository/postgres"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
 	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/middleware"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/telemetry"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/tlsconfig"
 )
 
 type App struct {
 	config       *config.Config
 	logger       *zap.Logger
 	grpcServer   *grpc.Server
+	gateway      *gateway.Server
+	debug        *debug.Server
 	repo         repository.RateRepository
+	rateService  *service.RateService
+	collector    *collector.Collector
+	symbols      *catalog.Registry
+	health       *health.Checker
+	exchanges    []exchange.Exchange
+	limiter      *exchange.RateLimiter
 	cleanupFuncs []func(context.Context) error
 }
 
@@ -37,7 +61,7 @@ var newRepositoryFunc = postgres.NewRepository
 
 func NewApp(config *config.Config, logger *zap.Logger) (*App, error) {
 	// Создание репозитория
-	repo, err := newRepositoryFunc(config.GetDBConnString(), logger)
+	repo, err := newRepositoryFunc(config.GetDBConnString(), logger.Named("repository"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create repository: %w", err)
 	}
@@ -53,12 +77,27 @@ func NewApp(config *config.Config, logger *zap.Logger) (*App, error) {
 func (a *App) Run(ctx context.Context) error {
 	// Инициализация трассировки
 	if a.config.EnableTracing {
+		otlpTLS, err := tlsconfig.NewClientTLSConfig(tlsconfig.ClientConfig{
+			CAFile:     a.config.OTLPCAFile,
+			CertFile:   a.config.OTLPCertFile,
+			KeyFile:    a.config.OTLPKeyFile,
+			ServerName: a.config.OTLPServerName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to configure OTLP TLS: %w", err)
+		}
+
 		tracingCleanup, err := telemetry.InitTracing(ctx, telemetry.TracingConfig{
 			ServiceName:    a.config.ServiceName,
 			ServiceVersion: a.config.ServiceVersion,
 			Environment:    a.config.Environment,
+			Exporter:       a.config.TracingExporter,
+			SamplingRatio:  a.config.TracingSamplingRatio,
+			FilePath:       a.config.TracingFilePath,
 			OTLPEndpoint:   a.config.OTLPEndpoint,
-		}, a.logger)
+			OTLPInsecure:   a.config.OTLPInsecure,
+			OTLPTLS:        otlpTLS,
+		}, a.logger.Named("telemetry"))
 
 		if err != nil {
 			a.logger.Warn("Не удалось инициализировать трассировку", zap.Error(err))
@@ -74,7 +113,7 @@ func (a *App) Run(ctx context.Context) error {
 			ServiceVersion: a.config.ServiceVersion,
 			Environment:    a.config.Environment,
 			HTTPAddr:       a.config.MetricsHTTPAddr,
-		}, a.logger)
+		}, a.logger.Named("telemetry"))
 
 		if err != nil {
 			a.logger.Warn("Не удалось инициализировать метрики", zap.Error(err))
@@ -88,14 +127,99 @@ func (a *App) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Создание клиента KuCoin
-	kuCoinClient := kucoin.NewKucoinClient(a.config.KuCoinBaseURL, a.logger)
+	// Создание клиентов бирж
+	rateExchange, err := a.buildExchange()
+	if err != nil {
+		return fmt.Errorf("failed to create exchange: %w", err)
+	}
+
+	// Запуск WebSocket-потока лучших цен KuCoin, только если KuCoin есть среди бирж
+	var quoteFeed service.QuoteFeed
+	if a.config.KuCoinWSEnabled && !a.exchangeConfigured("kucoin") {
+		a.logger.Warn("KuCoin WebSocket feed is enabled but kucoin is not in EXCHANGES, feed is not started",
+			zap.Strings("exchanges", a.config.Exchanges))
+	}
+	if a.config.KuCoinWSEnabled && a.exchangeConfigured("kucoin") {
+		feed, err := kucoin.NewWebSocketFeed(a.config.KuCoinBaseURL, kucoin.WebSocketConfig{
+			Symbols:     a.config.KuCoinWSSymbols,
+			Channel:     a.config.KuCoinWSChannel,
+			MaxQuoteAge: a.config.KuCoinWSMaxQuoteAge,
+			MinBackoff:  time.Second,
+			MaxBackoff:  time.Minute,
+			Limiter:     a.kucoinRateLimiter(),
+		}, a.logger.Named("kucoin"))
+		if err != nil {
+			return fmt.Errorf("failed to create KuCoin WebSocket feed: %w", err)
+		}
+
+		feedCtx, cancelFeed := context.WithCancel(ctx)
+		go feed.Run(feedCtx)
+		a.cleanupFuncs = append(a.cleanupFuncs, func(context.Context) error {
+			cancelFeed()
+			return nil
+		})
+
+		quoteFeed = feed
+	}
+
+	// Загрузка справочника пар биржи: по нему проверяются символы запросов
+	// и строится граф кросс-курсов
+	var handlerOptions []grpcServer.Option
+	var symbolSource service.SymbolSource
+	if a.config.SymbolCatalogEnabled {
+		registry, err := a.startSymbolRegistry(ctx, rateExchange)
+		if err != nil {
+			return fmt.Errorf("failed to create symbol registry: %w", err)
+		}
+		if registry != nil {
+			a.symbols = registry
+			symbolSource = registry
+			handlerOptions = append(handlerOptions, grpcServer.WithSymbolCatalog(registry))
+		}
+	}
 
 	// Создание сервиса
-	rateService := service.NewRateService(a.logger, a.repo, kuCoinClient)
+	a.rateService = service.NewRateService(a.logger.Named("service"), a.repo, rateExchange, quoteFeed, service.Config{
+		SubscribePollInterval: a.config.SubscribePollInterval,
+		QuoteCacheMaxAge:      a.config.QuoteCacheMaxAge,
+		StaleFallbackMaxAge:   a.config.StaleFallbackMaxAge,
+		BatchConcurrency:      a.config.BatchConcurrency,
+		CrossRateMaxLegs:      a.config.CrossRateMaxLegs,
+		CrossRateHubs:         a.config.CrossRateHubs,
+		PairGraphMaxAge:       a.config.PairGraphMaxAge,
+		Symbols:               symbolSource,
+	})
+
+	// Запуск фонового сбора курсов
+	if len(a.config.CollectSymbols) > 0 {
+		rateCollector, err := collector.NewCollector(a.logger.Named("collector"), rateExchange, a.repo, collector.Config{
+			Symbols:     a.config.CollectSymbols,
+			Interval:    a.config.CollectInterval,
+			Concurrency: a.config.CollectConcurrency,
+			Jitter:      a.config.CollectJitter,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create collector: %w", err)
+		}
+		a.collector = rateCollector
+		a.collector.Start(ctx)
+	}
+
+	// Запуск проверок зависимостей для grpc.health.v1 и HealthCheck
+	checker, err := a.startHealthChecker(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create health checker: %w", err)
+	}
+	a.health = checker
+	handlerOptions = append(handlerOptions, grpcServer.WithHealthReporter(checker))
+
+	// Запуск служебного HTTP-сервера для диагностики
+	if a.config.EnableDebugServer {
+		a.startDebugServer()
+	}
 
 	// Создание GRPC-сервера
-	rateServiceServer := grpcServer.NewRateServiceServer(a.logger, rateService)
+	rateServiceServer := grpcServer.NewRateServiceServer(a.logger.Named("grpc"), a.rateService, handlerOptions...)
 
 	// Создание и настройка GRPC-сервера с middleware для трассировки и метрик
 	var serverOptions []grpc.ServerOption
@@ -112,9 +236,40 @@ func (a *App) Run(ctx context.Context) error {
 		)
 	}
 
+	// Идентификатор запроса сохраняется до остальных проверок, чтобы он был
+	// в логах и ответах и для отклоненных запросов
+	serverOptions = append(serverOptions,
+		grpc.ChainUnaryInterceptor(middleware.RequestIDUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(middleware.RequestIDStreamServerInterceptor()),
+	)
+
+	// Аутентификация и квоты клиентов проверяются после телеметрии, чтобы отклоненные
+	// запросы попадали в метрики и трассы, а квоты - после аутентификации: клиент
+	// с проверенным ключом или токеном получает свою квоту, остальные - квоту адреса
+	authOptions, err := a.authOptions()
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	serverOptions = append(serverOptions, authOptions...)
+
+	quotaOptions, err := a.quotaOptions()
+	if err != nil {
+		return fmt.Errorf("failed to configure client quotas: %w", err)
+	}
+	serverOptions = append(serverOptions, quotaOptions...)
+
+	tlsOption, err := a.tlsOption(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	if tlsOption != nil {
+		serverOptions = append(serverOptions, tlsOption)
+	}
+
 	// Создание и настройка GRPC-сервера
 	a.grpcServer = grpc.NewServer(serverOptions...)
 	pb.RegisterRateServiceServer(a.grpcServer, rateServiceServer)
+	healthpb.RegisterHealthServer(a.grpcServer, checker.HealthServer())
 	reflection.Register(a.grpcServer)
 
 	// Запуск GRPC-сервера
@@ -139,6 +294,12 @@ func (a *App) Run(ctx context.Context) error {
 		}
 	}()
 
+	// Запуск REST-шлюза
+	if err := a.startGateway(ctx); err != nil {
+		a.Shutdown(ctx)
+		return fmt.Errorf("failed to start REST gateway: %w", err)
+	}
+
 	// Ожидание сигнала завершения или ошибки
 	select {
 	case <-quit:
@@ -158,12 +319,45 @@ func (a *App) Run(ctx context.Context) error {
 
 // Shutdown корректно завершает работу приложения
 func (a *App) Shutdown(ctx context.Context) {
+	// Завершаем потоковые подписки, иначе GracefulStop будет ждать их бесконечно
+	if a.rateService != nil {
+		a.rateService.Close()
+	}
+
+	// NOT_SERVING до остановки сервера, чтобы балансировщики успели снять нагрузку
+	if a.health != nil {
+		a.health.Stop()
+	}
+
+	// Шлюз останавливается первым: его запросы обслуживает gRPC-сервер
+	if a.gateway != nil {
+		if err := a.gateway.Shutdown(ctx); err != nil {
+			a.logger.Error("Failed to shutdown REST gateway", zap.Error(err))
+		} else {
+			a.logger.Info("REST gateway successfully shutdown")
+		}
+	}
+
 	// Graceful shutdown GRPC сервера
 	if a.grpcServer != nil {
 		a.grpcServer.GracefulStop()
 		a.logger.Info("GRPC server successfully shutdown")
 	}
 
+	// Сборщик должен остановиться до закрытия базы данных
+	if a.collector != nil {
+		a.collector.Stop()
+	}
+	if a.symbols != nil {
+		a.symbols.Stop()
+	}
+
+	if a.debug != nil {
+		if err := a.debug.Shutdown(ctx); err != nil {
+			a.logger.Error("Failed to shutdown debug HTTP server", zap.Error(err))
+		}
+	}
+
 	// Закрытие соединения с базой данных
 	if a.repo != nil {
 		if err := a.repo.Close(); err != nil {
@@ -181,6 +375,335 @@ func (a *App) Shutdown(ctx context.Context) {
 	}
 }
 
+// buildExchange создает клиентов бирж из конфигурации.
+// Если бирж несколько, они опрашиваются по порядку до первого успешного ответа.
+func (a *App) buildExchange() (exchange.Exchange, error) {
+	exchanges := make([]exchange.Exchange, 0, len(a.config.Exchanges))
+	for _, name := range a.config.Exchanges {
+		switch normalizeExchangeName(name) {
+		case "kucoin":
+			exchanges = append(exchanges, kucoin.NewKucoinClient(a.config.KuCoinBaseURL, a.logger.Named("kucoin"), a.kucoinOptions()...))
+		case "binance":
+			exchanges = append(exchanges, binance.NewBinanceClient(a.config.BinanceBaseURL, a.logger.Named("binance")))
+		case "okx":
+			exchanges = append(exchanges, okx.NewOKXClient(a.config.OKXBaseURL, a.logger.Named("okx")))
+		default:
+			return nil, fmt.Errorf("unknown exchange: %q", name)
+		}
+	}
+
+	a.exchanges = exchanges
+
+	switch len(exchanges) {
+	case 0:
+		return nil, fmt.Errorf("no exchanges configured")
+	case 1:
+		return exchanges[0], nil
+	default:
+		return exchange.NewFailover(a.logger.Named("exchange"), exchanges...), nil
+	}
+}
+
+// tlsOption возвращает TLS-сертификаты gRPC-сервера и запускает их перезагрузку
+// при изменении файлов; nil, если TLS не настроен
+func (a *App) tlsOption(ctx context.Context) (grpc.ServerOption, error) {
+	if a.config.GRPCTLSCertFile == "" && a.config.GRPCTLSKeyFile == "" {
+		a.logger.Warn("TLS is not configured, gRPC server accepts plaintext connections")
+		return nil, nil
+	}
+
+	reloader, err := tlsconfig.NewReloader(a.logger.Named("tls"), tlsconfig.ServerConfig{
+		CertFile:          a.config.GRPCTLSCertFile,
+		KeyFile:           a.config.GRPCTLSKeyFile,
+		ClientCAFile:      a.config.GRPCTLSClientCAFile,
+		RequireClientCert: a.config.GRPCTLSRequireClientCert,
+		ReloadInterval:    a.config.GRPCTLSReloadInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reloader.Start(ctx)
+	a.cleanupFuncs = append(a.cleanupFuncs, func(context.Context) error {
+		reloader.Stop()
+		return nil
+	})
+
+	a.logger.Info("TLS is enabled for gRPC server",
+		zap.String("cert_file", a.config.GRPCTLSCertFile),
+		zap.Bool("client_cert_required", a.config.GRPCTLSRequireClientCert))
+	return grpc.Creds(credentials.NewTLS(reloader.TLSConfig())), nil
+}
+
+// startDebugServer запускает служебный HTTP-сервер с pprof, уровнем логирования,
+// конфигурацией без секретов и состоянием кэшей и фонового сбора
+func (a *App) startDebugServer() {
+	opts := []debug.Option{debug.WithCache(a.rateService)}
+	if a.collector != nil {
+		opts = append(opts, debug.WithCollector(a.collector))
+	}
+
+	a.debug = debug.NewServer(a.logger.Named("debug"), debug.Config{
+		Addr:             a.config.DebugServerAddr,
+		LogLevelWritable: a.config.DebugLogLevelWritable,
+		Settings:         a.config.Redacted(),
+		ServiceName:      a.config.ServiceName,
+		ServiceVersion:   a.config.ServiceVersion,
+		Environment:      a.config.Environment,
+	}, opts...)
+	a.debug.Start()
+}
+
+// startGateway запускает REST-шлюз. Шлюз обращается к gRPC-серверу по сети, а не
+// вызывает обработчики напрямую, чтобы к REST-запросам применялись те же
+// аутентификация, квоты и телеметрия.
+func (a *App) startGateway(ctx context.Context) error {
+	if !a.config.GatewayEnabled {
+		return nil
+	}
+
+	var gatewayTLS *tls.Config
+	if a.config.GRPCTLSCertFile != "" {
+		// Без своего сертификата шлюз запустился бы, но каждый REST-запрос
+		// завершался бы ошибкой рукопожатия TLS
+		if a.config.GRPCTLSRequireClientCert && (a.config.GatewayGRPCCertFile == "" || a.config.GatewayGRPCKeyFile == "") {
+			return errors.New("gateway client certificate and key are required when the gRPC server requires client certificates")
+		}
+
+		serverName := a.config.GatewayGRPCServerName
+		if serverName == "" {
+			serverName = "localhost"
+		}
+		var err error
+		gatewayTLS, err = tlsconfig.NewClientTLSConfig(tlsconfig.ClientConfig{
+			CAFile:     a.config.GatewayGRPCCAFile,
+			CertFile:   a.config.GatewayGRPCCertFile,
+			KeyFile:    a.config.GatewayGRPCKeyFile,
+			ServerName: serverName,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to configure gateway TLS: %w", err)
+		}
+	}
+
+	var dialOptions []grpc.DialOption
+	if a.config.EnableTracing {
+		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+
+	server, err := gateway.NewServer(ctx, a.logger.Named("gateway"), gateway.Config{
+		Addr:           a.config.GatewayHTTPAddr,
+		GRPCEndpoint:   "localhost:" + a.config.GRPCPort,
+		TLS:            gatewayTLS,
+		ForwardHeaders: []string{a.config.ClientAPIKeyHeader, middleware.RequestIDHeader},
+		DialOptions:    dialOptions,
+	})
+	if err != nil {
+		return err
+	}
+	a.gateway = server
+	a.gateway.Start()
+	return nil
+}
+
+// authOptions возвращает перехватчики аутентификации; nil, если она отключена
+func (a *App) authOptions() ([]grpc.ServerOption, error) {
+	if !a.config.AuthEnabled {
+		a.logger.Warn("Authentication is disabled, gRPC methods are available without credentials")
+		return nil, nil
+	}
+
+	apiKeys, err := middleware.ParseAPIKeys(a.config.AuthAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	methodScopes, err := middleware.ParseMethodScopes(a.config.AuthMethodScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks *middleware.JWKS
+	if a.config.AuthJWKSFile != "" {
+		jwks, err = middleware.LoadJWKS(a.config.AuthJWKSFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(apiKeys) == 0 && jwks == nil {
+		return nil, fmt.Errorf("authentication is enabled but neither AUTH_API_KEYS nor AUTH_JWKS_FILE is set")
+	}
+
+	auth := middleware.NewAuth(a.logger.Named("auth"), middleware.AuthConfig{
+		APIKeys:      apiKeys,
+		APIKeyHeader: a.config.ClientAPIKeyHeader,
+		JWKS:         jwks,
+		Validation: middleware.TokenValidation{
+			Issuer:   a.config.AuthJWTIssuer,
+			Audience: a.config.AuthJWTAudience,
+		},
+		PublicMethods: a.config.AuthPublicMethods,
+		MethodScopes:  methodScopes,
+		DefaultScope:  a.config.AuthDefaultScope,
+	})
+
+	a.logger.Info("Authentication is enabled",
+		zap.Int("api_keys", len(apiKeys)),
+		zap.Bool("jwt", jwks != nil),
+		zap.Strings("public_methods", a.config.AuthPublicMethods))
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(auth.StreamServerInterceptor()),
+	}, nil
+}
+
+// quotaOptions возвращает перехватчики квот клиентов; nil, если квоты отключены.
+// Проверки работоспособности не ограничиваются, чтобы балансировщики не получали отказ.
+func (a *App) quotaOptions() ([]grpc.ServerOption, error) {
+	if a.config.ClientQuotaRate <= 0 {
+		return nil, nil
+	}
+
+	methods, err := middleware.ParseLimits(a.config.ClientQuotaMethods)
+	if err != nil {
+		return nil, err
+	}
+	for _, method := range []string{healthpb.Health_Check_FullMethodName, healthpb.Health_Watch_FullMethodName} {
+		if _, ok := methods[method]; !ok {
+			methods[method] = middleware.Limit{}
+		}
+	}
+
+	quota := middleware.NewQuota(middleware.QuotaConfig{
+		Default: middleware.Limit{Rate: a.config.ClientQuotaRate, Burst: a.config.ClientQuotaBurst},
+		Methods: methods,
+	})
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(quota.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(quota.StreamServerInterceptor()),
+	}, nil
+}
+
+// kucoinOptions настраивает повторы, автомат отключения и ограничитель запросов клиента KuCoin
+func (a *App) kucoinOptions() []kucoin.Option {
+	opts := []kucoin.Option{
+		kucoin.WithRetryPolicy(exchange.RetryPolicy{
+			MaxAttempts: a.config.KuCoinRetryMaxAttempts,
+			BaseDelay:   a.config.KuCoinRetryBaseDelay,
+			MaxDelay:    a.config.KuCoinRetryMaxDelay,
+		}),
+	}
+	if a.config.KuCoinRequestTimeout > 0 {
+		opts = append(opts, kucoin.WithRequestTimeout(a.config.KuCoinRequestTimeout))
+	}
+	if a.config.KuCoinBreakerFailures > 0 {
+		opts = append(opts, kucoin.WithCircuitBreaker(exchange.BreakerConfig{
+			FailureThreshold: a.config.KuCoinBreakerFailures,
+			OpenTimeout:      a.config.KuCoinBreakerOpenTimeout,
+		}))
+	}
+	if limiter := a.kucoinRateLimiter(); limiter != nil {
+		opts = append(opts, kucoin.WithRateLimiter(limiter))
+	}
+	return opts
+}
+
+// kucoinRateLimiter возвращает ограничитель запросов к KuCoin, общий для REST-клиента
+// и WebSocket-потока, так как квота KuCoin общая на IP; nil, если он отключен
+func (a *App) kucoinRateLimiter() *exchange.RateLimiter {
+	if a.config.KuCoinRateLimit <= 0 {
+		return nil
+	}
+	if a.limiter == nil {
+		a.limiter = exchange.NewRateLimiter("kucoin", exchange.LimiterConfig{
+			Limit:   a.config.KuCoinRateLimit,
+			Window:  a.config.KuCoinRateLimitWindow,
+			MaxWait: a.config.KuCoinRateLimitMaxWait,
+		})
+	}
+	return a.limiter
+}
+
+// startSymbolRegistry загружает справочник пар и запускает его обновление.
+// Возвращает nil, если биржа не умеет отдавать список пар.
+func (a *App) startSymbolRegistry(ctx context.Context, rateExchange exchange.Exchange) (*catalog.Registry, error) {
+	lister, ok := rateExchange.(exchange.SymbolLister)
+	if !ok {
+		a.logger.Warn("Exchange does not support symbol listing, symbol catalog is disabled")
+		return nil, nil
+	}
+
+	// Справочник сохраняется в БД, чтобы пережить перезапуск при недоступной бирже
+	symbolRepo, _ := a.repo.(repository.SymbolRepository)
+
+	registry, err := catalog.NewRegistry(a.logger.Named("catalog"), lister, symbolRepo, a.config.SymbolSyncInterval)
+	if err != nil {
+		return nil, err
+	}
+	registry.Start(ctx)
+	return registry, nil
+}
+
+// startHealthChecker регистрирует пробы зависимостей и запускает их периодическую проверку
+func (a *App) startHealthChecker(ctx context.Context) (*health.Checker, error) {
+	checker, err := health.NewChecker(a.logger.Named("health"), health.Config{
+		Interval: a.config.HealthCheckInterval,
+		Timeout:  a.config.HealthProbeTimeout,
+		Services: []string{pb.RateService_ServiceDesc.ServiceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if pinger, ok := a.repo.(repository.Pinger); ok {
+		checker.Register(health.Component{Name: "database", Probe: pinger.Ping, Critical: true})
+	}
+
+	// Биржи взаимозаменяемы: сервис работает, пока отвечает хотя бы одна
+	for _, rateExchange := range a.exchanges {
+		if pinger, ok := rateExchange.(exchange.Pinger); ok {
+			checker.Register(health.Component{
+				Name:     "exchange:" + rateExchange.Name(),
+				Probe:    pinger.Ping,
+				Critical: true,
+				Group:    "exchange",
+			})
+		}
+	}
+
+	// Отставание сборщика - пропуски в истории, но не отказ в обслуживании
+	if a.collector != nil {
+		maxAge := a.config.HealthCollectorMaxAge
+		if maxAge <= 0 {
+			maxAge = 3 * a.config.CollectInterval
+		}
+		rateCollector := a.collector
+		checker.Register(health.Component{Name: "collector", Probe: func(context.Context) error {
+			if stale := rateCollector.StaleSymbols(maxAge); len(stale) > 0 {
+				return fmt.Errorf("no rates saved within %s for %s", maxAge, strings.Join(stale, ","))
+			}
+			return nil
+		}})
+	}
+
+	checker.Start(ctx)
+	return checker, nil
+}
+
+// exchangeConfigured сообщает, есть ли биржа среди настроенных в EXCHANGES
+func (a *App) exchangeConfigured(name string) bool {
+	for _, configured := range a.config.Exchanges {
+		if normalizeExchangeName(configured) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeExchangeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
 // runMigrations запускает миграции базы данных
 func (a *App) runMigrations() error {
 	db, err := goose.OpenDBWithDriver("pgx", a.config.GetDBConnString())
@@ -22,6 +22,11 @@ func (m *MockRepository) SaveRate(ctx context.Context, rate model.Rate) error {
 	return args.Error(0)
 }
 
+func (m *MockRepository) SaveRates(ctx context.Context, rates []model.Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
 func (m *MockRepository) GetLatestRate(ctx context.Context, symbol string) (*model.Rate, error) {
 	args := m.Called(ctx, symbol)
 	if args.Get(0) == nil {
@@ -30,6 +35,22 @@ func (m *MockRepository) GetLatestRate(ctx context.Context, symbol string) (*mod
 	return args.Get(0).(*model.Rate), args.Error(1)
 }
 
+func (m *MockRepository) GetRateHistory(ctx context.Context, query repository.HistoryQuery) ([]model.Rate, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Rate), args.Error(1)
+}
+
+func (m *MockRepository) GetCandles(ctx context.Context, query repository.CandleQuery) ([]model.Candle, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Candle), args.Error(1)
+}
+
 func (m *MockRepository) Close() error {
 	args := m.Called()
 	return args.Error(0)
@@ -134,3 +155,66 @@ func TestShutdown_WithError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	// Мы не можем проверить логгирование напрямую, так как используем zap.NewNop()
 }
+
+func TestBuildExchange(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("single exchange", func(t *testing.T) {
+		app := &App{logger: logger, config: &config.Config{Exchanges: []string{"okx"}}}
+
+		exchange, err := app.buildExchange()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "okx", exchange.Name())
+	})
+
+	t.Run("failover across exchanges", func(t *testing.T) {
+		app := &App{logger: logger, config: &config.Config{Exchanges: []string{"KuCoin", " binance", "okx"}}}
+
+		exchange, err := app.buildExchange()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "kucoin,binance,okx", exchange.Name())
+	})
+
+	t.Run("unknown exchange", func(t *testing.T) {
+		app := &App{logger: logger, config: &config.Config{Exchanges: []string{"kucoin", "mtgox"}}}
+
+		exchange, err := app.buildExchange()
+
+		assert.Error(t, err)
+		assert.Nil(t, exchange)
+		assert.Contains(t, err.Error(), "unknown exchange")
+	})
+
+	t.Run("no exchanges", func(t *testing.T) {
+		app := &App{logger: logger, config: &config.Config{}}
+
+		_, err := app.buildExchange()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestExchangeConfigured(t *testing.T) {
+	app := &App{logger: zap.NewNop(), config: &config.Config{Exchanges: []string{"binance", " KuCoin"}}}
+
+	assert.True(t, app.exchangeConfigured("kucoin"))
+	assert.True(t, app.exchangeConfigured("binance"))
+	assert.False(t, app.exchangeConfigured("okx"))
+}
+
+func TestStartGateway_MTLSRequiresGatewayCertificate(t *testing.T) {
+	app := &App{logger: zap.NewNop(), config: &config.Config{
+		GatewayEnabled:           true,
+		GRPCTLSCertFile:          "server.pem",
+		GRPCTLSKeyFile:           "server.key",
+		GRPCTLSClientCAFile:      "ca.pem",
+		GRPCTLSRequireClientCert: true,
+	}}
+
+	err := app.startGateway(context.Background())
+
+	assert.ErrorContains(t, err, "gateway client certificate and key are required")
+	assert.Nil(t, app.gateway)
+}
@@ -0,0 +1,311 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+)
+
+var (
+	// ErrInvalidInterval возвращается, если интервал обновления справочника не положительный
+	ErrInvalidInterval = errors.New("symbol sync interval must be positive")
+	// ErrInvalidSymbol - символ не удается привести к виду BASE-QUOTE
+	ErrInvalidSymbol = errors.New("invalid symbol")
+	// ErrUnknownSymbol - символ записан корректно, но такой пары в справочнике нет
+	ErrUnknownSymbol = errors.New("unknown symbol")
+)
+
+// Registry - справочник торгуемых пар биржи. Загружается при старте и обновляется
+// по расписанию; последний полученный список сохраняется в БД, чтобы после
+// перезапуска справочник был доступен и при недоступной бирже.
+type Registry struct {
+	logger   *zap.Logger
+	lister   exchange.SymbolLister
+	repo     repository.SymbolRepository
+	interval time.Duration
+	tracer   trace.Tracer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu         sync.RWMutex
+	symbols    []model.Symbol
+	bySymbol   map[string]model.Symbol
+	compact    map[string]string
+	currencies map[string]struct{}
+	updatedAt  time.Time
+}
+
+// NewRegistry создает справочник. repo может быть nil, тогда список пар не сохраняется.
+func NewRegistry(
+	logger *zap.Logger,
+	lister exchange.SymbolLister,
+	repo repository.SymbolRepository,
+	interval time.Duration,
+) (*Registry, error) {
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	return &Registry{
+		logger:   logger,
+		lister:   lister,
+		repo:     repo,
+		interval: interval,
+		tracer:   otel.Tracer("symbol-registry"),
+	}, nil
+}
+
+// Start загружает справочник и запускает его периодическое обновление.
+// Если биржа недоступна при старте, используется список, сохраненный в БД.
+func (r *Registry) Start(ctx context.Context) {
+	if err := r.Sync(ctx); err != nil {
+		r.logger.Warn("Failed to load symbols from exchange, using stored catalog", zap.Error(err))
+		r.loadStored(ctx)
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+}
+
+// Stop останавливает обновление справочника
+func (r *Registry) Stop() {
+	if r.cancel == nil {
+		return
+	}
+
+	r.cancel()
+	r.wg.Wait()
+	r.logger.Info("Symbol registry stopped")
+}
+
+func (r *Registry) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Sync(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Warn("Failed to sync symbols", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Sync загружает список пар с биржи, сохраняет его в БД и заменяет справочник
+func (r *Registry) Sync(ctx context.Context) error {
+	ctx, span := r.tracer.Start(ctx, "SymbolRegistry.Sync")
+	defer span.End()
+
+	symbols, err := r.lister.ListSymbols(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list symbols")
+		span.RecordError(err)
+		return fmt.Errorf("failed to list symbols: %w", err)
+	}
+	if len(symbols) == 0 {
+		span.SetStatus(codes.Error, "Empty symbol list")
+		return errors.New("exchange returned empty symbol list")
+	}
+
+	r.replace(symbols)
+
+	if r.repo != nil {
+		// Справочник в памяти уже обновлен, ошибка БД влияет только на следующий старт
+		if err := r.repo.ReplaceSymbols(ctx, r.Symbols()); err != nil {
+			r.logger.Warn("Failed to store symbols", zap.Error(err))
+		}
+	}
+
+	span.SetAttributes(attribute.Int("count", len(symbols)))
+	span.SetStatus(codes.Ok, "Symbols synced")
+	r.logger.Info("Symbol catalog synced", zap.Int("count", len(symbols)))
+	return nil
+}
+
+// loadStored загружает справочник, сохраненный при предыдущей синхронизации
+func (r *Registry) loadStored(ctx context.Context) {
+	if r.repo == nil {
+		return
+	}
+
+	symbols, err := r.repo.ListSymbols(ctx)
+	if err != nil {
+		r.logger.Warn("Failed to load stored symbols", zap.Error(err))
+		return
+	}
+	if len(symbols) == 0 {
+		return
+	}
+
+	r.replace(symbols)
+	r.logger.Info("Symbol catalog loaded from database", zap.Int("count", len(symbols)))
+}
+
+// replace строит индексы по новому списку пар и подменяет справочник целиком
+func (r *Registry) replace(symbols []model.Symbol) {
+	bySymbol := make(map[string]model.Symbol, len(symbols))
+	compact := make(map[string]string, len(symbols))
+	ambiguous := make(map[string]struct{})
+	currencies := make(map[string]struct{})
+
+	for _, symbol := range symbols {
+		symbol.Symbol = strings.ToUpper(symbol.Symbol)
+		symbol.Base = strings.ToUpper(symbol.Base)
+		symbol.Quote = strings.ToUpper(symbol.Quote)
+		if symbol.Base == "" || symbol.Quote == "" {
+			continue
+		}
+		bySymbol[symbol.Symbol] = symbol
+		currencies[symbol.Base] = struct{}{}
+		currencies[symbol.Quote] = struct{}{}
+
+		// BTCUSDT без разделителя однозначно определяет пару, только если нет другой с той же склейкой
+		key := symbol.Base + symbol.Quote
+		if existing, ok := compact[key]; ok && existing != symbol.Symbol {
+			ambiguous[key] = struct{}{}
+		}
+		compact[key] = symbol.Symbol
+	}
+	for key := range ambiguous {
+		delete(compact, key)
+	}
+
+	sorted := make([]model.Symbol, 0, len(bySymbol))
+	for _, symbol := range bySymbol {
+		sorted = append(sorted, symbol)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Symbol < sorted[j].Symbol })
+
+	r.mu.Lock()
+	r.symbols = sorted
+	r.bySymbol = bySymbol
+	r.compact = compact
+	r.currencies = currencies
+	r.updatedAt = time.Now()
+	r.mu.Unlock()
+}
+
+// Symbols возвращает пары справочника, упорядоченные по символу
+func (r *Registry) Symbols() []model.Symbol {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.symbols
+}
+
+// UpdatedAt возвращает время последнего обновления справочника; нулевое, если он не загружен
+func (r *Registry) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.updatedAt
+}
+
+// Normalize приводит символ к виду BASE-QUOTE: регистр не важен, разделителем
+// может быть "-", "/" или "_", а слитная запись (btcusdt) разбирается по справочнику.
+// Наличие пары в справочнике не проверяется.
+func (r *Registry) Normalize(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", fmt.Errorf("%w: symbol is required", ErrInvalidSymbol)
+	}
+
+	symbol = strings.NewReplacer("/", "-", "_", "-").Replace(symbol)
+	for _, c := range symbol {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '-' {
+			return "", fmt.Errorf("%w: %q contains unsupported characters", ErrInvalidSymbol, symbol)
+		}
+	}
+
+	parts := strings.Split(symbol, "-")
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return symbol, nil
+	case len(parts) == 1:
+		if resolved, ok := r.splitCompact(symbol); ok {
+			return resolved, nil
+		}
+		if len(r.Symbols()) == 0 {
+			return "", fmt.Errorf("%w: %q must be in BASE-QUOTE form", ErrInvalidSymbol, symbol)
+		}
+		return "", fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
+	default:
+		return "", fmt.Errorf("%w: %q must be in BASE-QUOTE form", ErrInvalidSymbol, symbol)
+	}
+}
+
+// splitCompact разбирает слитную запись: сначала среди торгуемых пар,
+// затем среди пар известных валют (кросс-курс), если разбиение единственное
+func (r *Registry) splitCompact(symbol string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if resolved, ok := r.compact[symbol]; ok {
+		return resolved, true
+	}
+
+	var resolved string
+	for i := 1; i < len(symbol); i++ {
+		base, quote := symbol[:i], symbol[i:]
+		_, baseKnown := r.currencies[base]
+		_, quoteKnown := r.currencies[quote]
+		if !baseKnown || !quoteKnown {
+			continue
+		}
+		if resolved != "" {
+			return "", false
+		}
+		resolved = base + "-" + quote
+	}
+	return resolved, resolved != ""
+}
+
+// Resolve нормализует символ и проверяет его по справочнику. Пара, которой нет
+// в справочнике, допускается, если обе валюты известны: ее курс можно рассчитать
+// через промежуточные пары. Пока справочник не загружен, проверяется только формат.
+func (r *Registry) Resolve(symbol string) (string, error) {
+	normalized, err := r.Normalize(symbol)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.symbols) == 0 {
+		return normalized, nil
+	}
+	if _, ok := r.bySymbol[normalized]; ok {
+		return normalized, nil
+	}
+
+	parts := strings.Split(normalized, "-")
+	_, baseKnown := r.currencies[parts[0]]
+	_, quoteKnown := r.currencies[parts[1]]
+	if baseKnown && quoteKnown && parts[0] != parts[1] {
+		return normalized, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnknownSymbol, normalized)
+}
@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+type MockSymbolLister struct {
+	mock.Mock
+}
+
+func (m *MockSymbolLister) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Symbol), args.Error(1)
+}
+
+type MockSymbolRepository struct {
+	mock.Mock
+}
+
+func (m *MockSymbolRepository) ReplaceSymbols(ctx context.Context, symbols []model.Symbol) error {
+	args := m.Called(ctx, symbols)
+	return args.Error(0)
+}
+
+func (m *MockSymbolRepository) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Symbol), args.Error(1)
+}
+
+func testSymbols() []model.Symbol {
+	return []model.Symbol{
+		{Symbol: "ETH-USDT", Base: "ETH", Quote: "USDT"},
+		{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETH-BTC", Base: "ETH", Quote: "BTC"},
+	}
+}
+
+func newLoadedRegistry(t *testing.T) *Registry {
+	lister := new(MockSymbolLister)
+	lister.On("ListSymbols", mock.Anything).Return(testSymbols(), nil)
+
+	registry, err := NewRegistry(zap.NewNop(), lister, nil, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Sync(context.Background()))
+	return registry
+}
+
+func TestNewRegistry_InvalidInterval(t *testing.T) {
+	_, err := NewRegistry(zap.NewNop(), new(MockSymbolLister), nil, 0)
+
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	registry := newLoadedRegistry(t)
+
+	tests := []struct {
+		name    string
+		symbol  string
+		want    string
+		wantErr error
+	}{
+		{name: "canonical", symbol: "BTC-USDT", want: "BTC-USDT"},
+		{name: "lower case", symbol: "btc-usdt", want: "BTC-USDT"},
+		{name: "slash separator", symbol: "BTC/USDT", want: "BTC-USDT"},
+		{name: "underscore separator", symbol: " btc_usdt ", want: "BTC-USDT"},
+		{name: "compact", symbol: "btcusdt", want: "BTC-USDT"},
+		{name: "compact cross pair", symbol: "usdteth", want: "USDT-ETH"},
+		{name: "cross pair of known currencies", symbol: "BTC-ETH", want: "BTC-ETH"},
+		{name: "unknown currency", symbol: "DOGE-USDT", wantErr: ErrUnknownSymbol},
+		{name: "unknown compact", symbol: "dogeusdt", wantErr: ErrUnknownSymbol},
+		{name: "same currency", symbol: "USDT-USDT", wantErr: ErrUnknownSymbol},
+		{name: "empty", symbol: "  ", wantErr: ErrInvalidSymbol},
+		{name: "too many parts", symbol: "BTC-USDT-X", wantErr: ErrInvalidSymbol},
+		{name: "missing quote", symbol: "BTC-", wantErr: ErrInvalidSymbol},
+		{name: "unsupported characters", symbol: "BTC:USDT", wantErr: ErrInvalidSymbol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.Resolve(tt.symbol)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistry_ResolveBeforeLoad(t *testing.T) {
+	registry, err := NewRegistry(zap.NewNop(), new(MockSymbolLister), nil, time.Hour)
+	assert.NoError(t, err)
+
+	// Без справочника проверяется только формат
+	symbol, err := registry.Resolve("doge/usdt")
+	assert.NoError(t, err)
+	assert.Equal(t, "DOGE-USDT", symbol)
+
+	_, err = registry.Resolve("dogeusdt")
+	assert.ErrorIs(t, err, ErrInvalidSymbol)
+}
+
+func TestRegistry_SyncStoresSymbols(t *testing.T) {
+	// Arrange
+	lister := new(MockSymbolLister)
+	repo := new(MockSymbolRepository)
+	lister.On("ListSymbols", mock.Anything).Return(testSymbols(), nil)
+	repo.On("ReplaceSymbols", mock.Anything, mock.MatchedBy(func(symbols []model.Symbol) bool {
+		return len(symbols) == 3 && symbols[0].Symbol == "BTC-USDT"
+	})).Return(nil)
+
+	registry, err := NewRegistry(zap.NewNop(), lister, repo, time.Hour)
+	assert.NoError(t, err)
+
+	// Act
+	err = registry.Sync(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, registry.Symbols(), 3)
+	assert.False(t, registry.UpdatedAt().IsZero())
+	repo.AssertExpectations(t)
+}
+
+func TestRegistry_StartFallsBackToStored(t *testing.T) {
+	// Arrange
+	lister := new(MockSymbolLister)
+	repo := new(MockSymbolRepository)
+	lister.On("ListSymbols", mock.Anything).Return(nil, errors.New("kucoin is down"))
+	repo.On("ListSymbols", mock.Anything).Return(testSymbols(), nil)
+
+	registry, err := NewRegistry(zap.NewNop(), lister, repo, time.Hour)
+	assert.NoError(t, err)
+
+	// Act
+	registry.Start(context.Background())
+	defer registry.Stop()
+
+	// Assert
+	symbol, err := registry.Resolve("ethbtc")
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH-BTC", symbol)
+	repo.AssertNotCalled(t, "ReplaceSymbols", mock.Anything, mock.Anything)
+}
+
+func TestRegistry_SyncKeepsCatalogOnError(t *testing.T) {
+	// Arrange
+	lister := new(MockSymbolLister)
+	lister.On("ListSymbols", mock.Anything).Return(testSymbols(), nil).Once()
+	lister.On("ListSymbols", mock.Anything).Return(nil, errors.New("kucoin is down")).Once()
+
+	registry, err := NewRegistry(zap.NewNop(), lister, nil, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Sync(context.Background()))
+
+	// Act
+	err = registry.Sync(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+	assert.Len(t, registry.Symbols(), 3)
+}
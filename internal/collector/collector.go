@@ -0,0 +1,260 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/telemetry"
+)
+
+// Config содержит настройки фонового сбора курсов
+type Config struct {
+	Symbols  []string
+	Interval time.Duration
+	// Concurrency - максимальное число одновременных запросов к бирже
+	Concurrency int
+	// Jitter - максимальная случайная задержка перед запросом символа,
+	// чтобы запросы одного раунда не уходили на биржу одновременно
+	Jitter time.Duration
+}
+
+// Collector периодически запрашивает курсы настроенных символов и сохраняет их в БД
+type Collector struct {
+	logger   *zap.Logger
+	exchange exchange.Exchange
+	repo     repository.RateRepository
+	config   Config
+	tracer   trace.Tracer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu          sync.RWMutex
+	lastSuccess map[string]time.Time
+	startedAt   time.Time
+}
+
+// ErrInvalidInterval возвращается, если интервал сбора не положительный
+var ErrInvalidInterval = errors.New("collect interval must be positive")
+
+// NewCollector создает сборщик. Символы очищаются от пробелов, пустые и повторы отбрасываются.
+func NewCollector(
+	logger *zap.Logger,
+	exchange exchange.Exchange,
+	repo repository.RateRepository,
+	config Config,
+) (*Collector, error) {
+	if config.Interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	config.Symbols = normalizeSymbols(config.Symbols)
+
+	return &Collector{
+		logger:      logger,
+		exchange:    exchange,
+		repo:        repo,
+		config:      config,
+		tracer:      otel.Tracer("rate-collector"),
+		lastSuccess: make(map[string]time.Time),
+	}, nil
+}
+
+// normalizeSymbols убирает пробелы вокруг символов, пустые символы и повторы
+func normalizeSymbols(symbols []string) []string {
+	seen := make(map[string]struct{}, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			continue
+		}
+		if _, ok := seen[symbol]; ok {
+			continue
+		}
+		seen[symbol] = struct{}{}
+		result = append(result, symbol)
+	}
+	return result
+}
+
+// Start запускает сбор курсов в фоне. Первый раунд выполняется сразу.
+func (c *Collector) Start(ctx context.Context) {
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.mu.Lock()
+	c.startedAt = time.Now()
+	c.mu.Unlock()
+
+	c.logger.Info("Starting rate collector",
+		zap.Strings("symbols", c.config.Symbols),
+		zap.Duration("interval", c.config.Interval),
+		zap.Int("concurrency", c.config.Concurrency))
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run(ctx)
+	}()
+}
+
+// Stop останавливает сбор и дожидается завершения текущего раунда
+func (c *Collector) Stop() {
+	if c.cancel == nil {
+		return
+	}
+
+	c.cancel()
+	c.wg.Wait()
+	c.logger.Info("Rate collector stopped")
+}
+
+// LastSuccess возвращает время последнего успешного сохранения курса по символу
+func (c *Collector) LastSuccess(symbol string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.lastSuccess[symbol]
+	return t, ok
+}
+
+// StaleSymbols возвращает символы, курс которых не сохранялся дольше maxAge.
+// Символ без единого сохранения считается устаревшим, только если сбор идет дольше maxAge.
+func (c *Collector) StaleSymbols(maxAge time.Duration) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	var stale []string
+	for _, symbol := range c.config.Symbols {
+		last, ok := c.lastSuccess[symbol]
+		if !ok {
+			last = c.startedAt
+		}
+		if now.Sub(last) > maxAge {
+			stale = append(stale, symbol)
+		}
+	}
+	return stale
+}
+
+// Symbols возвращает список собираемых символов
+func (c *Collector) Symbols() []string {
+	return c.config.Symbols
+}
+
+func (c *Collector) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect выполняет один раунд сбора, ограничивая число одновременных запросов
+func (c *Collector) collect(ctx context.Context) {
+	semaphore := make(chan struct{}, c.config.Concurrency)
+	var wg sync.WaitGroup
+
+	for _, symbol := range c.config.Symbols {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			if !c.sleepJitter(ctx) {
+				return
+			}
+			c.collectSymbol(ctx, symbol)
+		}(symbol)
+	}
+
+	wg.Wait()
+}
+
+func (c *Collector) collectSymbol(ctx context.Context, symbol string) {
+	ctx, span := c.tracer.Start(ctx, "Collector.CollectSymbol",
+		trace.WithAttributes(attribute.String("symbol", symbol)))
+	defer span.End()
+
+	ask, bid, timestamp, err := c.exchange.GetOrderBook(ctx, symbol)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		c.logger.Warn("Collector failed to fetch rate", zap.Error(err), zap.String("symbol", symbol))
+		span.SetStatus(codes.Error, "Failed to fetch rate")
+		span.RecordError(err)
+		telemetry.CollectorFetchCounter.WithLabelValues(symbol, "fetch_error").Inc()
+		return
+	}
+
+	rate := model.Rate{
+		Symbol:    symbol,
+		Ask:       ask,
+		Bid:       bid,
+		Timestamp: timestamp,
+		CreatedAt: time.Now(),
+	}
+	if err := c.repo.SaveRate(ctx, rate); err != nil {
+		c.logger.Warn("Collector failed to save rate", zap.Error(err), zap.String("symbol", symbol))
+		span.SetStatus(codes.Error, "Failed to save rate")
+		span.RecordError(err)
+		telemetry.CollectorFetchCounter.WithLabelValues(symbol, "save_error").Inc()
+		return
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	c.lastSuccess[symbol] = now
+	c.mu.Unlock()
+
+	telemetry.CollectorFetchCounter.WithLabelValues(symbol, "success").Inc()
+	telemetry.CollectorLastSuccess.WithLabelValues(symbol).Set(float64(now.Unix()))
+	span.SetStatus(codes.Ok, "Rate collected")
+}
+
+// sleepJitter ждет случайное время до Jitter. Возвращает false, если контекст отменен.
+func (c *Collector) sleepJitter(ctx context.Context) bool {
+	if c.config.Jitter <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(c.config.Jitter))))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,257 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+)
+
+// MockExchange мок для биржи
+type MockExchange struct {
+	mock.Mock
+}
+
+func (m *MockExchange) Name() string {
+	return "mock"
+}
+
+func (m *MockExchange) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(float64), args.Get(1).(float64), args.Get(2).(time.Time), args.Error(3)
+}
+
+func (m *MockExchange) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	args := m.Called(ctx, symbol, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderBook), args.Error(1)
+}
+
+// MockRateRepository мок для репозитория
+type MockRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateRepository) SaveRate(ctx context.Context, rate model.Rate) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+func (m *MockRateRepository) SaveRates(ctx context.Context, rates []model.Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
+func (m *MockRateRepository) GetLatestRate(ctx context.Context, symbol string) (*model.Rate, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Rate), args.Error(1)
+}
+
+func (m *MockRateRepository) GetRateHistory(ctx context.Context, query repository.HistoryQuery) ([]model.Rate, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.Rate), args.Error(1)
+}
+
+func (m *MockRateRepository) GetCandles(ctx context.Context, query repository.CandleQuery) ([]model.Candle, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).([]model.Candle), args.Error(1)
+}
+
+func (m *MockRateRepository) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestCollector_CollectSavesRates(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockRepo := new(MockRateRepository)
+	timestamp := time.Now()
+
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, timestamp, nil)
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-USDT").Return(0.0, 0.0, time.Time{}, errors.New("exchange error"))
+	mockRepo.On("SaveRate", mock.Anything, mock.MatchedBy(func(rate model.Rate) bool {
+		return rate.Symbol == "BTC-USDT" && rate.Ask == 101.0 && rate.Bid == 100.0 && rate.Timestamp.Equal(timestamp)
+	})).Return(nil)
+
+	collector, err := NewCollector(zap.NewNop(), mockExchange, mockRepo, Config{
+		Symbols:     []string{"BTC-USDT", "ETH-USDT"},
+		Interval:    time.Minute,
+		Concurrency: 2,
+	})
+	require.NoError(t, err)
+
+	// Act
+	collector.collect(context.Background())
+
+	// Assert
+	mockExchange.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+
+	_, ok := collector.LastSuccess("BTC-USDT")
+	assert.True(t, ok)
+	_, ok = collector.LastSuccess("ETH-USDT")
+	assert.False(t, ok)
+}
+
+func TestCollector_StaleSymbols(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockRepo := new(MockRateRepository)
+
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, time.Now(), nil)
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-USDT").Return(0.0, 0.0, time.Time{}, errors.New("exchange error"))
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	collector, err := NewCollector(zap.NewNop(), mockExchange, mockRepo, Config{
+		Symbols:  []string{"BTC-USDT", "ETH-USDT"},
+		Interval: time.Minute,
+	})
+	require.NoError(t, err)
+	collector.startedAt = time.Now().Add(-time.Hour)
+
+	// Act
+	collector.collect(context.Background())
+
+	// Assert
+	assert.Equal(t, []string{"ETH-USDT"}, collector.StaleSymbols(time.Minute))
+	// Сразу после старта символ без сохранений еще не считается устаревшим
+	collector.startedAt = time.Now()
+	assert.Empty(t, collector.StaleSymbols(time.Minute))
+}
+
+func TestCollector_SaveError(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockRepo := new(MockRateRepository)
+
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, time.Now(), nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+	collector, err := NewCollector(zap.NewNop(), mockExchange, mockRepo, Config{
+		Symbols:  []string{"BTC-USDT"},
+		Interval: time.Minute,
+	})
+	require.NoError(t, err)
+
+	// Act
+	collector.collect(context.Background())
+
+	// Assert
+	mockRepo.AssertExpectations(t)
+	_, ok := collector.LastSuccess("BTC-USDT")
+	assert.False(t, ok)
+}
+
+func TestCollector_BoundedConcurrency(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockRepo := new(MockRateRepository)
+
+	var inFlight, maxInFlight atomic.Int32
+	mockExchange.On("GetOrderBook", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			n := inFlight.Add(1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			inFlight.Add(-1)
+		}).
+		Return(101.0, 100.0, time.Now(), nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	collector, err := NewCollector(zap.NewNop(), mockExchange, mockRepo, Config{
+		Symbols:     []string{"A-USDT", "B-USDT", "C-USDT", "D-USDT", "E-USDT", "F-USDT"},
+		Interval:    time.Minute,
+		Concurrency: 2,
+	})
+	require.NoError(t, err)
+
+	// Act
+	collector.collect(context.Background())
+
+	// Assert
+	mockExchange.AssertNumberOfCalls(t, "GetOrderBook", 6)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestCollector_StartStop(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockRepo := new(MockRateRepository)
+
+	var calls atomic.Int32
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").
+		Run(func(mock.Arguments) { calls.Add(1) }).
+		Return(101.0, 100.0, time.Now(), nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	collector, err := NewCollector(zap.NewNop(), mockExchange, mockRepo, Config{
+		Symbols:  []string{"BTC-USDT"},
+		Interval: 10 * time.Millisecond,
+		Jitter:   time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	// Act
+	collector.Start(context.Background())
+	assert.Eventually(t, func() bool {
+		return calls.Load() >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		collector.Stop()
+		close(stopped)
+	}()
+
+	// Assert
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("collector did not stop")
+	}
+}
+
+func TestNewCollector_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		// Act
+		collector, err := NewCollector(zap.NewNop(), new(MockExchange), new(MockRateRepository), Config{
+			Symbols:  []string{"BTC-USDT"},
+			Interval: interval,
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, ErrInvalidInterval)
+		assert.Nil(t, collector)
+	}
+}
+
+func TestNewCollector_NormalizesSymbols(t *testing.T) {
+	// Act
+	collector, err := NewCollector(zap.NewNop(), new(MockExchange), new(MockRateRepository), Config{
+		Symbols:  []string{"BTC-USDT", " ETH-USDT", "", "  ", "BTC-USDT "},
+		Interval: time.Minute,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC-USDT", "ETH-USDT"}, collector.Symbols())
+}
@@ -0,0 +1,324 @@
+// Package debug обслуживает служебный HTTP-сервер для диагностики: pprof,
+// уровень логирования, действующую конфигурацию, сведения о сборке и
+// состояние кэшей и фонового сбора
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	runtimedebug "runtime/debug"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
+)
+
+// CacheInspector отдает содержимое кэшей сервиса курсов
+type CacheInspector interface {
+	CacheSnapshot() service.CacheSnapshot
+}
+
+// CollectorInspector отдает состояние фонового сбора курсов
+type CollectorInspector interface {
+	Symbols() []string
+	LastSuccess(symbol string) (time.Time, bool)
+}
+
+// Config содержит настройки служебного сервера
+type Config struct {
+	Addr string
+	// LogLevelWritable разрешает менять уровень логирования через PUT и POST
+	// /debug/loglevel; без него уровень доступен только для чтения
+	LogLevelWritable bool
+	// Settings - действующая конфигурация сервиса со скрытыми секретами
+	Settings map[string]interface{}
+
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+}
+
+// Option настраивает служебный сервер
+type Option func(*Server)
+
+// WithCache включает /debug/cache
+func WithCache(cache CacheInspector) Option {
+	return func(s *Server) {
+		s.cache = cache
+	}
+}
+
+// WithCollector включает /debug/collector
+func WithCollector(collector CollectorInspector) Option {
+	return func(s *Server) {
+		s.collector = collector
+	}
+}
+
+// Server - служебный HTTP-сервер. Он не требует аутентификации, поэтому его
+// адрес должен быть доступен только из внутренней сети.
+type Server struct {
+	config    Config
+	logger    *zap.Logger
+	cache     CacheInspector
+	collector CollectorInspector
+	startedAt time.Time
+	server    *http.Server
+}
+
+// NewServer создает служебный сервер
+func NewServer(logger *zap.Logger, config Config, opts ...Option) *Server {
+	s := &Server{
+		config:    config,
+		logger:    logger,
+		startedAt: time.Now(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/loglevel", s.handleLogLevel)
+	mux.HandleFunc("/debug/config", s.handleConfig)
+	mux.HandleFunc("/debug/buildinfo", s.handleBuildInfo)
+	mux.HandleFunc("/debug/cache", s.handleCache)
+	mux.HandleFunc("/debug/collector", s.handleCollector)
+
+	s.server = &http.Server{
+		Addr:              config.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       30 * time.Second,
+		// WriteTimeout не задается: профиль CPU и трасса пишутся дольше любого таймаута
+	}
+	return s
+}
+
+// Start запускает служебный сервер в отдельной горутине
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("Starting debug HTTP server", zap.String("addr", s.config.Addr))
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Failed to start debug HTTP server", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown останавливает служебный сервер
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+type logLevelRequest struct {
+	Level     string `json:"level"`
+	Component string `json:"component"`
+}
+
+type logLevelResponse struct {
+	Level string `json:"level"`
+	// Components - уровни компонентов, заданные отдельно от общего
+	Components map[string]string `json:"components"`
+}
+
+// handleLogLevel возвращает (GET) или, если это разрешено в Config.LogLevelWritable,
+// меняет (PUT, POST) уровень логирования.
+// Уровень и компонент передаются параметрами level и component или телом
+// {"level": "WARN", "component": "kucoin"}. Без компонента меняется общий уровень,
+// пустой уровень компонента возвращает его к общему.
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		if !s.config.LogLevelWritable {
+			http.Error(w, "changing the log level is disabled", http.StatusForbidden)
+			return
+		}
+
+		request := logLevelRequest{
+			Level:     r.URL.Query().Get("level"),
+			Component: r.URL.Query().Get("component"),
+		}
+		if !r.URL.Query().Has("level") {
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, "level is required", http.StatusBadRequest)
+				return
+			}
+		}
+
+		var err error
+		if request.Component != "" {
+			err = logger.SetComponentLevel(request.Component, request.Level)
+		} else {
+			err = logger.SetLevel(request.Level)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.logger.Warn("Log level changed",
+			zap.String("component", request.Component),
+			zap.String("level", request.Level),
+			zap.String("remote_addr", r.RemoteAddr))
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodPost)
+		return
+	}
+
+	writeJSON(w, logLevelResponse{Level: logger.CurrentLevel(), Components: logger.ComponentLevels()})
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	writeJSON(w, s.config.Settings)
+}
+
+type buildInfoResponse struct {
+	Service     string            `json:"service"`
+	Version     string            `json:"version"`
+	Environment string            `json:"environment"`
+	GoVersion   string            `json:"go_version"`
+	Module      string            `json:"module,omitempty"`
+	VCS         map[string]string `json:"vcs,omitempty"`
+	StartedAt   time.Time         `json:"started_at"`
+	Uptime      string            `json:"uptime"`
+	Goroutines  int               `json:"goroutines"`
+}
+
+func (s *Server) handleBuildInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	response := buildInfoResponse{
+		Service:     s.config.ServiceName,
+		Version:     s.config.ServiceVersion,
+		Environment: s.config.Environment,
+		GoVersion:   runtime.Version(),
+		StartedAt:   s.startedAt,
+		Uptime:      time.Since(s.startedAt).Round(time.Second).String(),
+		Goroutines:  runtime.NumGoroutine(),
+	}
+	if info, ok := runtimedebug.ReadBuildInfo(); ok {
+		response.Module = info.Main.Path
+		for _, setting := range info.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") {
+				if response.VCS == nil {
+					response.VCS = make(map[string]string)
+				}
+				response.VCS[strings.TrimPrefix(setting.Key, "vcs.")] = setting.Value
+			}
+		}
+	}
+	writeJSON(w, response)
+}
+
+type cacheEntryResponse struct {
+	Symbol    string    `json:"symbol"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Age       string    `json:"age"`
+	Fresh     bool      `json:"fresh"`
+}
+
+type cacheResponse struct {
+	MaxAge        string               `json:"max_age"`
+	Quotes        []cacheEntryResponse `json:"quotes"`
+	OrderBooks    []cacheEntryResponse `json:"order_books"`
+	Subscriptions map[string]int       `json:"subscriptions"`
+}
+
+func (s *Server) handleCache(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if s.cache == nil {
+		http.Error(w, "rate service is not started", http.StatusServiceUnavailable)
+		return
+	}
+
+	snapshot := s.cache.CacheSnapshot()
+	now := time.Now()
+	writeJSON(w, cacheResponse{
+		MaxAge:        snapshot.MaxAge.String(),
+		Quotes:        cacheEntries(snapshot.Quotes, now),
+		OrderBooks:    cacheEntries(snapshot.OrderBooks, now),
+		Subscriptions: snapshot.Subscriptions,
+	})
+}
+
+func cacheEntries(entries []service.CacheEntry, now time.Time) []cacheEntryResponse {
+	result := make([]cacheEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, cacheEntryResponse{
+			Symbol:    entry.Symbol,
+			FetchedAt: entry.FetchedAt,
+			Age:       now.Sub(entry.FetchedAt).Round(time.Millisecond).String(),
+			Fresh:     entry.Fresh,
+		})
+	}
+	return result
+}
+
+type collectorSymbolResponse struct {
+	Symbol string `json:"symbol"`
+	// LastSuccess и Age пустые, если курс символа еще ни разу не сохранен
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Age         string     `json:"age,omitempty"`
+}
+
+type collectorResponse struct {
+	Enabled bool                      `json:"enabled"`
+	Symbols []collectorSymbolResponse `json:"symbols"`
+}
+
+func (s *Server) handleCollector(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if s.collector == nil {
+		writeJSON(w, collectorResponse{Symbols: []collectorSymbolResponse{}})
+		return
+	}
+
+	now := time.Now()
+	symbols := s.collector.Symbols()
+	response := collectorResponse{Enabled: true, Symbols: make([]collectorSymbolResponse, 0, len(symbols))}
+	for _, symbol := range symbols {
+		item := collectorSymbolResponse{Symbol: symbol}
+		if last, ok := s.collector.LastSuccess(symbol); ok {
+			item.LastSuccess = &last
+			item.Age = now.Sub(last).Round(time.Millisecond).String()
+		}
+		response.Symbols = append(response.Symbols, item)
+	}
+	writeJSON(w, response)
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
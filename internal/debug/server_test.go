@@ -0,0 +1,150 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
+)
+
+type fakeCache struct {
+	snapshot service.CacheSnapshot
+}
+
+func (f *fakeCache) CacheSnapshot() service.CacheSnapshot {
+	return f.snapshot
+}
+
+type fakeCollector struct {
+	symbols     []string
+	lastSuccess map[string]time.Time
+}
+
+func (f *fakeCollector) Symbols() []string {
+	return f.symbols
+}
+
+func (f *fakeCollector) LastSuccess(symbol string) (time.Time, bool) {
+	t, ok := f.lastSuccess[symbol]
+	return t, ok
+}
+
+func serve(t *testing.T, s *Server, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func TestServer_LogLevel(t *testing.T) {
+	// Arrange
+	logger.BuildLogger(logger.LevelInfo)
+	require.NoError(t, logger.SetLevel(logger.LevelInfo))
+	t.Cleanup(func() {
+		_ = logger.SetLevel(logger.LevelInfo)
+		_ = logger.SetComponentLevels("")
+	})
+	s := NewServer(zap.NewNop(), Config{LogLevelWritable: true})
+
+	// Act
+	changed := serve(t, s, http.MethodPut, "/debug/loglevel", `{"level": "warn"}`)
+	component := serve(t, s, http.MethodPut, "/debug/loglevel?component=kucoin&level=debug", "")
+	current := serve(t, s, http.MethodGet, "/debug/loglevel", "")
+	invalid := serve(t, s, http.MethodPut, "/debug/loglevel?level=verbose", "")
+
+	// Assert
+	require.Equal(t, http.StatusOK, changed.Code, changed.Body.String())
+	require.Equal(t, http.StatusOK, component.Code, component.Body.String())
+	assert.JSONEq(t, `{"level": "warn", "components": {"kucoin": "DEBUG"}}`, current.Body.String())
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	assert.Equal(t, "warn", logger.CurrentLevel())
+}
+
+func TestServer_LogLevelReadOnlyByDefault(t *testing.T) {
+	// Arrange
+	logger.BuildLogger(logger.LevelInfo)
+	require.NoError(t, logger.SetLevel(logger.LevelInfo))
+	s := NewServer(zap.NewNop(), Config{})
+
+	// Act
+	changed := serve(t, s, http.MethodPut, "/debug/loglevel?level=debug", "")
+	current := serve(t, s, http.MethodGet, "/debug/loglevel", "")
+
+	// Assert
+	assert.Equal(t, http.StatusForbidden, changed.Code)
+	assert.Equal(t, http.StatusOK, current.Code)
+	assert.Equal(t, "info", logger.CurrentLevel())
+}
+
+func TestServer_Config(t *testing.T) {
+	s := NewServer(zap.NewNop(), Config{Settings: map[string]interface{}{"DB_PASSWORD": "[REDACTED]"}})
+
+	rec := serve(t, s, http.MethodGet, "/debug/config", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"DB_PASSWORD": "[REDACTED]"}`, rec.Body.String())
+}
+
+func TestServer_Cache(t *testing.T) {
+	// Arrange
+	fetchedAt := time.Now().Add(-500 * time.Millisecond)
+	s := NewServer(zap.NewNop(), Config{}, WithCache(&fakeCache{snapshot: service.CacheSnapshot{
+		MaxAge:        time.Second,
+		Quotes:        []service.CacheEntry{{Symbol: "BTC-USDT", FetchedAt: fetchedAt, Fresh: true}},
+		Subscriptions: map[string]int{"ETH-USDT": 2},
+	}}))
+
+	// Act
+	rec := serve(t, s, http.MethodGet, "/debug/cache", "")
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body cacheResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "1s", body.MaxAge)
+	require.Len(t, body.Quotes, 1)
+	assert.Equal(t, "BTC-USDT", body.Quotes[0].Symbol)
+	assert.True(t, body.Quotes[0].Fresh)
+	assert.Empty(t, body.OrderBooks)
+	assert.Equal(t, map[string]int{"ETH-USDT": 2}, body.Subscriptions)
+}
+
+func TestServer_Collector(t *testing.T) {
+	// Arrange
+	lastSuccess := time.Now().Add(-time.Minute).UTC()
+	s := NewServer(zap.NewNop(), Config{}, WithCollector(&fakeCollector{
+		symbols:     []string{"BTC-USDT", "ETH-USDT"},
+		lastSuccess: map[string]time.Time{"BTC-USDT": lastSuccess},
+	}))
+
+	// Act
+	rec := serve(t, s, http.MethodGet, "/debug/collector", "")
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body collectorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.True(t, body.Enabled)
+	require.Len(t, body.Symbols, 2)
+	require.NotNil(t, body.Symbols[0].LastSuccess)
+	assert.True(t, lastSuccess.Equal(*body.Symbols[0].LastSuccess))
+	assert.Nil(t, body.Symbols[1].LastSuccess)
+}
+
+func TestServer_WithoutInspectors(t *testing.T) {
+	s := NewServer(zap.NewNop(), Config{})
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve(t, s, http.MethodGet, "/debug/cache", "").Code)
+	assert.JSONEq(t, `{"enabled": false, "symbols": []}`, serve(t, s, http.MethodGet, "/debug/collector", "").Body.String())
+	assert.Equal(t, http.StatusOK, serve(t, s, http.MethodGet, "/debug/pprof/", "").Code)
+	assert.Equal(t, http.StatusOK, serve(t, s, http.MethodGet, "/debug/buildinfo", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(t, s, http.MethodDelete, "/debug/config", "").Code)
+}
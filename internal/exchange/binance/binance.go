@@ -0,0 +1,235 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+type BinanceClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+	tracer     trace.Tracer
+}
+
+type OrderBookResponse struct {
+	LastUpdateID int64      `json:"lastUpdateId"`
+	Bids         [][]string `json:"bids"`
+	Asks         [][]string `json:"asks"`
+}
+
+func NewBinanceClient(baseURL string, logger *zap.Logger) *BinanceClient {
+	return &BinanceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+		tracer: otel.Tracer("binance-client"),
+	}
+}
+
+func (c *BinanceClient) Name() string {
+	return "binance"
+}
+
+// ToExchangeSymbol переводит символ BASE-QUOTE в формат Binance (BTC-USDT -> BTCUSDT)
+func ToExchangeSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "-", ""))
+}
+
+func (c *BinanceClient) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	ctx, span := c.tracer.Start(ctx, "Binance.GetOrderBook",
+		trace.WithAttributes(attribute.String("symbol", symbol)))
+	defer span.End()
+
+	response, err := c.requestOrderBook(ctx, span, symbol, 20)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+
+	// Уровень может прийти без полей, поэтому разбираем его с проверкой формата
+	asks, err := exchange.ParseLevels(response.Asks, 1)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse ask price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse ask price: %w", err))
+	}
+	askPrice := asks[0].Price
+
+	bids, err := exchange.ParseLevels(response.Bids, 1)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse bid price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse bid price: %w", err))
+	}
+	bidPrice := bids[0].Price
+
+	// Стакан Binance не содержит времени, поэтому используем время получения ответа
+	timestamp := time.Now().UTC()
+
+	c.logger.Debug("Successfully received order book data",
+		zap.String("symbol", symbol),
+		zap.Float64("ask", askPrice),
+		zap.Float64("bid", bidPrice))
+
+	span.SetAttributes(
+		attribute.Float64("ask", askPrice),
+		attribute.Float64("bid", bidPrice),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book data")
+
+	return askPrice, bidPrice, timestamp, nil
+}
+
+// depthLimits - допустимые значения параметра limit эндпоинта /api/v3/depth
+var depthLimits = []int{5, 10, 20, 50, 100, 500, 1000, 5000}
+
+// GetOrderBookDepth возвращает до depth уровней стакана
+func (c *BinanceClient) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	ctx, span := c.tracer.Start(ctx, "Binance.GetOrderBookDepth",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.Int("depth", depth),
+		))
+	defer span.End()
+
+	// Binance принимает только фиксированные значения limit, берем ближайшее не меньше depth
+	limit := depthLimits[len(depthLimits)-1]
+	for _, l := range depthLimits {
+		if l >= depth {
+			limit = l
+			break
+		}
+	}
+
+	response, err := c.requestOrderBook(ctx, span, symbol, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	asks, err := exchange.ParseLevels(response.Asks, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse asks")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse asks: %w", err))
+	}
+
+	bids, err := exchange.ParseLevels(response.Bids, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse bids")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse bids: %w", err))
+	}
+
+	span.SetAttributes(
+		attribute.Int("asks", len(asks)),
+		attribute.Int("bids", len(bids)),
+		attribute.Int64("sequence", response.LastUpdateID),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book depth")
+
+	return &model.OrderBook{
+		Symbol:   symbol,
+		Exchange: c.Name(),
+		Sequence: response.LastUpdateID,
+		Asks:     asks,
+		Bids:     bids,
+		// Стакан Binance не содержит времени, поэтому используем время получения ответа
+		Timestamp: time.Now().UTC(),
+	}, nil
+}
+
+// requestOrderBook запрашивает limit уровней стакана и проверяет, что обе его стороны не пустые
+func (c *BinanceClient) requestOrderBook(ctx context.Context, span trace.Span, symbol string, limit int) (*OrderBookResponse, error) {
+	query := url.Values{}
+	query.Set("symbol", ToExchangeSymbol(symbol))
+	query.Set("limit", strconv.Itoa(limit))
+	requestURL := fmt.Sprintf("%s/api/v3/depth?%s", c.baseURL, query.Encode())
+
+	c.logger.Debug("Requesting order book from Binance",
+		zap.String("url", requestURL),
+		zap.String("symbol", symbol))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", requestURL))
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return nil, exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := c.statusError(resp)
+		c.logger.Error("Unexpected status code",
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("url", requestURL))
+
+		span.SetStatus(codes.Error, statusErr.Error())
+		return nil, statusErr
+	}
+
+	var response OrderBookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.logger.Error("Failed to decode response", zap.Error(err))
+		span.SetStatus(codes.Error, "Failed to decode response")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
+	}
+
+	if len(response.Asks) == 0 || len(response.Bids) == 0 {
+		errMsg := "empty order book data"
+		span.SetStatus(codes.Error, errMsg)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("%s", errMsg))
+	}
+
+	return &response, nil
+}
+
+// codeInvalidSymbol - код ошибки Binance API для неизвестного символа
+const codeInvalidSymbol = -1121
+
+// statusError классифицирует неуспешный ответ Binance: неизвестный символ
+// приходит с HTTP 400 и кодом -1121 в теле
+func (c *BinanceClient) statusError(resp *http.Response) *exchange.Error {
+	statusErr := exchange.StatusError(c.Name(), resp)
+	if resp.StatusCode != http.StatusBadRequest {
+		return statusErr
+	}
+
+	var body struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Code == codeInvalidSymbol {
+		statusErr.Kind = exchange.ErrSymbolNotFound
+		statusErr.Err = fmt.Errorf("unexpected status code: %d: binance error %d: %s", resp.StatusCode, body.Code, body.Msg)
+	}
+	return statusErr
+}
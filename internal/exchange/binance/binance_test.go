@@ -0,0 +1,144 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// Вспомогательная функция для создания тестового сервера и клиента
+func setupTestServerAndClient(handler func(w http.ResponseWriter, r *http.Request)) (*BinanceClient, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	client := NewBinanceClient(server.URL, zap.NewNop())
+	return client, server
+}
+
+func TestToExchangeSymbol(t *testing.T) {
+	assert.Equal(t, "BTCUSDT", ToExchangeSymbol("BTC-USDT"))
+	assert.Equal(t, "ETHBTC", ToExchangeSymbol("eth-btc"))
+}
+
+func TestGetOrderBook_Success(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v3/depth", r.URL.Path)
+		assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"lastUpdateId": 1027024,
+			"bids": [["40000.0", "1.0"], ["39999.0", "0.5"]],
+			"asks": [["40001.0", "0.8"], ["40002.0", "0.3"]]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	ask, bid, timestamp, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, 40001.0, ask)
+	assert.Equal(t, 40000.0, bid)
+	assert.False(t, timestamp.IsZero())
+}
+
+func TestGetOrderBook_EmptyData(t *testing.T) {
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"lastUpdateId": 1, "bids": [], "asks": []}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "empty order book data")
+}
+
+func TestGetOrderBook_MalformedLevel(t *testing.T) {
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"lastUpdateId": 1, "bids": [[]], "asks": [[]]}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse ask price")
+}
+
+func TestGetOrderBook_ServerError(t *testing.T) {
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := w.Write([]byte(`{"code": -1121, "msg": "Invalid symbol."}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "FOO-BAR")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 400")
+	assert.ErrorIs(t, err, exchange.ErrSymbolNotFound)
+}
+
+func TestGetOrderBookDepth(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		// Глубина 30 округляется до ближайшего допустимого limit
+		assert.Equal(t, "50", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"lastUpdateId": 1027024,
+			"bids": [["40000.0", "1.0"], ["39999.0", "0.5"]],
+			"asks": [["40001.0", "0.8"], ["40002.0", "0.3"]]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	book, err := client.GetOrderBookDepth(context.Background(), "BTC-USDT", 30)
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, "binance", book.Exchange)
+	assert.Equal(t, int64(1027024), book.Sequence)
+	assert.Equal(t, []model.PriceLevel{{Price: 40001, Size: 0.8}, {Price: 40002, Size: 0.3}}, book.Asks)
+	assert.Equal(t, []model.PriceLevel{{Price: 40000, Size: 1}, {Price: 39999, Size: 0.5}}, book.Bids)
+}
+
+func TestListSymbols(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"symbols": [
+				{"symbol": "ETHBTC", "status": "TRADING", "baseAsset": "ETH", "quoteAsset": "BTC"},
+				{"symbol": "OLDBTC", "status": "BREAK", "baseAsset": "OLD", "quoteAsset": "BTC"}
+			]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	symbols, err := client.ListSymbols(context.Background())
+
+	// Проверяем результаты: символы переведены в формат BASE-QUOTE
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Symbol{{Symbol: "ETH-BTC", Base: "ETH", Quote: "BTC"}}, symbols)
+}
@@ -0,0 +1,45 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+)
+
+// Ping проверяет доступность API Binance запросом проверки соединения
+func (c *BinanceClient) Ping(ctx context.Context) error {
+	ctx, span := c.tracer.Start(ctx, "Binance.Ping")
+	defer span.End()
+
+	url := fmt.Sprintf("%s/api/v3/ping", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := c.statusError(resp)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return statusErr
+	}
+
+	span.SetStatus(codes.Ok, "Binance is reachable")
+	return nil
+}
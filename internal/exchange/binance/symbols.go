@@ -0,0 +1,83 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// statusTrading - статус пары, по которой разрешена торговля
+const statusTrading = "TRADING"
+
+type ExchangeInfoResponse struct {
+	Symbols []struct {
+		Symbol     string `json:"symbol"`
+		Status     string `json:"status"`
+		BaseAsset  string `json:"baseAsset"`
+		QuoteAsset string `json:"quoteAsset"`
+	} `json:"symbols"`
+}
+
+// ListSymbols возвращает пары, по которым на Binance разрешена торговля
+func (c *BinanceClient) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	ctx, span := c.tracer.Start(ctx, "Binance.ListSymbols")
+	defer span.End()
+
+	requestURL := fmt.Sprintf("%s/api/v3/exchangeInfo", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", requestURL))
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return nil, exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := c.statusError(resp)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return nil, statusErr
+	}
+
+	var response ExchangeInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		span.SetStatus(codes.Error, "Failed to decode response")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
+	}
+
+	symbols := make([]model.Symbol, 0, len(response.Symbols))
+	for _, item := range response.Symbols {
+		if item.Status != statusTrading {
+			continue
+		}
+		// Binance пишет пары слитно (ETHBTC), переводим в единый формат
+		symbols = append(symbols, model.Symbol{
+			Symbol: item.BaseAsset + "-" + item.QuoteAsset,
+			Base:   item.BaseAsset,
+			Quote:  item.QuoteAsset,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("count", len(symbols)))
+	span.SetStatus(codes.Ok, "Symbols received")
+
+	return symbols, nil
+}
@@ -0,0 +1,170 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/telemetry"
+)
+
+// ErrCircuitOpen - запрос не отправлен, потому что биржа отключена автоматом
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState - состояние автомата отключения
+type BreakerState int
+
+const (
+	// BreakerClosed - запросы проходят
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen - проходит один пробный запрос, по нему решается, закрыть ли автомат
+	BreakerHalfOpen
+	// BreakerOpen - запросы отклоняются без обращения к бирже
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig содержит настройки автомата отключения
+type BreakerConfig struct {
+	// FailureThreshold - число отказов подряд, после которого автомат размыкается
+	FailureThreshold int
+	// OpenTimeout - время, через которое разомкнутый автомат пропускает пробный запрос
+	OpenTimeout time.Duration
+}
+
+// CircuitBreaker прекращает запросы к бирже после серии отказов подряд и
+// через OpenTimeout пропускает один пробный запрос. Отказом считаются только
+// недоступность и таймаут: ответ с ошибкой означает, что биржа работает.
+type CircuitBreaker struct {
+	exchange string
+	config   BreakerConfig
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// NewCircuitBreaker создает замкнутый автомат для биржи exchange
+func NewCircuitBreaker(exchange string, config BreakerConfig, logger *zap.Logger) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+
+	telemetry.ExchangeCircuitState.WithLabelValues(exchange).Set(float64(BreakerClosed))
+	return &CircuitBreaker{
+		exchange: exchange,
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Allow разрешает запрос или возвращает ErrCircuitOpen вида ErrUnavailable
+// с RetryAfter до пробного запроса. Каждый разрешенный запрос завершается вызовом Record.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.setState(BreakerHalfOpen)
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		return b.openError()
+	case BreakerHalfOpen:
+		if b.trial {
+			return b.openError()
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// Record учитывает результат запроса, разрешенного Allow. ctx - контекст
+// вызывающего: если он отменен или истек, ошибка запроса не учитывается.
+func (b *CircuitBreaker) Record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Отмена запроса клиентом или его собственный дедлайн ничего не говорят о бирже.
+	// Таймаут самого HTTP-клиента и сетевые таймауты учитываются как отказы.
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		b.trial = false
+		return
+	}
+
+	if !errors.Is(err, ErrUnavailable) && !errors.Is(err, ErrTimeout) {
+		b.failures = 0
+		b.trial = false
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.trial = false
+		b.openedAt = b.now()
+		if b.state != BreakerOpen {
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+// State возвращает текущее состояние автомата
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Err возвращает ErrCircuitOpen, пока автомат разомкнут, не расходуя пробный запрос
+func (b *CircuitBreaker) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return nil
+	}
+	return b.openError()
+}
+
+func (b *CircuitBreaker) openError() error {
+	err := NewError(b.exchange, ErrUnavailable, ErrCircuitOpen)
+	if b.state == BreakerOpen {
+		err.RetryAfter = b.config.OpenTimeout - b.now().Sub(b.openedAt)
+	}
+	return err
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.logger.Warn("Exchange circuit breaker state changed",
+		zap.String("exchange", b.exchange),
+		zap.Stringer("from", b.state),
+		zap.Stringer("to", state),
+		zap.Int("failures", b.failures))
+
+	b.state = state
+	telemetry.ExchangeCircuitState.WithLabelValues(b.exchange).Set(float64(state))
+}
@@ -0,0 +1,120 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestBreaker(threshold int, openTimeout time.Duration) (*CircuitBreaker, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker("kucoin", BreakerConfig{FailureThreshold: threshold, OpenTimeout: openTimeout}, zap.NewNop())
+	breaker.now = func() time.Time { return now }
+	return breaker, &now
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	breaker, _ := newTestBreaker(3, time.Minute)
+	unavailable := NewError("kucoin", ErrUnavailable, nil)
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), unavailable)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// Успешный ответ сбрасывает счетчик отказов
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(context.Background(), nil)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), unavailable)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(context.Background(), NewError("kucoin", ErrTimeout, nil))
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	err := breaker.Allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	retryAfter, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, retryAfter)
+}
+
+func TestCircuitBreaker_ErrorResponsesAreNotFailures(t *testing.T) {
+	breaker, _ := newTestBreaker(1, time.Minute)
+
+	for _, err := range []error{
+		NewError("kucoin", ErrSymbolNotFound, nil),
+		NewError("kucoin", ErrBadResponse, nil),
+		NewError("kucoin", ErrRateLimited, nil),
+		context.Canceled,
+	} {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), err)
+	}
+
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreaker_CallerDeadlineIsNotFailure(t *testing.T) {
+	breaker, _ := newTestBreaker(2, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	// Биржа не успела ответить до дедлайна клиента: RequestError возвращает ErrTimeout
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(ctx, NewError("kucoin", ErrTimeout, context.DeadlineExceeded))
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// Таймаут самого HTTP-клиента при живом контексте вызывающего - отказ биржи
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), NewError("kucoin", ErrTimeout, nil))
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	t.Run("successful trial closes", func(t *testing.T) {
+		breaker, now := newTestBreaker(1, time.Minute)
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), NewError("kucoin", ErrUnavailable, nil))
+		assert.Error(t, breaker.Err())
+
+		*now = now.Add(time.Minute)
+
+		// Пропускается только один пробный запрос
+		assert.NoError(t, breaker.Allow())
+		assert.Equal(t, BreakerHalfOpen, breaker.State())
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+		breaker.Record(context.Background(), nil)
+		assert.Equal(t, BreakerClosed, breaker.State())
+		assert.NoError(t, breaker.Err())
+	})
+
+	t.Run("failed trial reopens", func(t *testing.T) {
+		breaker, now := newTestBreaker(3, time.Minute)
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, breaker.Allow())
+			breaker.Record(context.Background(), NewError("kucoin", ErrUnavailable, nil))
+		}
+
+		*now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(context.Background(), NewError("kucoin", ErrTimeout, nil))
+
+		assert.Equal(t, BreakerOpen, breaker.State())
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+	})
+}
@@ -0,0 +1,147 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Виды ошибок запросов к бирже. Проверяются через errors.Is, в том числе
+// сквозь ошибки Failover, объединяющие ответы нескольких бирж.
+var (
+	ErrSymbolNotFound = errors.New("symbol not found on exchange")
+	ErrUnavailable    = errors.New("exchange unavailable")
+	ErrRateLimited    = errors.New("exchange rate limit exceeded")
+	ErrBadResponse    = errors.New("bad exchange response")
+	ErrTimeout        = errors.New("exchange request timed out")
+)
+
+// Error - ошибка запроса к бирже: вид ошибки Kind и, если биржа его сообщила,
+// время RetryAfter, через которое запрос можно повторить
+type Error struct {
+	Exchange   string
+	Kind       error
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// NewError создает ошибку вида kind
+func NewError(exchange string, kind error, err error) *Error {
+	return &Error{Exchange: exchange, Kind: kind, Err: err}
+}
+
+// RetryAfter возвращает подсказку биржи о времени повторного запроса. Если
+// ошибка объединяет ответы нескольких бирж, берется наибольшая подсказка.
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryAfter time.Duration
+	walkErrors(err, func(err error) {
+		if exchangeErr, ok := err.(*Error); ok && exchangeErr.RetryAfter > retryAfter {
+			retryAfter = exchangeErr.RetryAfter
+		}
+	})
+	return retryAfter, retryAfter > 0
+}
+
+// Find возвращает первую в дереве err ошибку биржи вида kind
+func Find(err error, kind error) (*Error, bool) {
+	var found *Error
+	walkErrors(err, func(err error) {
+		if exchangeErr, ok := err.(*Error); ok && found == nil && exchangeErr.Kind == kind {
+			found = exchangeErr
+		}
+	})
+	return found, found != nil
+}
+
+// walkErrors обходит дерево обернутых ошибок
+func walkErrors(err error, visit func(error)) {
+	if err == nil {
+		return
+	}
+	visit(err)
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		walkErrors(wrapped.Unwrap(), visit)
+	case interface{ Unwrap() []error }:
+		for _, e := range wrapped.Unwrap() {
+			walkErrors(e, visit)
+		}
+	}
+}
+
+// RequestError классифицирует ошибку выполнения HTTP-запроса к бирже.
+// Отмена запроса клиентом возвращается как есть.
+func RequestError(exchange string, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewError(exchange, ErrTimeout, fmt.Errorf("failed to make request: %w", err))
+	}
+	return NewError(exchange, ErrUnavailable, fmt.Errorf("failed to make request: %w", err))
+}
+
+// StatusError классифицирует неуспешный HTTP-ответ биржи. Для 429 и 503
+// учитывается заголовок Retry-After.
+func StatusError(exchange string, resp *http.Response) *Error {
+	err := fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+
+	var exchangeErr *Error
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		exchangeErr = NewError(exchange, ErrRateLimited, err)
+	case resp.StatusCode == http.StatusNotFound:
+		exchangeErr = NewError(exchange, ErrSymbolNotFound, err)
+	case resp.StatusCode == http.StatusGatewayTimeout:
+		exchangeErr = NewError(exchange, ErrTimeout, err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		exchangeErr = NewError(exchange, ErrUnavailable, err)
+	default:
+		exchangeErr = NewError(exchange, ErrBadResponse, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		exchangeErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return exchangeErr
+}
+
+// parseRetryAfter разбирает Retry-After в секундах или в виде HTTP-даты
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
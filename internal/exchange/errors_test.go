@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name           string
+		statusCode     int
+		retryAfter     string
+		wantKind       error
+		wantRetryAfter time.Duration
+	}{
+		{name: "rate limited", statusCode: http.StatusTooManyRequests, retryAfter: "2", wantKind: ErrRateLimited, wantRetryAfter: 2 * time.Second},
+		{name: "not found", statusCode: http.StatusNotFound, wantKind: ErrSymbolNotFound},
+		{name: "gateway timeout", statusCode: http.StatusGatewayTimeout, wantKind: ErrTimeout},
+		{name: "unavailable", statusCode: http.StatusServiceUnavailable, retryAfter: "5", wantKind: ErrUnavailable, wantRetryAfter: 5 * time.Second},
+		{name: "server error", statusCode: http.StatusInternalServerError, retryAfter: "5", wantKind: ErrUnavailable},
+		{name: "bad request", statusCode: http.StatusBadRequest, wantKind: ErrBadResponse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := StatusError("kucoin", resp)
+
+			assert.ErrorIs(t, err, tt.wantKind)
+			assert.Equal(t, "kucoin", err.Exchange)
+			assert.Equal(t, tt.wantRetryAfter, err.RetryAfter)
+			assert.Contains(t, err.Error(), fmt.Sprintf("unexpected status code: %d", tt.statusCode))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 7*time.Second, parseRetryAfter("7", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("-1", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+	assert.Zero(t, parseRetryAfter("", now))
+}
+
+func TestRequestError(t *testing.T) {
+	t.Run("deadline", func(t *testing.T) {
+		err := RequestError("okx", fmt.Errorf("get: %w", context.DeadlineExceeded))
+
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("connection error", func(t *testing.T) {
+		err := RequestError("okx", errors.New("connection refused"))
+
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+
+	t.Run("canceled by client", func(t *testing.T) {
+		err := RequestError("okx", context.Canceled)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrUnavailable)
+	})
+}
+
+func TestRetryAfter_ThroughJoin(t *testing.T) {
+	rateLimited := NewError("kucoin", ErrRateLimited, errors.New("429"))
+	rateLimited.RetryAfter = time.Second
+
+	// Failover объединяет ошибки бирж через errors.Join
+	err := errors.Join(NewError("okx", ErrUnavailable, nil), rateLimited)
+
+	retryAfter, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
@@ -0,0 +1,34 @@
+package exchange
+
+import (
+	"context"
+	"time"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// Exchange - источник котировок биржи.
+// Символы передаются в едином формате BASE-QUOTE (например, BTC-USDT),
+// каждая реализация сама переводит их в формат своей биржи.
+type Exchange interface {
+	// Name возвращает идентификатор биржи
+	Name() string
+	// GetOrderBook возвращает лучшие цены ask/bid и время стакана по символу
+	GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error)
+	// GetOrderBookDepth возвращает не больше depth лучших уровней стакана с каждой стороны
+	GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error)
+}
+
+// SymbolLister - биржа, которая умеет отдавать список торгуемых пар.
+// Реализуется опционально, проверяется приведением типа.
+type SymbolLister interface {
+	// ListSymbols возвращает пары, доступные для торговли, в формате BASE-QUOTE
+	ListSymbols(ctx context.Context) ([]model.Symbol, error)
+}
+
+// Pinger - биржа, доступность API которой можно проверить легким запросом.
+// Реализуется опционально, проверяется приведением типа.
+type Pinger interface {
+	// Ping проверяет, что API биржи отвечает
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,114 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// Failover опрашивает биржи по порядку и возвращает ответ первой успешной
+type Failover struct {
+	exchanges []Exchange
+	logger    *zap.Logger
+}
+
+func NewFailover(logger *zap.Logger, exchanges ...Exchange) *Failover {
+	return &Failover{
+		exchanges: exchanges,
+		logger:    logger,
+	}
+}
+
+func (f *Failover) Name() string {
+	names := make([]string, 0, len(f.exchanges))
+	for _, exchange := range f.exchanges {
+		names = append(names, exchange.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (f *Failover) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	if len(f.exchanges) == 0 {
+		return 0, 0, time.Time{}, errors.New("no exchanges configured")
+	}
+
+	errs := make([]error, 0, len(f.exchanges))
+	for _, exchange := range f.exchanges {
+		ask, bid, timestamp, err := exchange.GetOrderBook(ctx, symbol)
+		if err == nil {
+			return ask, bid, timestamp, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", exchange.Name(), err))
+
+		// Отмененный запрос нет смысла повторять на другой бирже
+		if ctx.Err() != nil {
+			break
+		}
+
+		f.logger.Warn("Exchange failed, trying next one",
+			zap.String("exchange", exchange.Name()),
+			zap.String("symbol", symbol),
+			zap.Error(err))
+	}
+
+	return 0, 0, time.Time{}, fmt.Errorf("all exchanges failed: %w", errors.Join(errs...))
+}
+
+func (f *Failover) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	if len(f.exchanges) == 0 {
+		return nil, errors.New("no exchanges configured")
+	}
+
+	errs := make([]error, 0, len(f.exchanges))
+	for _, exchange := range f.exchanges {
+		book, err := exchange.GetOrderBookDepth(ctx, symbol, depth)
+		if err == nil {
+			return book, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", exchange.Name(), err))
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		f.logger.Warn("Exchange failed, trying next one",
+			zap.String("exchange", exchange.Name()),
+			zap.String("symbol", symbol),
+			zap.Error(err))
+	}
+
+	return nil, fmt.Errorf("all exchanges failed: %w", errors.Join(errs...))
+}
+
+// ListSymbols возвращает список пар первой биржи, которая умеет его отдавать
+func (f *Failover) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	errs := make([]error, 0, len(f.exchanges))
+	for _, exchange := range f.exchanges {
+		lister, ok := exchange.(SymbolLister)
+		if !ok {
+			continue
+		}
+
+		symbols, err := lister.ListSymbols(ctx)
+		if err == nil {
+			return symbols, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", exchange.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("no exchange supports symbol listing")
+	}
+	return nil, fmt.Errorf("all exchanges failed: %w", errors.Join(errs...))
+}
@@ -0,0 +1,159 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+type MockExchange struct {
+	mock.Mock
+	name string
+}
+
+func (m *MockExchange) Name() string {
+	return m.name
+}
+
+func (m *MockExchange) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(float64), args.Get(1).(float64), args.Get(2).(time.Time), args.Error(3)
+}
+
+func (m *MockExchange) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	args := m.Called(ctx, symbol, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderBook), args.Error(1)
+}
+
+func TestFailover_Name(t *testing.T) {
+	failover := NewFailover(zap.NewNop(), &MockExchange{name: "kucoin"}, &MockExchange{name: "okx"})
+
+	assert.Equal(t, "kucoin,okx", failover.Name())
+}
+
+func TestFailover_GetOrderBook(t *testing.T) {
+	ctx := context.Background()
+	timestamp := time.Now().UTC()
+
+	t.Run("first exchange succeeds", func(t *testing.T) {
+		first := &MockExchange{name: "kucoin"}
+		second := &MockExchange{name: "okx"}
+		first.On("GetOrderBook", ctx, "BTC-USDT").Return(40001.0, 40000.0, timestamp, nil)
+
+		ask, bid, resultTimestamp, err := NewFailover(zap.NewNop(), first, second).GetOrderBook(ctx, "BTC-USDT")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 40001.0, ask)
+		assert.Equal(t, 40000.0, bid)
+		assert.Equal(t, timestamp, resultTimestamp)
+		second.AssertNotCalled(t, "GetOrderBook")
+	})
+
+	t.Run("falls back to next exchange", func(t *testing.T) {
+		first := &MockExchange{name: "kucoin"}
+		second := &MockExchange{name: "okx"}
+		first.On("GetOrderBook", ctx, "BTC-USDT").Return(0.0, 0.0, time.Time{}, errors.New("kucoin is down"))
+		second.On("GetOrderBook", ctx, "BTC-USDT").Return(40002.0, 40001.0, timestamp, nil)
+
+		ask, bid, _, err := NewFailover(zap.NewNop(), first, second).GetOrderBook(ctx, "BTC-USDT")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 40002.0, ask)
+		assert.Equal(t, 40001.0, bid)
+		first.AssertExpectations(t)
+		second.AssertExpectations(t)
+	})
+
+	t.Run("all exchanges fail", func(t *testing.T) {
+		first := &MockExchange{name: "kucoin"}
+		second := &MockExchange{name: "okx"}
+		kucoinErr := errors.New("kucoin is down")
+		first.On("GetOrderBook", ctx, "BTC-USDT").Return(0.0, 0.0, time.Time{}, kucoinErr)
+		second.On("GetOrderBook", ctx, "BTC-USDT").Return(0.0, 0.0, time.Time{}, errors.New("okx is down"))
+
+		_, _, _, err := NewFailover(zap.NewNop(), first, second).GetOrderBook(ctx, "BTC-USDT")
+
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, kucoinErr)
+		assert.Contains(t, err.Error(), "okx: okx is down")
+	})
+
+	t.Run("canceled context stops failover", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		first := &MockExchange{name: "kucoin"}
+		second := &MockExchange{name: "okx"}
+		first.On("GetOrderBook", canceledCtx, "BTC-USDT").Return(0.0, 0.0, time.Time{}, context.Canceled)
+
+		_, _, _, err := NewFailover(zap.NewNop(), first, second).GetOrderBook(canceledCtx, "BTC-USDT")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		second.AssertNotCalled(t, "GetOrderBook")
+	})
+}
+
+func TestFailover_GetOrderBookDepth(t *testing.T) {
+	ctx := context.Background()
+	book := &model.OrderBook{Symbol: "BTC-USDT", Exchange: "okx", Sequence: 42}
+
+	first := &MockExchange{name: "kucoin"}
+	second := &MockExchange{name: "okx"}
+	first.On("GetOrderBookDepth", ctx, "BTC-USDT", 10).Return(nil, errors.New("kucoin is down"))
+	second.On("GetOrderBookDepth", ctx, "BTC-USDT", 10).Return(book, nil)
+
+	result, err := NewFailover(zap.NewNop(), first, second).GetOrderBookDepth(ctx, "BTC-USDT", 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, book, result)
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
+
+// MockListingExchange - биржа, которая умеет отдавать список пар
+type MockListingExchange struct {
+	MockExchange
+}
+
+func (m *MockListingExchange) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Symbol), args.Error(1)
+}
+
+func TestFailover_ListSymbols(t *testing.T) {
+	ctx := context.Background()
+	symbols := []model.Symbol{{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"}}
+
+	t.Run("skips exchanges without listing and failed ones", func(t *testing.T) {
+		plain := &MockExchange{name: "plain"}
+		failing := &MockListingExchange{MockExchange{name: "kucoin"}}
+		listing := &MockListingExchange{MockExchange{name: "okx"}}
+		failing.On("ListSymbols", ctx).Return(nil, errors.New("kucoin is down"))
+		listing.On("ListSymbols", ctx).Return(symbols, nil)
+
+		result, err := NewFailover(zap.NewNop(), plain, failing, listing).ListSymbols(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, symbols, result)
+		failing.AssertExpectations(t)
+		listing.AssertExpectations(t)
+	})
+
+	t.Run("no exchange supports listing", func(t *testing.T) {
+		_, err := NewFailover(zap.NewNop(), &MockExchange{name: "plain"}).ListSymbols(ctx)
+
+		assert.EqualError(t, err, "no exchange supports symbol listing")
+	})
+}
@@ -13,6 +13,9 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
 )
 
 type KuCoinClient struct {
@@ -20,8 +23,41 @@ type KuCoinClient struct {
 	httpClient *http.Client
 	logger     *zap.Logger
 	tracer     trace.Tracer
+	retry      exchange.RetryPolicy
+	breaker    *exchange.CircuitBreaker
+	limiter    *exchange.RateLimiter
 }
 
+// Option - дополнительная настройка KuCoinClient
+type Option func(*KuCoinClient)
+
+// WithRequestTimeout задает таймаут одной попытки HTTP-запроса
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *KuCoinClient) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetryPolicy включает повтор запросов при временных ошибках KuCoin
+func WithRetryPolicy(policy exchange.RetryPolicy) Option {
+	return func(c *KuCoinClient) {
+		c.retry = policy
+	}
+}
+
+// WithCircuitBreaker прекращает запросы к KuCoin после серии отказов
+func WithCircuitBreaker(config exchange.BreakerConfig) Option {
+	return func(c *KuCoinClient) {
+		c.breaker = exchange.NewCircuitBreaker(c.Name(), config, c.logger)
+	}
+}
+
+const (
+	exchangeName = "kucoin"
+	// codeOK - код успешного ответа KuCoin API
+	codeOK = "200000"
+)
+
 type OrderBookResponse struct {
 	Code string `json:"code"`
 	Data struct {
@@ -32,8 +68,8 @@ type OrderBookResponse struct {
 	}
 }
 
-func NewKucoinClient(baseUrl string, logger *zap.Logger) *KuCoinClient {
-	return &KuCoinClient{
+func NewKucoinClient(baseUrl string, logger *zap.Logger, opts ...Option) *KuCoinClient {
+	c := &KuCoinClient{
 		baseURL: baseUrl,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
@@ -41,6 +77,14 @@ func NewKucoinClient(baseUrl string, logger *zap.Logger) *KuCoinClient {
 		logger: logger,
 		tracer: otel.Tracer("kucoin-client"),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *KuCoinClient) Name() string {
+	return exchangeName
 }
 
 func (c *KuCoinClient) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
@@ -49,9 +93,190 @@ func (c *KuCoinClient) GetOrderBook(ctx context.Context, symbol string) (float64
 		trace.WithAttributes(attribute.String("symbol", symbol)))
 	defer span.End()
 
-	url := fmt.Sprintf("%s/api/v1/market/orderbook/level2_20?symbol=%s", c.baseURL, symbol)
+	response, err := c.requestOrderBook(ctx, span, "level2_20", symbol)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+
+	// Уровень может прийти без полей, поэтому разбираем его с проверкой формата
+	asks, err := exchange.ParseLevels(response.Data.Asks, 1)
+	if err != nil {
+		logger.WithContext(ctx, c.logger).Error("Failed to parse ask price", zap.Error(err))
+		span.SetStatus(codes.Error, "Failed to parse ask price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse ask price: %w", err))
+	}
+	askPrice := asks[0].Price
+
+	bids, err := exchange.ParseLevels(response.Data.Bids, 1)
+	if err != nil {
+		logger.WithContext(ctx, c.logger).Error("Failed to parse bid price", zap.Error(err))
+		span.SetStatus(codes.Error, "Failed to parse bid price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse bid price: %w", err))
+	}
+	bidPrice := bids[0].Price
+
+	// Преобразуем timestamp из миллисекунд в time.Time в UTC
+	timestamp := time.Unix(0, response.Data.Time*int64(time.Millisecond)).UTC()
 
-	c.logger.Debug("Requesting order book from KuCoin",
+	logger.WithContext(ctx, c.logger).Debug("Successfully received order book data",
+		zap.String("symbol", symbol),
+		zap.Float64("ask", askPrice),
+		zap.Float64("bid", bidPrice),
+		zap.Time("timestamp", timestamp))
+
+	// Добавляем результаты в спан
+	span.SetAttributes(
+		attribute.Float64("ask", askPrice),
+		attribute.Float64("bid", bidPrice),
+		attribute.String("timestamp", timestamp.Format(time.RFC3339)),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book data")
+
+	return askPrice, bidPrice, timestamp, nil
+}
+
+// maxDepth - число уровней в самом полном публичном стакане KuCoin (level2_100)
+const maxDepth = 100
+
+// GetOrderBookDepth возвращает до depth уровней стакана. При depth <= 20 используется
+// эндпоинт level2_20, иначе level2_100.
+func (c *KuCoinClient) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	ctx, span := c.tracer.Start(ctx, "KuCoin.GetOrderBookDepth",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.Int("depth", depth),
+		))
+	defer span.End()
+
+	if depth <= 0 || depth > maxDepth {
+		depth = maxDepth
+	}
+
+	endpoint := "level2_20"
+	if depth > 20 {
+		endpoint = "level2_100"
+	}
+
+	response, err := c.requestOrderBook(ctx, span, endpoint, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	asks, err := exchange.ParseLevels(response.Data.Asks, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse asks")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse asks: %w", err))
+	}
+
+	bids, err := exchange.ParseLevels(response.Data.Bids, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse bids")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse bids: %w", err))
+	}
+
+	sequence, err := strconv.ParseInt(response.Data.Sequence, 10, 64)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse sequence")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse sequence: %w", err))
+	}
+
+	span.SetAttributes(
+		attribute.Int("asks", len(asks)),
+		attribute.Int("bids", len(bids)),
+		attribute.Int64("sequence", sequence),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book depth")
+
+	return &model.OrderBook{
+		Symbol:    symbol,
+		Exchange:  c.Name(),
+		Sequence:  sequence,
+		Asks:      asks,
+		Bids:      bids,
+		Timestamp: time.Unix(0, response.Data.Time*int64(time.Millisecond)).UTC(),
+	}, nil
+}
+
+// BreakerState возвращает состояние автомата отключения; без автомата - BreakerClosed
+func (c *KuCoinClient) BreakerState() exchange.BreakerState {
+	if c.breaker == nil {
+		return exchange.BreakerClosed
+	}
+	return c.breaker.State()
+}
+
+// do выполняет идемпотентный запрос весом weight через ограничитель,
+// автомат отключения и с повторами
+func (c *KuCoinClient) do(ctx context.Context, weight int, request func(ctx context.Context) error) error {
+	attempt := 0
+	return c.retry.Do(ctx, func(ctx context.Context) error {
+		attempt++
+		if attempt > 1 {
+			logger.WithContext(ctx, c.logger).Debug("Retrying KuCoin request", zap.Int("attempt", attempt))
+		}
+
+		// Пока автомат разомкнут, бюджет запросов не расходуется
+		if c.breaker != nil {
+			if err := c.breaker.Err(); err != nil {
+				return err
+			}
+		}
+		if err := wait(ctx, c.limiter, weight); err != nil {
+			return err
+		}
+		if c.breaker != nil {
+			if err := c.breaker.Allow(); err != nil {
+				return err
+			}
+		}
+
+		err := request(ctx)
+		if c.breaker != nil {
+			c.breaker.Record(ctx, err)
+		}
+		return err
+	})
+}
+
+// requestOrderBook запрашивает стакан с указанного эндпоинта (level2_20, level2_100),
+// повторяя запрос при временных ошибках
+func (c *KuCoinClient) requestOrderBook(
+	ctx context.Context,
+	span trace.Span,
+	endpoint string,
+	symbol string,
+) (*OrderBookResponse, error) {
+	var response *OrderBookResponse
+	err := c.do(ctx, orderBookWeight(endpoint), func(ctx context.Context) error {
+		var err error
+		response, err = c.fetchOrderBook(ctx, span, endpoint, symbol)
+		return err
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return response, nil
+}
+
+// fetchOrderBook выполняет одну попытку запроса стакана и проверяет,
+// что обе его стороны не пустые
+func (c *KuCoinClient) fetchOrderBook(
+	ctx context.Context,
+	span trace.Span,
+	endpoint string,
+	symbol string,
+) (*OrderBookResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/market/orderbook/%s?symbol=%s", c.baseURL, endpoint, symbol)
+
+	logger.WithContext(ctx, c.logger).Debug("Requesting order book from KuCoin",
 		zap.String("url", url),
 		zap.String("symbol", symbol))
 
@@ -59,26 +284,27 @@ func (c *KuCoinClient) GetOrderBook(ctx context.Context, symbol string) (float64
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		c.logger.Error("Failed to create request", zap.Error(err), zap.String("url", url))
+		logger.WithContext(ctx, c.logger).Error("Failed to create request", zap.Error(err), zap.String("url", url))
 		span.SetStatus(codes.Error, "Failed to create request")
 		span.RecordError(err)
-		return 0, 0, time.Time{}, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Создаем вложенный спан для HTTP запроса
-	ctx, reqSpan := c.tracer.Start(ctx, "KuCoin.HTTPRequest")
+	_, reqSpan := c.tracer.Start(ctx, "KuCoin.HTTPRequest")
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", url))
+		logger.WithContext(ctx, c.logger).Error("Failed to make request", zap.Error(err), zap.String("url", url))
 		reqSpan.SetStatus(codes.Error, "Failed to make HTTP request")
 		reqSpan.RecordError(err)
 		reqSpan.End()
 
 		span.SetStatus(codes.Error, "Failed to make HTTP request")
 		span.RecordError(err)
-		return 0, 0, time.Time{}, fmt.Errorf("failed to make request: %w", err)
+		return nil, exchange.RequestError(c.Name(), err)
 	}
 	defer resp.Body.Close()
+	observeRateLimit(c.limiter, resp)
 
 	reqSpan.SetAttributes(
 		attribute.Int("http.status_code", resp.StatusCode),
@@ -87,91 +313,62 @@ func (c *KuCoinClient) GetOrderBook(ctx context.Context, symbol string) (float64
 	reqSpan.End()
 
 	if resp.StatusCode != http.StatusOK {
-		errMsg := fmt.Sprintf("unexpected status code: %d", resp.StatusCode)
-		c.logger.Error("Unexpected status code",
+		statusErr := c.statusError(resp)
+		logger.WithContext(ctx, c.logger).Error("Unexpected status code",
 			zap.Int("status_code", resp.StatusCode),
+			zap.Duration("retry_after", statusErr.RetryAfter),
 			zap.String("url", url))
 
-		span.SetStatus(codes.Error, errMsg)
-		return 0, 0, time.Time{}, fmt.Errorf("%s", errMsg)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return nil, statusErr
 	}
 
 	// Создаем вложенный спан для декодирования ответа
-	ctx, decodeSpan := c.tracer.Start(ctx, "KuCoin.DecodeResponse")
+	_, decodeSpan := c.tracer.Start(ctx, "KuCoin.DecodeResponse")
 	var response OrderBookResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		c.logger.Error("Failed to decode response", zap.Error(err))
+		logger.WithContext(ctx, c.logger).Error("Failed to decode response", zap.Error(err))
 		decodeSpan.SetStatus(codes.Error, "Failed to decode response")
 		decodeSpan.RecordError(err)
 		decodeSpan.End()
 
 		span.SetStatus(codes.Error, "Failed to decode response")
 		span.RecordError(err)
-		return 0, 0, time.Time{}, fmt.Errorf("failed to decode response: %w", err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
 	}
 	decodeSpan.End()
 
+	if response.Code != codeOK {
+		errMsg := fmt.Sprintf("kucoin error %s", response.Code)
+		span.SetStatus(codes.Error, errMsg)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("%s", errMsg))
+	}
+
+	// На неизвестный символ KuCoin отвечает успешно, но с пустым стаканом
 	if len(response.Data.Asks) == 0 || len(response.Data.Bids) == 0 {
 		errMsg := "empty order book data"
-		c.logger.Error(errMsg,
+		logger.WithContext(ctx, c.logger).Error(errMsg,
 			zap.String("symbol", symbol),
 			zap.Int("asks_length", len(response.Data.Asks)),
 			zap.Int("bids_length", len(response.Data.Bids)))
 
 		span.SetStatus(codes.Error, errMsg)
-		return 0, 0, time.Time{}, fmt.Errorf("%s", errMsg)
+		return nil, exchange.NewError(c.Name(), exchange.ErrSymbolNotFound, fmt.Errorf("%s", errMsg))
 	}
 
-	// Создаем вложенный спан для парсинга цен
-	_, parseSpan := c.tracer.Start(ctx, "KuCoin.ParsePrices")
-	// Получаем первые ask и bid цены
-	askPrice, err := strconv.ParseFloat(response.Data.Asks[0][0], 64)
-	if err != nil {
-		c.logger.Error("Failed to parse ask price",
-			zap.Error(err),
-			zap.String("raw_value", response.Data.Asks[0][0]))
-
-		parseSpan.SetStatus(codes.Error, "Failed to parse ask price")
-		parseSpan.RecordError(err)
-		parseSpan.End()
+	return &response, nil
+}
 
-		span.SetStatus(codes.Error, "Failed to parse ask price")
-		span.RecordError(err)
-		return 0, 0, time.Time{}, fmt.Errorf("failed to parse ask price: %w", err)
+// statusError классифицирует неуспешный ответ KuCoin. Если Retry-After нет,
+// время повтора берется из заголовка gw-ratelimit-reset (в миллисекундах).
+func (c *KuCoinClient) statusError(resp *http.Response) *exchange.Error {
+	statusErr := exchange.StatusError(c.Name(), resp)
+	if statusErr.RetryAfter > 0 || resp.StatusCode != http.StatusTooManyRequests {
+		return statusErr
 	}
 
-	bidPrice, err := strconv.ParseFloat(response.Data.Bids[0][0], 64)
-	if err != nil {
-		c.logger.Error("Failed to parse bid price",
-			zap.Error(err),
-			zap.String("raw_value", response.Data.Bids[0][0]))
-
-		parseSpan.SetStatus(codes.Error, "Failed to parse bid price")
-		parseSpan.RecordError(err)
-		parseSpan.End()
-
-		span.SetStatus(codes.Error, "Failed to parse bid price")
-		span.RecordError(err)
-		return 0, 0, time.Time{}, fmt.Errorf("failed to parse bid price: %w", err)
+	if resetMs, err := strconv.ParseInt(resp.Header.Get("gw-ratelimit-reset"), 10, 64); err == nil && resetMs > 0 {
+		statusErr.RetryAfter = time.Duration(resetMs) * time.Millisecond
 	}
-	parseSpan.End()
-
-	// Преобразуем timestamp из миллисекунд в time.Time в UTC
-	timestamp := time.Unix(0, response.Data.Time*int64(time.Millisecond)).UTC()
-
-	c.logger.Debug("Successfully received order book data",
-		zap.String("symbol", symbol),
-		zap.Float64("ask", askPrice),
-		zap.Float64("bid", bidPrice),
-		zap.Time("timestamp", timestamp))
-
-	// Добавляем результаты в спан
-	span.SetAttributes(
-		attribute.Float64("ask", askPrice),
-		attribute.Float64("bid", bidPrice),
-		attribute.String("timestamp", timestamp.Format(time.RFC3339)),
-	)
-	span.SetStatus(codes.Ok, "Successfully received order book data")
-
-	return askPrice, bidPrice, timestamp, nil
+	return statusErr
 }
@@ -4,18 +4,25 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
 )
 
 // Вспомогательная функция для создания тестового сервера и клиента
-func setupTestServerAndClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) (*KuCoinClient, *httptest.Server) {
+func setupTestServerAndClient(
+	t *testing.T,
+	handler func(w http.ResponseWriter, r *http.Request),
+	opts ...Option,
+) (*KuCoinClient, *httptest.Server) {
 	server := httptest.NewServer(http.HandlerFunc(handler))
 	logger := zap.NewNop()
-	client := NewKucoinClient(server.URL, logger)
+	client := NewKucoinClient(server.URL, logger, opts...)
 	return client, server
 }
 
@@ -111,6 +118,7 @@ func TestGetOrderBook_EmptyData(t *testing.T) {
 	// Проверяем, что получили ошибку
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "empty order book data")
+	assert.ErrorIs(t, err, exchange.ErrSymbolNotFound)
 }
 
 func TestGetOrderBook_InvalidPrices(t *testing.T) {
@@ -158,6 +166,37 @@ func TestGetOrderBook_ServerError(t *testing.T) {
 	// Проверяем, что получили ошибку
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unexpected status code: 500")
+	assert.ErrorIs(t, err, exchange.ErrUnavailable)
+}
+
+func TestGetOrderBook_RateLimited(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		value          string
+		wantRetryAfter time.Duration
+	}{
+		{name: "Retry-After", header: "Retry-After", value: "3", wantRetryAfter: 3 * time.Second},
+		{name: "gw-ratelimit-reset", header: "gw-ratelimit-reset", value: "1500", wantRetryAfter: 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(tt.header, tt.value)
+				w.WriteHeader(http.StatusTooManyRequests)
+				writeResponse(t, w, []byte(`{"code":"429000","msg":"Too Many Requests"}`))
+			})
+			defer server.Close()
+
+			_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+			assert.ErrorIs(t, err, exchange.ErrRateLimited)
+			retryAfter, ok := exchange.RetryAfter(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantRetryAfter, retryAfter)
+		})
+	}
 }
 
 func TestGetOrderBook_NetworkError(t *testing.T) {
@@ -173,4 +212,226 @@ func TestGetOrderBook_NetworkError(t *testing.T) {
 	// Проверяем, что получили ошибку
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to make request")
+	assert.ErrorIs(t, err, exchange.ErrUnavailable)
+}
+
+func TestGetOrderBookDepth(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/market/orderbook/level2_20", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": {
+				"sequence": "1234567890",
+				"time": 1617267321123,
+				"bids": [["40000.0", "1.0"], ["39999.0", "0.5"], ["39998.0", "2"]],
+				"asks": [["40001.0", "0.8"], ["40002.0", "0.3"], ["40003.0", "4"]]
+			}
+		}`))
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	book, err := client.GetOrderBookDepth(context.Background(), "BTC-USDT", 2)
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, "kucoin", book.Exchange)
+	assert.Equal(t, int64(1234567890), book.Sequence)
+	assert.Equal(t, []model.PriceLevel{{Price: 40001, Size: 0.8}, {Price: 40002, Size: 0.3}}, book.Asks)
+	assert.Equal(t, []model.PriceLevel{{Price: 40000, Size: 1}, {Price: 39999, Size: 0.5}}, book.Bids)
+	assert.Equal(t, time.Unix(0, 1617267321123*int64(time.Millisecond)).UTC(), book.Timestamp)
+}
+
+func TestGetOrderBookDepth_Level2_100(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/market/orderbook/level2_100", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": {"sequence": "1", "time": 1617267321123, "bids": [["1", "1"]], "asks": [["2", "1"]]}
+		}`))
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	_, err := client.GetOrderBookDepth(context.Background(), "BTC-USDT", 50)
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+}
+
+func TestGetOrderBookDepth_InvalidSize(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": {"sequence": "1", "time": 1617267321123, "bids": [["1", "x"]], "asks": [["2", "1"]]}
+		}`))
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	_, err := client.GetOrderBookDepth(context.Background(), "BTC-USDT", 20)
+
+	// Проверяем результаты
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse bids")
+}
+
+func TestListSymbols(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/symbols", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": [
+				{"symbol": "BTC-USDT", "baseCurrency": "BTC", "quoteCurrency": "USDT", "enableTrading": true},
+				{"symbol": "OLD-USDT", "baseCurrency": "OLD", "quoteCurrency": "USDT", "enableTrading": false}
+			]
+		}`))
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	symbols, err := client.ListSymbols(context.Background())
+
+	// Проверяем результаты: пары без торговли пропускаются
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Symbol{{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"}}, symbols)
+}
+
+func TestPing(t *testing.T) {
+	t.Run("reachable", func(t *testing.T) {
+		client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/timestamp", r.URL.Path)
+			writeResponse(t, w, []byte(`{"code":"200000","data":1617267321123}`))
+		})
+		defer server.Close()
+
+		assert.NoError(t, client.Ping(context.Background()))
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		defer server.Close()
+
+		err := client.Ping(context.Background())
+
+		assert.ErrorIs(t, err, exchange.ErrUnavailable)
+	})
+}
+
+func TestGetOrderBook_RetriesTransientErrors(t *testing.T) {
+	// Arrange
+	var requests atomic.Int32
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": {"sequence": "1", "time": 1617267321123, "bids": [["40000.0", "1.0"]], "asks": [["40001.0", "0.8"]]}
+		}`))
+	}, WithRetryPolicy(exchange.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	defer server.Close()
+
+	// Act
+	ask, bid, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 40001.0, ask)
+	assert.Equal(t, 40000.0, bid)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestGetOrderBook_CircuitBreakerOpens(t *testing.T) {
+	// Arrange
+	var requests atomic.Int32
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithCircuitBreaker(exchange.BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}))
+	defer server.Close()
+
+	// Act
+	for i := 0; i < 2; i++ {
+		_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+		assert.ErrorIs(t, err, exchange.ErrUnavailable)
+	}
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.ErrorIs(t, err, exchange.ErrCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, exchange.BreakerOpen, client.BreakerState())
+	// Разомкнутый автомат виден в проверке доступности
+	assert.ErrorIs(t, client.Ping(context.Background()), exchange.ErrCircuitOpen)
+}
+
+func TestGetOrderBook_ClientDeadlinesDoNotOpenBreaker(t *testing.T) {
+	// Arrange
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		writeResponse(t, w, []byte(`{"code": "200000", "data": {"time": 1617267321123, `+
+			`"bids": [["40000.0", "1.0"]], "asks": [["40001.0", "0.8"]]}}`))
+	},
+		WithRetryPolicy(exchange.RetryPolicy{MaxAttempts: 1}),
+		WithCircuitBreaker(exchange.BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}))
+	defer server.Close()
+
+	// Act
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, _, _, err := client.GetOrderBook(ctx, "BTC-USDT")
+		cancel()
+		assert.Error(t, err)
+	}
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, exchange.BreakerClosed, client.BreakerState())
+}
+
+func TestGetOrderBook_RateLimitHeadersPauseRequests(t *testing.T) {
+	// Arrange
+	var requests atomic.Int32
+	limiter := exchange.NewRateLimiter("kucoin", exchange.LimiterConfig{Limit: 2000, Window: 30 * time.Second})
+	client, server := setupTestServerAndClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		// Бюджет исчерпан, окно сбросится через 10 секунд
+		w.Header().Set("gw-ratelimit-remaining", "0")
+		w.Header().Set("gw-ratelimit-reset", "10000")
+		writeResponse(t, w, []byte(`{
+			"code": "200000",
+			"data": {"sequence": "1", "time": 1617267321123, "bids": [["40000.0", "1.0"]], "asks": [["40001.0", "0.8"]]}
+		}`))
+	}, WithRateLimiter(limiter))
+	defer server.Close()
+
+	// Act
+	_, _, _, firstErr := client.GetOrderBook(context.Background(), "BTC-USDT")
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.ErrorIs(t, err, exchange.ErrRateLimited)
+	retryAfter, ok := exchange.RetryAfter(err)
+	assert.True(t, ok)
+	assert.InDelta(t, 10*time.Second, retryAfter, float64(time.Second))
+	// Второй запрос отклонен без обращения к KuCoin
+	assert.Equal(t, int32(1), requests.Load())
 }
@@ -0,0 +1,59 @@
+package kucoin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+)
+
+// Ping проверяет доступность API KuCoin запросом времени сервера
+func (c *KuCoinClient) Ping(ctx context.Context) error {
+	ctx, span := c.tracer.Start(ctx, "KuCoin.Ping")
+	defer span.End()
+
+	// Пока автомат разомкнут, запросы к KuCoin не выполняются, и биржа для сервиса недоступна
+	if c.breaker != nil {
+		if err := c.breaker.Err(); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+	}
+
+	if err := wait(ctx, c.limiter, weightTimestamp); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/timestamp", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+	observeRateLimit(c.limiter, resp)
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := c.statusError(resp)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return statusErr
+	}
+
+	span.SetStatus(codes.Ok, "KuCoin is reachable")
+	return nil
+}
@@ -0,0 +1,72 @@
+package kucoin
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+)
+
+// Вес запросов к публичным эндпоинтам KuCoin. Бюджет пула Public - 2000 единиц
+// за 30 секунд на IP (KUCOIN_RATE_LIMIT, KUCOIN_RATE_LIMIT_WINDOW).
+const (
+	weightOrderBook20  = 2
+	weightOrderBook100 = 4
+	weightSymbols      = 4
+	weightTimestamp    = 3
+	weightBulletPublic = 10
+)
+
+// WithRateLimiter расходует бюджет limiter на каждый запрос к KuCoin. Один
+// ограничитель передается всем клиентам KuCoin, так как квота общая на IP.
+func WithRateLimiter(limiter *exchange.RateLimiter) Option {
+	return func(c *KuCoinClient) {
+		c.limiter = limiter
+	}
+}
+
+// orderBookWeight возвращает вес запроса стакана с эндпоинта endpoint
+func orderBookWeight(endpoint string) int {
+	if endpoint == "level2_100" {
+		return weightOrderBook100
+	}
+	return weightOrderBook20
+}
+
+// wait резервирует вес запроса; без ограничителя запрос выполняется сразу
+func wait(ctx context.Context, limiter *exchange.RateLimiter, weight int) error {
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx, weight)
+}
+
+// observeRateLimit подстраивает ограничитель под заголовки gw-ratelimit-remaining
+// и gw-ratelimit-reset (в миллисекундах), а после ответа 429 приостанавливает запросы
+// на Retry-After или до сброса окна
+func observeRateLimit(limiter *exchange.RateLimiter, resp *http.Response) {
+	if limiter == nil {
+		return
+	}
+
+	var reset time.Duration
+	if resetMs, err := strconv.ParseInt(resp.Header.Get("gw-ratelimit-reset"), 10, 64); err == nil && resetMs > 0 {
+		reset = time.Duration(resetMs) * time.Millisecond
+	}
+
+	if remaining, err := strconv.Atoi(resp.Header.Get("gw-ratelimit-remaining")); err == nil {
+		limiter.Observe(remaining, reset)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := exchange.StatusError(exchangeName, resp).RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = reset
+		}
+		if retryAfter > 0 {
+			limiter.Pause(retryAfter)
+		}
+	}
+}
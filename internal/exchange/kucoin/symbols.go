@@ -0,0 +1,93 @@
+package kucoin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+type SymbolsResponse struct {
+	Code string `json:"code"`
+	Data []struct {
+		Symbol        string `json:"symbol"`
+		BaseCurrency  string `json:"baseCurrency"`
+		QuoteCurrency string `json:"quoteCurrency"`
+		EnableTrading bool   `json:"enableTrading"`
+	} `json:"data"`
+}
+
+// ListSymbols возвращает пары, по которым на KuCoin разрешена торговля
+func (c *KuCoinClient) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	ctx, span := c.tracer.Start(ctx, "KuCoin.ListSymbols")
+	defer span.End()
+
+	var response SymbolsResponse
+	err := c.do(ctx, weightSymbols, func(ctx context.Context) error {
+		return c.fetchSymbols(ctx, span, &response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]model.Symbol, 0, len(response.Data))
+	for _, item := range response.Data {
+		if !item.EnableTrading {
+			continue
+		}
+		symbols = append(symbols, model.Symbol{
+			Symbol: item.Symbol,
+			Base:   item.BaseCurrency,
+			Quote:  item.QuoteCurrency,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("count", len(symbols)))
+	span.SetStatus(codes.Ok, "Symbols received")
+
+	return symbols, nil
+}
+
+// fetchSymbols выполняет одну попытку запроса списка пар
+func (c *KuCoinClient) fetchSymbols(ctx context.Context, span trace.Span, response *SymbolsResponse) error {
+	url := fmt.Sprintf("%s/api/v1/symbols", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", url))
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+	observeRateLimit(c.limiter, resp)
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := c.statusError(resp)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return statusErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		span.SetStatus(codes.Error, "Failed to decode response")
+		span.RecordError(err)
+		return exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
+	}
+	return nil
+}
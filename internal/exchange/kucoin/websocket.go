@@ -0,0 +1,440 @@
+package kucoin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+)
+
+const (
+	// ChannelTicker - канал лучших цен /market/ticker
+	ChannelTicker = "ticker"
+	// ChannelLevel2 - канал пяти лучших уровней стакана /spotMarket/level2Depth5
+	ChannelLevel2 = "level2"
+
+	// maxTopicsPerSubscribe - ограничение KuCoin на число символов в одной подписке
+	maxTopicsPerSubscribe = 100
+
+	defaultPingInterval = 18 * time.Second
+	defaultPingTimeout  = 10 * time.Second
+)
+
+// ErrInvalidChannel возвращается, если канал не ticker и не level2
+var ErrInvalidChannel = errors.New("kucoin websocket channel must be ticker or level2")
+
+// WebSocketConfig содержит настройки подключения к WebSocket KuCoin
+type WebSocketConfig struct {
+	Symbols     []string
+	Channel     string
+	MaxQuoteAge time.Duration
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// Limiter - общий с REST-клиентом ограничитель запросов к KuCoin
+	Limiter *exchange.RateLimiter
+}
+
+// WebSocketFeed держит в памяти лучшие цены по символам,
+// получая их из публичного WebSocket KuCoin
+type WebSocketFeed struct {
+	baseURL    string
+	config     WebSocketConfig
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+	logger     *zap.Logger
+
+	mu     sync.RWMutex
+	quotes map[string]wsQuote
+}
+
+// wsQuote - последняя известная верхушка стакана по символу
+type wsQuote struct {
+	ask        float64
+	bid        float64
+	timestamp  time.Time
+	receivedAt time.Time
+}
+
+type bulletResponse struct {
+	Code string `json:"code"`
+	Data struct {
+		Token           string `json:"token"`
+		InstanceServers []struct {
+			Endpoint     string `json:"endpoint"`
+			PingInterval int64  `json:"pingInterval"`
+			PingTimeout  int64  `json:"pingTimeout"`
+		} `json:"instanceServers"`
+	} `json:"data"`
+}
+
+type wsMessage struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Topic   string          `json:"topic"`
+	Subject string          `json:"subject"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type wsRequest struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	Topic          string `json:"topic,omitempty"`
+	PrivateChannel bool   `json:"privateChannel"`
+	Response       bool   `json:"response"`
+}
+
+type tickerData struct {
+	BestAsk string `json:"bestAsk"`
+	BestBid string `json:"bestBid"`
+	Time    int64  `json:"time"`
+}
+
+type level2Data struct {
+	Asks      [][]string `json:"asks"`
+	Bids      [][]string `json:"bids"`
+	Timestamp int64      `json:"timestamp"`
+}
+
+// wsSession - параметры подключения, полученные вместе с публичным токеном
+type wsSession struct {
+	endpoint     string
+	pingInterval time.Duration
+	pingTimeout  time.Duration
+}
+
+// NewWebSocketFeed создает поток лучших цен. Пустой канал означает ChannelTicker,
+// неизвестный - ошибку ErrInvalidChannel.
+func NewWebSocketFeed(baseURL string, config WebSocketConfig, logger *zap.Logger) (*WebSocketFeed, error) {
+	switch config.Channel {
+	case "":
+		config.Channel = ChannelTicker
+	case ChannelTicker, ChannelLevel2:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidChannel, config.Channel)
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = config.MinBackoff
+	}
+
+	return &WebSocketFeed{
+		baseURL: baseURL,
+		config:  config,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		dialer: &websocket.Dialer{
+			HandshakeTimeout: 10 * time.Second,
+		},
+		logger: logger,
+		quotes: make(map[string]wsQuote),
+	}, nil
+}
+
+// BestQuote возвращает лучшие цены по символу, если они получены не раньше MaxQuoteAge назад
+func (f *WebSocketFeed) BestQuote(symbol string) (float64, float64, time.Time, bool) {
+	f.mu.RLock()
+	quote, ok := f.quotes[symbol]
+	f.mu.RUnlock()
+
+	if !ok || time.Since(quote.receivedAt) > f.config.MaxQuoteAge {
+		return 0, 0, time.Time{}, false
+	}
+
+	return quote.ask, quote.bid, quote.timestamp, true
+}
+
+// Run поддерживает подключение к WebSocket до отмены контекста,
+// переподключаясь с экспоненциальной задержкой после обрывов
+func (f *WebSocketFeed) Run(ctx context.Context) {
+	backoff := f.config.MinBackoff
+
+	for {
+		connected, err := f.runSession(ctx)
+
+		// После обрыва старые цены больше не обновляются, поэтому их нельзя отдавать
+		f.resetQuotes()
+
+		if ctx.Err() != nil {
+			f.logger.Info("KuCoin WebSocket feed stopped")
+			return
+		}
+
+		if connected {
+			backoff = f.config.MinBackoff
+		}
+
+		delay := jitter(backoff)
+		f.logger.Warn("KuCoin WebSocket disconnected, reconnecting",
+			zap.Error(err),
+			zap.Duration("delay", delay))
+
+		select {
+		case <-ctx.Done():
+			f.logger.Info("KuCoin WebSocket feed stopped")
+			return
+		case <-time.After(delay):
+		}
+
+		backoff *= 2
+		if backoff > f.config.MaxBackoff {
+			backoff = f.config.MaxBackoff
+		}
+	}
+}
+
+// runSession выполняет одно подключение: получает токен, подписывается на каналы
+// и читает сообщения до ошибки. Возвращает true, если подключение было установлено.
+func (f *WebSocketFeed) runSession(ctx context.Context) (bool, error) {
+	session, err := f.requestToken(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	conn, _, err := f.dialer.DialContext(ctx, session.endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to dial websocket: %w", err)
+	}
+	defer conn.Close()
+
+	readTimeout := session.pingInterval + session.pingTimeout
+	if err := f.awaitWelcome(conn, readTimeout); err != nil {
+		return false, err
+	}
+
+	if err := f.subscribe(conn); err != nil {
+		return false, err
+	}
+
+	f.logger.Info("Connected to KuCoin WebSocket",
+		zap.String("channel", f.config.Channel),
+		zap.Strings("symbols", f.config.Symbols))
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Закрываем соединение при отмене контекста, чтобы прервать блокирующее чтение
+	go func() {
+		<-sessionCtx.Done()
+		conn.Close()
+	}()
+
+	go f.keepAlive(sessionCtx, conn, session.pingInterval)
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return true, err
+		}
+
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return true, fmt.Errorf("failed to read websocket message: %w", err)
+		}
+
+		switch msg.Type {
+		case "message":
+			f.handleMessage(msg)
+		case "error":
+			return true, fmt.Errorf("websocket error message: %s", string(msg.Data))
+		}
+	}
+}
+
+// requestToken получает публичный токен и адрес сервера для подключения
+func (f *WebSocketFeed) requestToken(ctx context.Context) (*wsSession, error) {
+	if err := wait(ctx, f.config.Limiter, weightBulletPublic); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/api/v1/bullet-public", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request websocket token: %w", err)
+	}
+	defer resp.Body.Close()
+	observeRateLimit(f.config.Limiter, resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected token status code: %d", resp.StatusCode)
+	}
+
+	var bullet bulletResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bullet); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if bullet.Data.Token == "" || len(bullet.Data.InstanceServers) == 0 {
+		return nil, errors.New("empty websocket token response")
+	}
+
+	server := bullet.Data.InstanceServers[0]
+	endpoint, err := url.Parse(server.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+	query.Set("token", bullet.Data.Token)
+	query.Set("connectId", strconv.FormatInt(time.Now().UnixNano(), 10))
+	endpoint.RawQuery = query.Encode()
+
+	session := &wsSession{
+		endpoint:     endpoint.String(),
+		pingInterval: time.Duration(server.PingInterval) * time.Millisecond,
+		pingTimeout:  time.Duration(server.PingTimeout) * time.Millisecond,
+	}
+	if session.pingInterval <= 0 {
+		session.pingInterval = defaultPingInterval
+	}
+	if session.pingTimeout <= 0 {
+		session.pingTimeout = defaultPingTimeout
+	}
+
+	return session, nil
+}
+
+func (f *WebSocketFeed) awaitWelcome(conn *websocket.Conn, timeout time.Duration) error {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+
+	var msg wsMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		return fmt.Errorf("failed to read welcome message: %w", err)
+	}
+	if msg.Type != "welcome" {
+		return fmt.Errorf("unexpected first message type: %s", msg.Type)
+	}
+
+	return nil
+}
+
+func (f *WebSocketFeed) subscribe(conn *websocket.Conn) error {
+	prefix := "/market/ticker:"
+	if f.config.Channel == ChannelLevel2 {
+		prefix = "/spotMarket/level2Depth5:"
+	}
+
+	for start := 0; start < len(f.config.Symbols); start += maxTopicsPerSubscribe {
+		end := min(start+maxTopicsPerSubscribe, len(f.config.Symbols))
+
+		err := conn.WriteJSON(wsRequest{
+			ID:    strconv.FormatInt(time.Now().UnixNano(), 10),
+			Type:  "subscribe",
+			Topic: prefix + strings.Join(f.config.Symbols[start:end], ","),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to subscribe: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// keepAlive отправляет ping с интервалом, который сервер указал вместе с токеном
+func (f *WebSocketFeed) keepAlive(ctx context.Context, conn *websocket.Conn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := conn.WriteJSON(wsRequest{
+				ID:   strconv.FormatInt(time.Now().UnixNano(), 10),
+				Type: "ping",
+			})
+			if err != nil {
+				f.logger.Debug("Failed to send websocket ping", zap.Error(err))
+				return
+			}
+		}
+	}
+}
+
+func (f *WebSocketFeed) handleMessage(msg wsMessage) {
+	_, symbol, ok := strings.Cut(msg.Topic, ":")
+	if !ok {
+		return
+	}
+
+	var (
+		askRaw, bidRaw string
+		timeMs         int64
+	)
+
+	switch {
+	case strings.HasPrefix(msg.Topic, "/market/ticker:"):
+		var data tickerData
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			f.logger.Debug("Failed to decode ticker message", zap.Error(err))
+			return
+		}
+		askRaw, bidRaw, timeMs = data.BestAsk, data.BestBid, data.Time
+	case strings.HasPrefix(msg.Topic, "/spotMarket/level2Depth5:"):
+		var data level2Data
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			f.logger.Debug("Failed to decode level2 message", zap.Error(err))
+			return
+		}
+		if len(data.Asks) == 0 || len(data.Bids) == 0 || len(data.Asks[0]) == 0 || len(data.Bids[0]) == 0 {
+			return
+		}
+		askRaw, bidRaw, timeMs = data.Asks[0][0], data.Bids[0][0], data.Timestamp
+	default:
+		return
+	}
+
+	ask, err := strconv.ParseFloat(askRaw, 64)
+	if err != nil {
+		f.logger.Debug("Failed to parse websocket ask price", zap.Error(err), zap.String("raw_value", askRaw))
+		return
+	}
+	bid, err := strconv.ParseFloat(bidRaw, 64)
+	if err != nil {
+		f.logger.Debug("Failed to parse websocket bid price", zap.Error(err), zap.String("raw_value", bidRaw))
+		return
+	}
+
+	f.mu.Lock()
+	f.quotes[symbol] = wsQuote{
+		ask:        ask,
+		bid:        bid,
+		timestamp:  time.Unix(0, timeMs*int64(time.Millisecond)).UTC(),
+		receivedAt: time.Now(),
+	}
+	f.mu.Unlock()
+}
+
+func (f *WebSocketFeed) resetQuotes() {
+	f.mu.Lock()
+	f.quotes = make(map[string]wsQuote)
+	f.mu.Unlock()
+}
+
+// jitter возвращает случайную задержку в диапазоне [d/2, d)
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
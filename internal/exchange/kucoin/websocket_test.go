@@ -0,0 +1,251 @@
+package kucoin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// Локальная замена WebSocket KuCoin: выдает токен, приветствие и отвечает на ping
+type wsStandIn struct {
+	server      *httptest.Server
+	connections atomic.Int32
+	pings       atomic.Int32
+	subscribed  chan string
+	// onConnect вызывается после подписки и может отправлять сообщения клиенту
+	onConnect func(conn *websocket.Conn, n int32)
+}
+
+func newWSStandIn(t *testing.T, onConnect func(conn *websocket.Conn, n int32)) *wsStandIn {
+	standIn := &wsStandIn{subscribed: make(chan string, 10), onConnect: onConnect}
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/bullet-public", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		endpoint := "ws" + strings.TrimPrefix(standIn.server.URL, "http") + "/ws"
+		writeResponse(t, w, []byte(fmt.Sprintf(`{
+			"code": "200000",
+			"data": {
+				"token": "test-token",
+				"instanceServers": [{"endpoint": %q, "pingInterval": 20, "pingTimeout": 500}]
+			}
+		}`, endpoint)))
+	})
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-token", r.URL.Query().Get("token"))
+		assert.NotEmpty(t, r.URL.Query().Get("connectId"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		n := standIn.connections.Add(1)
+		if err := conn.WriteJSON(map[string]string{"id": "welcome-id", "type": "welcome"}); err != nil {
+			return
+		}
+
+		var sub wsRequest
+		if err := conn.ReadJSON(&sub); err != nil {
+			return
+		}
+		standIn.subscribed <- sub.Topic
+
+		go standIn.onConnect(conn, n)
+
+		for {
+			var msg wsRequest
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "ping" {
+				standIn.pings.Add(1)
+			}
+		}
+	})
+
+	standIn.server = httptest.NewServer(mux)
+	return standIn
+}
+
+func tickerMessage(symbol, ask, bid string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":    "message",
+		"topic":   "/market/ticker:" + symbol,
+		"subject": "trade.ticker",
+		"data": map[string]interface{}{
+			"bestAsk": ask,
+			"bestBid": bid,
+			"time":    1617267321123,
+		},
+	}
+}
+
+func startFeed(t *testing.T, baseURL string, config WebSocketConfig) (*WebSocketFeed, context.CancelFunc) {
+	feed, err := NewWebSocketFeed(baseURL, config, zap.NewNop())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		feed.Run(ctx)
+		close(done)
+	}()
+
+	return feed, func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("websocket feed did not stop")
+		}
+	}
+}
+
+func TestWebSocketFeed_TickerQuotes(t *testing.T) {
+	// Arrange
+	standIn := newWSStandIn(t, func(conn *websocket.Conn, _ int32) {
+		_ = conn.WriteJSON(tickerMessage("BTC-USDT", "40001.5", "40000.5"))
+	})
+	defer standIn.server.Close()
+
+	// Act
+	feed, stop := startFeed(t, standIn.server.URL, WebSocketConfig{
+		Symbols:     []string{"BTC-USDT", "ETH-USDT"},
+		MaxQuoteAge: time.Minute,
+	})
+	defer stop()
+
+	// Assert
+	assert.Equal(t, "/market/ticker:BTC-USDT,ETH-USDT", <-standIn.subscribed)
+	assert.Eventually(t, func() bool {
+		_, _, _, ok := feed.BestQuote("BTC-USDT")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	ask, bid, timestamp, _ := feed.BestQuote("BTC-USDT")
+	assert.Equal(t, 40001.5, ask)
+	assert.Equal(t, 40000.5, bid)
+	assert.Equal(t, time.Unix(0, 1617267321123*int64(time.Millisecond)).UTC(), timestamp)
+
+	_, _, _, ok := feed.BestQuote("ETH-USDT")
+	assert.False(t, ok)
+
+	// Клиент должен регулярно отправлять ping
+	assert.Eventually(t, func() bool { return standIn.pings.Load() >= 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWebSocketFeed_Level2Quotes(t *testing.T) {
+	// Arrange
+	standIn := newWSStandIn(t, func(conn *websocket.Conn, _ int32) {
+		_ = conn.WriteJSON(map[string]interface{}{
+			"type":    "message",
+			"topic":   "/spotMarket/level2Depth5:ETH-USDT",
+			"subject": "level2",
+			"data": map[string]interface{}{
+				"asks":      [][]string{{"2001.1", "3"}, {"2002", "1"}},
+				"bids":      [][]string{{"2000.9", "2"}, {"2000", "5"}},
+				"timestamp": 1617267321123,
+			},
+		})
+	})
+	defer standIn.server.Close()
+
+	// Act
+	feed, stop := startFeed(t, standIn.server.URL, WebSocketConfig{
+		Symbols:     []string{"ETH-USDT"},
+		Channel:     ChannelLevel2,
+		MaxQuoteAge: time.Minute,
+	})
+	defer stop()
+
+	// Assert
+	assert.Equal(t, "/spotMarket/level2Depth5:ETH-USDT", <-standIn.subscribed)
+	assert.Eventually(t, func() bool {
+		ask, bid, _, ok := feed.BestQuote("ETH-USDT")
+		return ok && ask == 2001.1 && bid == 2000.9
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestWebSocketFeed_ReconnectsAfterDisconnect(t *testing.T) {
+	// Arrange: первое соединение сервер сразу закрывает
+	standIn := newWSStandIn(t, func(conn *websocket.Conn, n int32) {
+		if n == 1 {
+			conn.Close()
+			return
+		}
+		_ = conn.WriteJSON(tickerMessage("BTC-USDT", "101", "100"))
+	})
+	defer standIn.server.Close()
+
+	// Act
+	feed, stop := startFeed(t, standIn.server.URL, WebSocketConfig{
+		Symbols:     []string{"BTC-USDT"},
+		MaxQuoteAge: time.Minute,
+		MinBackoff:  10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+	})
+	defer stop()
+
+	// Assert
+	assert.Eventually(t, func() bool {
+		_, bid, _, ok := feed.BestQuote("BTC-USDT")
+		return ok && bid == 100
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, standIn.connections.Load(), int32(2))
+}
+
+func TestWebSocketFeed_StaleQuote(t *testing.T) {
+	// Arrange
+	feed, err := NewWebSocketFeed("http://localhost:1", WebSocketConfig{MaxQuoteAge: time.Second}, zap.NewNop())
+	require.NoError(t, err)
+	feed.quotes["BTC-USDT"] = wsQuote{ask: 101, bid: 100, receivedAt: time.Now().Add(-2 * time.Second)}
+
+	// Act
+	_, _, _, ok := feed.BestQuote("BTC-USDT")
+
+	// Assert
+	assert.False(t, ok)
+}
+
+func TestWebSocketFeed_TokenError(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	feed, err := NewWebSocketFeed(server.URL, WebSocketConfig{}, zap.NewNop())
+	require.NoError(t, err)
+
+	// Act
+	connected, err := feed.runSession(context.Background())
+
+	// Assert
+	assert.False(t, connected)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected token status code: 503")
+}
+
+func TestNewWebSocketFeed_Channel(t *testing.T) {
+	feed, err := NewWebSocketFeed("http://localhost:1", WebSocketConfig{}, zap.NewNop())
+	assert.NoError(t, err)
+	assert.Equal(t, ChannelTicker, feed.config.Channel)
+
+	_, err = NewWebSocketFeed("http://localhost:1", WebSocketConfig{Channel: ChannelLevel2}, zap.NewNop())
+	assert.NoError(t, err)
+
+	_, err = NewWebSocketFeed("http://localhost:1", WebSocketConfig{Channel: "tickr"}, zap.NewNop())
+	assert.ErrorIs(t, err, ErrInvalidChannel)
+}
@@ -0,0 +1,35 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// ParseLevels разбирает уровни стакана в формате [цена, объем, ...] и оставляет не больше depth первых
+func ParseLevels(raw [][]string, depth int) ([]model.PriceLevel, error) {
+	if depth > 0 && len(raw) > depth {
+		raw = raw[:depth]
+	}
+
+	levels := make([]model.PriceLevel, 0, len(raw))
+	for i, level := range raw {
+		if len(level) < 2 {
+			return nil, fmt.Errorf("level %d: expected price and size, got %d fields", i, len(level))
+		}
+
+		price, err := strconv.ParseFloat(level[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("level %d: failed to parse price: %w", i, err)
+		}
+		size, err := strconv.ParseFloat(level[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("level %d: failed to parse size: %w", i, err)
+		}
+
+		levels = append(levels, model.PriceLevel{Price: price, Size: size})
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,55 @@
+package exchange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+func TestParseLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      [][]string
+		depth    int
+		expected []model.PriceLevel
+		wantErr  string
+	}{
+		{
+			name:     "limited by depth",
+			raw:      [][]string{{"1.5", "2"}, {"1.4", "3", "extra"}, {"1.3", "1"}},
+			depth:    2,
+			expected: []model.PriceLevel{{Price: 1.5, Size: 2}, {Price: 1.4, Size: 3}},
+		},
+		{
+			name:     "depth larger than book",
+			raw:      [][]string{{"1.5", "2"}},
+			depth:    10,
+			expected: []model.PriceLevel{{Price: 1.5, Size: 2}},
+		},
+		{
+			name:    "missing size",
+			raw:     [][]string{{"1.5"}},
+			depth:   10,
+			wantErr: "expected price and size",
+		},
+		{
+			name:    "invalid price",
+			raw:     [][]string{{"abc", "1"}},
+			depth:   10,
+			wantErr: "failed to parse price",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, err := ParseLevels(tt.raw, tt.depth)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, levels)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errLimiterExhausted - запрос отклонен локальным ограничителем, не дойдя до биржи
+var errLimiterExhausted = errors.New("request weight budget exhausted")
+
+// LimiterConfig содержит настройки ограничителя исходящих запросов
+type LimiterConfig struct {
+	// Limit - суммарный вес запросов, который биржа допускает за Window
+	Limit  int
+	Window time.Duration
+	// MaxWait - сколько запрос может ждать пополнения бюджета. Если ждать
+	// пришлось бы дольше, запрос отклоняется с ErrRateLimited.
+	MaxWait time.Duration
+}
+
+// RateLimiter - общий для всех исходящих запросов к бирже ограничитель
+// (token bucket): каждый запрос расходует свой вес, бюджет пополняется
+// равномерно до Limit за Window. Остаток бюджета подстраивается под
+// заголовки ответов биржи через Observe и Pause.
+type RateLimiter struct {
+	exchange string
+	config   LimiterConfig
+	// perToken - время пополнения бюджета на единицу веса
+	perToken time.Duration
+	now      func() time.Time
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter создает ограничитель с полным бюджетом
+func NewRateLimiter(exchange string, config LimiterConfig) *RateLimiter {
+	if config.Limit <= 0 {
+		config.Limit = 1
+	}
+	if config.Window <= 0 {
+		config.Window = time.Second
+	}
+
+	return &RateLimiter{
+		exchange: exchange,
+		config:   config,
+		perToken: config.Window / time.Duration(config.Limit),
+		now:      time.Now,
+		tokens:   float64(config.Limit),
+		last:     time.Now(),
+	}
+}
+
+// Wait резервирует weight единиц бюджета. Если бюджета не хватает, ждет его
+// пополнения не дольше MaxWait; иначе сразу возвращает ошибку вида ErrRateLimited
+// с RetryAfter - временем, через которое бюджета хватит.
+func (l *RateLimiter) Wait(ctx context.Context, weight int) error {
+	l.mu.Lock()
+	now := l.now()
+	l.refill(now)
+
+	wait := l.delay(now, weight)
+	if wait > l.config.MaxWait {
+		l.mu.Unlock()
+		err := NewError(l.exchange, ErrRateLimited, errLimiterExhausted)
+		err.RetryAfter = wait
+		return err
+	}
+	// Вес списывается сразу, поэтому ожидающие запросы выстраиваются в очередь
+	l.tokens -= float64(weight)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += float64(weight)
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Observe подстраивает бюджет под остаток, который сообщила биржа: бюджет не
+// может быть больше remaining, а при нулевом остатке запросы ждут до сброса окна
+func (l *RateLimiter) Observe(remaining int, reset time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.refill(now)
+	if float64(remaining) < l.tokens {
+		l.tokens = float64(remaining)
+	}
+	if remaining <= 0 && reset > 0 {
+		l.pause(now, reset)
+	}
+}
+
+// Pause приостанавливает запросы на d, например после ответа 429 с Retry-After
+func (l *RateLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pause(l.now(), d)
+}
+
+func (l *RateLimiter) pause(now time.Time, d time.Duration) {
+	if until := now.Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// refill пополняет бюджет за время с предыдущего вызова
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.last)
+	if elapsed <= 0 {
+		return
+	}
+	l.last = now
+
+	l.tokens += float64(elapsed) / float64(l.perToken)
+	if limit := float64(l.config.Limit); l.tokens > limit {
+		l.tokens = limit
+	}
+}
+
+// delay возвращает время, через которое бюджета хватит на weight
+func (l *RateLimiter) delay(now time.Time, weight int) time.Duration {
+	var wait time.Duration
+	if missing := float64(weight) - l.tokens; missing > 0 {
+		wait = time.Duration(missing * float64(l.perToken))
+	}
+	if paused := l.pausedUntil.Sub(now); paused > wait {
+		wait = paused
+	}
+	return wait
+}
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(config LimiterConfig) (*RateLimiter, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter("kucoin", config)
+	limiter.now = func() time.Time { return now }
+	limiter.last = now
+	return limiter, &now
+}
+
+func TestRateLimiter_RejectsWhenBudgetExhausted(t *testing.T) {
+	limiter, now := newTestLimiter(LimiterConfig{Limit: 10, Window: 10 * time.Second})
+
+	assert.NoError(t, limiter.Wait(context.Background(), 6))
+	assert.NoError(t, limiter.Wait(context.Background(), 4))
+
+	err := limiter.Wait(context.Background(), 2)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	retryAfter, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, retryAfter)
+
+	// Бюджет пополняется равномерно
+	*now = now.Add(2 * time.Second)
+	assert.NoError(t, limiter.Wait(context.Background(), 2))
+}
+
+func TestRateLimiter_QueuesWithinMaxWait(t *testing.T) {
+	limiter := NewRateLimiter("kucoin", LimiterConfig{Limit: 100, Window: time.Second, MaxWait: time.Second})
+	assert.NoError(t, limiter.Wait(context.Background(), 100))
+
+	started := time.Now()
+	err := limiter.Wait(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), 40*time.Millisecond)
+}
+
+func TestRateLimiter_CanceledWaitReturnsBudget(t *testing.T) {
+	limiter, _ := newTestLimiter(LimiterConfig{Limit: 10, Window: time.Hour, MaxWait: 2 * time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, limiter.Wait(ctx, 15), context.Canceled)
+	assert.NoError(t, limiter.Wait(context.Background(), 10))
+}
+
+func TestRateLimiter_ObserveAdaptsToExchangeBudget(t *testing.T) {
+	limiter, now := newTestLimiter(LimiterConfig{Limit: 2000, Window: 30 * time.Second})
+
+	// Биржа сообщает меньший остаток, чем считает ограничитель
+	limiter.Observe(10, 5*time.Second)
+	err := limiter.Wait(context.Background(), 20)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.NoError(t, limiter.Wait(context.Background(), 10))
+
+	// При нулевом остатке запросы ждут сброса окна
+	*now = now.Add(time.Second)
+	limiter.Observe(0, 5*time.Second)
+	err = limiter.Wait(context.Background(), 1)
+	retryAfter, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, retryAfter)
+
+	*now = now.Add(5 * time.Second)
+	assert.NoError(t, limiter.Wait(context.Background(), 1))
+}
+
+func TestRateLimiter_Pause(t *testing.T) {
+	limiter, now := newTestLimiter(LimiterConfig{Limit: 2000, Window: 30 * time.Second})
+
+	limiter.Pause(3 * time.Second)
+	assert.ErrorIs(t, limiter.Wait(context.Background(), 1), ErrRateLimited)
+
+	*now = now.Add(3 * time.Second)
+	assert.NoError(t, limiter.Wait(context.Background(), 1))
+}
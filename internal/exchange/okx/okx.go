@@ -0,0 +1,257 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// codeOK - код успешного ответа OKX API
+const codeOK = "0"
+
+type OKXClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+	tracer     trace.Tracer
+}
+
+type OrderBookResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		Asks  [][]string `json:"asks"`
+		Bids  [][]string `json:"bids"`
+		Ts    string     `json:"ts"`
+		SeqID int64      `json:"seqId"`
+	} `json:"data"`
+}
+
+func NewOKXClient(baseURL string, logger *zap.Logger) *OKXClient {
+	return &OKXClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+		tracer: otel.Tracer("okx-client"),
+	}
+}
+
+func (c *OKXClient) Name() string {
+	return "okx"
+}
+
+// ToExchangeSymbol переводит символ BASE-QUOTE в формат инструмента OKX (btc-usdt -> BTC-USDT)
+func ToExchangeSymbol(symbol string) string {
+	return strings.ToUpper(symbol)
+}
+
+func (c *OKXClient) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	ctx, span := c.tracer.Start(ctx, "OKX.GetOrderBook",
+		trace.WithAttributes(attribute.String("symbol", symbol)))
+	defer span.End()
+
+	response, err := c.requestOrderBook(ctx, span, symbol, 20)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+	book := response.Data[0]
+
+	// Уровень может прийти без полей, поэтому разбираем его с проверкой формата
+	asks, err := exchange.ParseLevels(book.Asks, 1)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse ask price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse ask price: %w", err))
+	}
+	askPrice := asks[0].Price
+
+	bids, err := exchange.ParseLevels(book.Bids, 1)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse bid price")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, exchange.NewError(c.Name(), exchange.ErrBadResponse,
+			fmt.Errorf("failed to parse bid price: %w", err))
+	}
+	bidPrice := bids[0].Price
+
+	timeMs, err := strconv.ParseInt(book.Ts, 10, 64)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse timestamp")
+		span.RecordError(err)
+		return 0, 0, time.Time{}, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+	timestamp := time.Unix(0, timeMs*int64(time.Millisecond)).UTC()
+
+	c.logger.Debug("Successfully received order book data",
+		zap.String("symbol", symbol),
+		zap.Float64("ask", askPrice),
+		zap.Float64("bid", bidPrice),
+		zap.Time("timestamp", timestamp))
+
+	span.SetAttributes(
+		attribute.Float64("ask", askPrice),
+		attribute.Float64("bid", bidPrice),
+		attribute.String("timestamp", timestamp.Format(time.RFC3339)),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book data")
+
+	return askPrice, bidPrice, timestamp, nil
+}
+
+// maxDepth - максимальное число уровней стакана в ответе /api/v5/market/books
+const maxDepth = 400
+
+// GetOrderBookDepth возвращает до depth уровней стакана
+func (c *OKXClient) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	ctx, span := c.tracer.Start(ctx, "OKX.GetOrderBookDepth",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.Int("depth", depth),
+		))
+	defer span.End()
+
+	if depth <= 0 || depth > maxDepth {
+		depth = maxDepth
+	}
+
+	response, err := c.requestOrderBook(ctx, span, symbol, depth)
+	if err != nil {
+		return nil, err
+	}
+	book := response.Data[0]
+
+	asks, err := exchange.ParseLevels(book.Asks, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse asks")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse asks: %w", err))
+	}
+
+	bids, err := exchange.ParseLevels(book.Bids, depth)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse bids")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to parse bids: %w", err))
+	}
+
+	timeMs, err := strconv.ParseInt(book.Ts, 10, 64)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to parse timestamp")
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to parse timestamp: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("asks", len(asks)),
+		attribute.Int("bids", len(bids)),
+		attribute.Int64("sequence", book.SeqID),
+	)
+	span.SetStatus(codes.Ok, "Successfully received order book depth")
+
+	return &model.OrderBook{
+		Symbol:    symbol,
+		Exchange:  c.Name(),
+		Sequence:  book.SeqID,
+		Asks:      asks,
+		Bids:      bids,
+		Timestamp: time.Unix(0, timeMs*int64(time.Millisecond)).UTC(),
+	}, nil
+}
+
+// Коды ошибок OKX API, которые классифицируются отдельно
+const (
+	codeInstrumentNotFound = "51001"
+	codeRateLimited        = "50011"
+)
+
+// apiError классифицирует ошибку OKX, переданную кодом в теле ответа
+func (c *OKXClient) apiError(code, msg string) *exchange.Error {
+	err := fmt.Errorf("okx error %s: %s", code, msg)
+	switch code {
+	case codeInstrumentNotFound:
+		return exchange.NewError(c.Name(), exchange.ErrSymbolNotFound, err)
+	case codeRateLimited:
+		return exchange.NewError(c.Name(), exchange.ErrRateLimited, err)
+	default:
+		return exchange.NewError(c.Name(), exchange.ErrBadResponse, err)
+	}
+}
+
+// requestOrderBook запрашивает size уровней стакана и проверяет, что обе его стороны не пустые
+func (c *OKXClient) requestOrderBook(ctx context.Context, span trace.Span, symbol string, size int) (*OrderBookResponse, error) {
+	query := url.Values{}
+	query.Set("instId", ToExchangeSymbol(symbol))
+	query.Set("sz", strconv.Itoa(size))
+	requestURL := fmt.Sprintf("%s/api/v5/market/books?%s", c.baseURL, query.Encode())
+
+	c.logger.Debug("Requesting order book from OKX",
+		zap.String("url", requestURL),
+		zap.String("symbol", symbol))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", requestURL))
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return nil, exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := exchange.StatusError(c.Name(), resp)
+		c.logger.Error("Unexpected status code",
+			zap.Int("status_code", resp.StatusCode),
+			zap.String("url", requestURL))
+
+		span.SetStatus(codes.Error, statusErr.Error())
+		return nil, statusErr
+	}
+
+	var response OrderBookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.logger.Error("Failed to decode response", zap.Error(err))
+		span.SetStatus(codes.Error, "Failed to decode response")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
+	}
+
+	// OKX возвращает ошибки с HTTP 200 и ненулевым кодом в теле
+	if response.Code != codeOK {
+		apiErr := c.apiError(response.Code, response.Msg)
+		span.SetStatus(codes.Error, apiErr.Error())
+		return nil, apiErr
+	}
+
+	if len(response.Data) == 0 || len(response.Data[0].Asks) == 0 || len(response.Data[0].Bids) == 0 {
+		errMsg := "empty order book data"
+		span.SetStatus(codes.Error, errMsg)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("%s", errMsg))
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,150 @@
+package okx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// Вспомогательная функция для создания тестового сервера и клиента
+func setupTestServerAndClient(handler func(w http.ResponseWriter, r *http.Request)) (*OKXClient, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	client := NewOKXClient(server.URL, zap.NewNop())
+	return client, server
+}
+
+func TestGetOrderBook_Success(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v5/market/books", r.URL.Path)
+		assert.Equal(t, "BTC-USDT", r.URL.Query().Get("instId"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"code": "0",
+			"msg": "",
+			"data": [{
+				"asks": [["40001.0", "0.8", "0", "1"]],
+				"bids": [["40000.0", "1.0", "0", "2"]],
+				"ts": "1617267321123"
+			}]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	ask, bid, timestamp, err := client.GetOrderBook(context.Background(), "btc-usdt")
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, 40001.0, ask)
+	assert.Equal(t, 40000.0, bid)
+	assert.Equal(t, time.Unix(0, 1617267321123*int64(time.Millisecond)).UTC(), timestamp)
+}
+
+func TestGetOrderBook_APIError(t *testing.T) {
+	// OKX сообщает об ошибке кодом в теле ответа при HTTP 200
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"code": "51001", "msg": "Instrument ID does not exist", "data": []}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "FOO-BAR")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "okx error 51001")
+}
+
+func TestGetOrderBook_EmptyData(t *testing.T) {
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"code": "0", "msg": "", "data": [{"asks": [], "bids": [], "ts": "1"}]}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "empty order book data")
+}
+
+func TestGetOrderBook_MalformedLevel(t *testing.T) {
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`{"code": "0", "msg": "", "data": [{"asks": [["40001", "1"]], "bids": [[]], "ts": "1"}]}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	_, _, _, err := client.GetOrderBook(context.Background(), "BTC-USDT")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse bid price")
+}
+
+func TestGetOrderBookDepth(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("sz"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"code": "0",
+			"msg": "",
+			"data": [{
+				"asks": [["40001.0", "0.8", "0", "1"]],
+				"bids": [["40000.0", "1.0", "0", "2"]],
+				"ts": "1617267321123",
+				"seqId": 3366
+			}]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	book, err := client.GetOrderBookDepth(context.Background(), "BTC-USDT", 1)
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, "okx", book.Exchange)
+	assert.Equal(t, int64(3366), book.Sequence)
+	assert.Equal(t, []model.PriceLevel{{Price: 40001, Size: 0.8}}, book.Asks)
+	assert.Equal(t, []model.PriceLevel{{Price: 40000, Size: 1}}, book.Bids)
+	assert.Equal(t, time.Unix(0, 1617267321123*int64(time.Millisecond)).UTC(), book.Timestamp)
+}
+
+func TestListSymbols(t *testing.T) {
+	// Создаем тестовый сервер
+	client, server := setupTestServerAndClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v5/public/instruments", r.URL.Path)
+		assert.Equal(t, "SPOT", r.URL.Query().Get("instType"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+			"code": "0",
+			"msg": "",
+			"data": [
+				{"instId": "SOL-EUR", "baseCcy": "SOL", "quoteCcy": "EUR", "state": "live"},
+				{"instId": "NEW-USDT", "baseCcy": "NEW", "quoteCcy": "USDT", "state": "preopen"}
+			]
+		}`))
+		assert.NoError(t, err)
+	})
+	defer server.Close()
+
+	// Выполняем запрос
+	symbols, err := client.ListSymbols(context.Background())
+
+	// Проверяем результаты
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Symbol{{Symbol: "SOL-EUR", Base: "SOL", Quote: "EUR"}}, symbols)
+}
@@ -0,0 +1,90 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// stateLive - состояние инструмента, по которому разрешена торговля
+const stateLive = "live"
+
+type InstrumentsResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		InstID   string `json:"instId"`
+		BaseCcy  string `json:"baseCcy"`
+		QuoteCcy string `json:"quoteCcy"`
+		State    string `json:"state"`
+	} `json:"data"`
+}
+
+// ListSymbols возвращает спотовые пары, по которым на OKX разрешена торговля
+func (c *OKXClient) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	ctx, span := c.tracer.Start(ctx, "OKX.ListSymbols")
+	defer span.End()
+
+	requestURL := fmt.Sprintf("%s/api/v5/public/instruments?instType=SPOT", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to create request")
+		span.RecordError(err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Error("Failed to make request", zap.Error(err), zap.String("url", requestURL))
+		span.SetStatus(codes.Error, "Failed to make HTTP request")
+		span.RecordError(err)
+		return nil, exchange.RequestError(c.Name(), err)
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		statusErr := exchange.StatusError(c.Name(), resp)
+		span.SetStatus(codes.Error, statusErr.Error())
+		return nil, statusErr
+	}
+
+	var response InstrumentsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		span.SetStatus(codes.Error, "Failed to decode response")
+		span.RecordError(err)
+		return nil, exchange.NewError(c.Name(), exchange.ErrBadResponse, fmt.Errorf("failed to decode response: %w", err))
+	}
+
+	if response.Code != codeOK {
+		apiErr := c.apiError(response.Code, response.Msg)
+		span.SetStatus(codes.Error, apiErr.Error())
+		return nil, apiErr
+	}
+
+	symbols := make([]model.Symbol, 0, len(response.Data))
+	for _, item := range response.Data {
+		if item.State != stateLive {
+			continue
+		}
+		symbols = append(symbols, model.Symbol{
+			Symbol: item.InstID,
+			Base:   item.BaseCcy,
+			Quote:  item.QuoteCcy,
+		})
+	}
+
+	span.SetAttributes(attribute.Int("count", len(symbols)))
+	span.SetStatus(codes.Ok, "Symbols received")
+
+	return symbols, nil
+}
@@ -0,0 +1,84 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy - повтор идемпотентных запросов к бирже с экспоненциальной
+// задержкой и случайным разбросом. Нулевое значение - запрос без повторов.
+type RetryPolicy struct {
+	// MaxAttempts - число попыток вместе с первой
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay ограничивает задержку перед повтором. Если биржа просит
+	// подождать дольше (Retry-After), запрос не повторяется.
+	MaxDelay time.Duration
+}
+
+// Retryable сообщает, может ли повтор запроса дать другой результат
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrTimeout) || errors.Is(err, ErrRateLimited)
+}
+
+// Do выполняет attempt, повторяя его при временных ошибках. Перед повтором
+// ждет случайное время до BaseDelay*2^n (не больше MaxDelay), но не меньше
+// Retry-After биржи. Возвращает ошибку последней попытки.
+func (p RetryPolicy) Do(ctx context.Context, attempt func(ctx context.Context) error) error {
+	var err error
+	for n := 0; ; n++ {
+		err = attempt(ctx)
+		if err == nil || !Retryable(err) || n+1 >= p.MaxAttempts {
+			return err
+		}
+
+		delay, ok := p.delay(n, err)
+		if !ok {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay возвращает задержку перед повтором после попытки n (с нуля).
+// false - биржа просит подождать дольше MaxDelay.
+func (p RetryPolicy) delay(n int, err error) (time.Duration, bool) {
+	backoff := p.BaseDelay
+	for i := 0; i < n && backoff > 0 && backoff <= math.MaxInt64/2; i++ {
+		backoff *= 2
+	}
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+
+	var delay time.Duration
+	if backoff > 0 {
+		delay = time.Duration(rand.Int63n(int64(backoff)))
+	}
+
+	if retryAfter, ok := RetryAfter(err); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return 0, false
+		}
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+	return delay, true
+}
@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Do(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "success after transient failures",
+			errs:         []error{NewError("kucoin", ErrUnavailable, nil), NewError("kucoin", ErrTimeout, nil), nil},
+			wantAttempts: 3,
+		},
+		{
+			name:         "attempts exhausted",
+			errs:         []error{NewError("kucoin", ErrUnavailable, nil), NewError("kucoin", ErrUnavailable, nil), NewError("kucoin", ErrUnavailable, nil)},
+			wantAttempts: 3,
+			wantErr:      ErrUnavailable,
+		},
+		{
+			name:         "not retryable",
+			errs:         []error{NewError("kucoin", ErrSymbolNotFound, nil)},
+			wantAttempts: 1,
+			wantErr:      ErrSymbolNotFound,
+		},
+		{
+			name:         "canceled",
+			errs:         []error{context.Canceled},
+			wantAttempts: 1,
+			wantErr:      context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+
+			err := policy.Do(context.Background(), func(context.Context) error {
+				err := tt.errs[attempts]
+				attempts++
+				return err
+			})
+
+			assert.Equal(t, tt.wantAttempts, attempts)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRetryPolicy_ZeroValueDoesNotRetry(t *testing.T) {
+	attempts := 0
+
+	err := RetryPolicy{}.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return NewError("kucoin", ErrUnavailable, nil)
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryPolicy_RespectsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	t.Run("waits for retry after", func(t *testing.T) {
+		rateLimited := NewError("kucoin", ErrRateLimited, nil)
+		rateLimited.RetryAfter = 50 * time.Millisecond
+
+		attempts := 0
+		start := time.Now()
+		err := policy.Do(context.Background(), func(context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return rateLimited
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("gives up when retry after exceeds max delay", func(t *testing.T) {
+		rateLimited := NewError("kucoin", ErrRateLimited, nil)
+		rateLimited.RetryAfter = time.Minute
+
+		attempts := 0
+		err := policy.Do(context.Background(), func(context.Context) error {
+			attempts++
+			return rateLimited
+		})
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestRetryPolicy_StopsAtDeadline(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	err := policy.Do(ctx, func(context.Context) error {
+		attempts++
+		return NewError("kucoin", ErrUnavailable, errors.New("502"))
+	})
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Less(t, attempts, 5)
+}
+
+func TestRetryPolicy_DelayBounds(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+	err := NewError("kucoin", ErrUnavailable, nil)
+
+	for n := 0; n < 10; n++ {
+		delay, ok := policy.delay(n, err)
+
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, 40*time.Millisecond)
+	}
+}
@@ -0,0 +1,124 @@
+// Package gateway обслуживает REST/JSON-фасад RateService (grpc-gateway) и его описание OpenAPI
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
+)
+
+// OpenAPIPath - адрес описания REST-шлюза в формате OpenAPI
+const OpenAPIPath = "/openapi.json"
+
+// Config содержит настройки REST-шлюза
+type Config struct {
+	// Addr - адрес HTTP-сервера шлюза
+	Addr string
+	// GRPCEndpoint - адрес gRPC-сервера, которому шлюз передает запросы. Запросы
+	// идут через сервер, чтобы к ним применялись аутентификация, квоты и телеметрия.
+	GRPCEndpoint string
+	// TLS - настройки подключения к gRPC-серверу; nil - без TLS
+	TLS *tls.Config
+	// ForwardHeaders - HTTP-заголовки, передаваемые в метаданные gRPC как есть
+	// (например, x-api-key). Authorization передается всегда.
+	ForwardHeaders []string
+	// DialOptions - дополнительные настройки подключения к gRPC-серверу
+	DialOptions []grpc.DialOption
+}
+
+// Server - HTTP-сервер REST-шлюза
+type Server struct {
+	config Config
+	logger *zap.Logger
+	conn   *grpc.ClientConn
+	server *http.Server
+}
+
+// NewServer подключается к gRPC-серверу и регистрирует маршруты шлюза
+func NewServer(ctx context.Context, logger *zap.Logger, config Config) (*Server, error) {
+	creds := insecure.NewCredentials()
+	if config.TLS != nil {
+		creds = credentials.NewTLS(config.TLS)
+	}
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, config.DialOptions...)
+
+	conn, err := grpc.NewClient(config.GRPCEndpoint, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gateway gRPC client: %w", err)
+	}
+
+	gatewayMux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher(config.ForwardHeaders)))
+	if err := pb.RegisterRateServiceHandler(ctx, gatewayMux, conn); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to register gateway handlers: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(OpenAPIPath, serveOpenAPI)
+	mux.Handle("/", gatewayMux)
+
+	return &Server{
+		config: config,
+		logger: logger,
+		conn:   conn,
+		server: &http.Server{
+			Addr:              config.Addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       30 * time.Second,
+			// WriteTimeout не задается: SubscribeRates отдает ответ потоком
+		},
+	}, nil
+}
+
+// Start запускает HTTP-сервер шлюза в отдельной горутине
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("Starting REST gateway", zap.String("addr", s.config.Addr))
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Failed to start REST gateway", zap.Error(err))
+		}
+	}()
+}
+
+// Shutdown дожидается завершения текущих запросов и закрывает соединение с gRPC-сервером
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	if closeErr := s.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// headerMatcher передает заголовки из ForwardHeaders, остальные - по правилам grpc-gateway
+func headerMatcher(forward []string) runtime.HeaderMatcherFunc {
+	return func(key string) (string, bool) {
+		for _, header := range forward {
+			if strings.EqualFold(key, header) {
+				return strings.ToLower(header), true
+			}
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(pb.OpenAPI)
+}
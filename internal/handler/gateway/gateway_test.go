@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
+)
+
+// fakeRateService запоминает последний запрос GetRates и его метаданные
+type fakeRateService struct {
+	pb.UnimplementedRateServiceServer
+	request  *pb.GetRatesRequest
+	metadata metadata.MD
+}
+
+func (f *fakeRateService) GetRates(ctx context.Context, req *pb.GetRatesRequest) (*pb.GetRatesResponse, error) {
+	f.request = req
+	f.metadata, _ = metadata.FromIncomingContext(ctx)
+	if req.Symbol == "UNKNOWN-USDT" {
+		return nil, status.Error(codes.NotFound, "symbol not found")
+	}
+	return &pb.GetRatesResponse{Ask: 101, Bid: 99, Source: pb.RateSource_RATE_SOURCE_EXCHANGE}, nil
+}
+
+func newTestGateway(t *testing.T) (*Server, *fakeRateService) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	service := &fakeRateService{}
+	grpcServer := grpc.NewServer()
+	pb.RegisterRateServiceServer(grpcServer, service)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	gateway, err := NewServer(context.Background(), zap.NewNop(), Config{
+		GRPCEndpoint:   listener.Addr().String(),
+		ForwardHeaders: []string{"x-api-key"},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = gateway.conn.Close() })
+
+	return gateway, service
+}
+
+func TestGateway_GetRates(t *testing.T) {
+	// Arrange
+	gateway, service := newTestGateway(t)
+	req := httptest.NewRequest(http.MethodGet, "/v1/rates/BTC-USDT?notional=1000", nil)
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+
+	// Act
+	gateway.server.Handler.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 101.0, body["ask"])
+	assert.Equal(t, "RATE_SOURCE_EXCHANGE", body["source"])
+
+	assert.Equal(t, "BTC-USDT", service.request.Symbol)
+	assert.Equal(t, 1000.0, service.request.Notional)
+	assert.Equal(t, []string{"secret"}, service.metadata.Get("x-api-key"))
+	assert.Equal(t, []string{"Bearer token"}, service.metadata.Get("authorization"))
+}
+
+func TestGateway_ErrorStatus(t *testing.T) {
+	gateway, _ := newTestGateway(t)
+	rec := httptest.NewRecorder()
+
+	gateway.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/rates/UNKNOWN-USDT", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "symbol not found")
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	gateway, _ := newTestGateway(t)
+	rec := httptest.NewRecorder()
+
+	gateway.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var spec struct {
+		Paths map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Contains(t, spec.Paths, "/v1/rates/{symbol}")
+}
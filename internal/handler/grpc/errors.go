@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
+)
+
+// errorDomain - домен причин ошибок в ErrorInfo
+const errorDomain = "rate-service"
+
+// errorClass описывает, каким gRPC-кодом и причиной ErrorInfo отвечать на вид ошибки
+type errorClass struct {
+	kind   error
+	code   codes.Code
+	reason string
+}
+
+// errorClasses проверяются по порядку: ошибка Failover объединяет ответы нескольких
+// бирж, и временные отказы важнее того, что какая-то из бирж не знает символ
+var errorClasses = []errorClass{
+	{kind: exchange.ErrRateLimited, code: codes.ResourceExhausted, reason: "UPSTREAM_RATE_LIMITED"},
+	{kind: exchange.ErrTimeout, code: codes.DeadlineExceeded, reason: "UPSTREAM_TIMEOUT"},
+	{kind: exchange.ErrUnavailable, code: codes.Unavailable, reason: "UPSTREAM_UNAVAILABLE"},
+	{kind: exchange.ErrBadResponse, code: codes.Unavailable, reason: "UPSTREAM_BAD_RESPONSE"},
+	{kind: exchange.ErrSymbolNotFound, code: codes.NotFound, reason: "SYMBOL_NOT_FOUND"},
+	{kind: repository.ErrTimeout, code: codes.DeadlineExceeded, reason: "STORAGE_TIMEOUT"},
+	{kind: repository.ErrUnavailable, code: codes.Unavailable, reason: "STORAGE_UNAVAILABLE"},
+	{kind: repository.ErrNotFound, code: codes.NotFound, reason: "NOT_FOUND"},
+	{kind: service.ErrServiceClosed, code: codes.Unavailable, reason: "SERVICE_SHUTTING_DOWN"},
+}
+
+// errorStatus переводит ошибку сервиса в gRPC-статус с ErrorInfo и, если биржа
+// сообщила время повтора, RetryInfo. message - описание неудавшейся операции;
+// текст исходной ошибки клиенту не передается.
+func errorStatus(err error, message string) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	for _, class := range errorClasses {
+		if !errors.Is(err, class.kind) {
+			continue
+		}
+
+		info := &errdetails.ErrorInfo{Reason: class.reason, Domain: errorDomain}
+		if exchangeErr, ok := exchange.Find(err, class.kind); ok {
+			info.Metadata = map[string]string{"exchange": exchangeErr.Exchange}
+		}
+		details := []protoadapt.MessageV1{info}
+		if retryAfter, ok := exchange.RetryAfter(err); ok && class.code != codes.NotFound {
+			details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+		}
+
+		st := status.New(class.code, message+": "+class.kind.Error())
+		if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+			return withDetails
+		}
+		return st
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+	return status.New(codes.Internal, message)
+}
@@ -0,0 +1,180 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
+	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
+)
+
+// errorDetails извлекает ErrorInfo и RetryInfo из статуса
+func errorDetails(st *status.Status) (*errdetails.ErrorInfo, *errdetails.RetryInfo) {
+	var info *errdetails.ErrorInfo
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+	return info, retry
+}
+
+func TestErrorStatus(t *testing.T) {
+	rateLimited := exchange.NewError("kucoin", exchange.ErrRateLimited, errors.New("unexpected status code: 429"))
+	rateLimited.RetryAfter = 2 * time.Second
+
+	tests := []struct {
+		name           string
+		err            error
+		wantCode       codes.Code
+		wantReason     string
+		wantExchange   string
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:         "symbol not found",
+			err:          exchange.NewError("kucoin", exchange.ErrSymbolNotFound, nil),
+			wantCode:     codes.NotFound,
+			wantReason:   "SYMBOL_NOT_FOUND",
+			wantExchange: "kucoin",
+		},
+		{
+			name:           "rate limited",
+			err:            fmt.Errorf("all exchanges failed: %w", rateLimited),
+			wantCode:       codes.ResourceExhausted,
+			wantReason:     "UPSTREAM_RATE_LIMITED",
+			wantExchange:   "kucoin",
+			wantRetryAfter: 2 * time.Second,
+		},
+		{
+			name:         "timeout",
+			err:          exchange.RequestError("okx", context.DeadlineExceeded),
+			wantCode:     codes.DeadlineExceeded,
+			wantReason:   "UPSTREAM_TIMEOUT",
+			wantExchange: "okx",
+		},
+		{
+			name:         "bad response",
+			err:          exchange.NewError("binance", exchange.ErrBadResponse, errors.New("failed to decode response")),
+			wantCode:     codes.Unavailable,
+			wantReason:   "UPSTREAM_BAD_RESPONSE",
+			wantExchange: "binance",
+		},
+		{
+			name: "failover prefers transient failure",
+			err: errors.Join(
+				exchange.NewError("kucoin", exchange.ErrSymbolNotFound, nil),
+				exchange.NewError("okx", exchange.ErrUnavailable, nil),
+			),
+			wantCode:     codes.Unavailable,
+			wantReason:   "UPSTREAM_UNAVAILABLE",
+			wantExchange: "okx",
+		},
+		{
+			name:       "storage unavailable",
+			err:        fmt.Errorf("failed to get rate history: %w", repository.ErrUnavailable),
+			wantCode:   codes.Unavailable,
+			wantReason: "STORAGE_UNAVAILABLE",
+		},
+		{
+			name:       "service closed",
+			err:        service.ErrServiceClosed,
+			wantCode:   codes.Unavailable,
+			wantReason: "SERVICE_SHUTTING_DOWN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := errorStatus(tt.err, "failed to get rates")
+
+			assert.Equal(t, tt.wantCode, st.Code())
+			info, retry := errorDetails(st)
+			if assert.NotNil(t, info) {
+				assert.Equal(t, tt.wantReason, info.Reason)
+				assert.Equal(t, errorDomain, info.Domain)
+				assert.Equal(t, tt.wantExchange, info.Metadata["exchange"])
+			}
+			if tt.wantRetryAfter == 0 {
+				assert.Nil(t, retry)
+				return
+			}
+			if assert.NotNil(t, retry) {
+				assert.Equal(t, tt.wantRetryAfter, retry.RetryDelay.AsDuration())
+			}
+		})
+	}
+}
+
+func TestErrorStatus_HidesUnclassifiedError(t *testing.T) {
+	st := errorStatus(errors.New("pq: password authentication failed"), "failed to get candles")
+
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, "failed to get candles", st.Message())
+	assert.Empty(t, st.Details())
+}
+
+func TestErrorStatus_ContextError(t *testing.T) {
+	st := errorStatus(fmt.Errorf("failed to make request: %w", context.Canceled), "failed to get rates")
+
+	assert.Equal(t, codes.Canceled, st.Code())
+}
+
+func TestGetRates_ExchangeErrorCodes(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetRates", ctx, "FOO-BAR").
+		Return(model.Quote{}, exchange.NewError("kucoin", exchange.ErrSymbolNotFound, nil))
+
+	// Act
+	_, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: "FOO-BAR"})
+
+	// Assert
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRatesBatch_RateLimitedSymbol(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	rateLimited := exchange.NewError("kucoin", exchange.ErrRateLimited, nil)
+	rateLimited.RetryAfter = time.Second
+
+	ctx := context.Background()
+	mockService.On("GetRatesBatch", ctx, []string{"BTC-USDT"}).Return([]service.QuoteResult{
+		{Symbol: "BTC-USDT", Err: rateLimited},
+	})
+
+	// Act
+	resp, err := server.GetRatesBatch(ctx, &pb.GetRatesBatchRequest{Symbols: []string{"BTC-USDT"}})
+
+	// Assert
+	assert.NoError(t, err)
+	st := status.FromProto(resp.Results[0].GetError())
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	_, retry := errorDetails(st)
+	if assert.NotNil(t, retry) {
+		assert.Equal(t, time.Second, retry.RetryDelay.AsDuration())
+	}
+	mockService.AssertExpectations(t)
+}
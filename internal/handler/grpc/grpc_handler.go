@@ -2,54 +2,603 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"math"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/catalog"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/health"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/pricing"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
 	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
 )
 
+const (
+	// maxSubscribeSymbols - максимальное число символов в одной подписке
+	maxSubscribeSymbols = 50
+	// maxBatchSymbols - максимальное число символов в одном GetRatesBatch
+	maxBatchSymbols = 100
+
+	defaultOrderBookDepth = 20
+	maxOrderBookDepth     = 100
+
+	defaultHistoryPageSize = 100
+	maxHistoryPageSize     = 1000
+
+	// maxCandles - максимальное число свечей, которое может покрыть один запрос
+	maxCandles = 5000
+)
+
+// candleIntervals - длительности поддерживаемых интервалов свечей
+var candleIntervals = map[pb.CandleInterval]time.Duration{
+	pb.CandleInterval_CANDLE_INTERVAL_1M: time.Minute,
+	pb.CandleInterval_CANDLE_INTERVAL_5M: 5 * time.Minute,
+	pb.CandleInterval_CANDLE_INTERVAL_1H: time.Hour,
+	pb.CandleInterval_CANDLE_INTERVAL_1D: 24 * time.Hour,
+}
+
 // RateServiceInterface - интерфейс для сервиса ставок, для облегчения тестирования
 type RateServiceInterface interface {
-	GetRates(ctx context.Context, symbol string) (float64, float64, time.Time, error)
-	HealthCheck(ctx context.Context) bool
+	GetRates(ctx context.Context, symbol string) (model.Quote, error)
+	GetRatesWithBook(ctx context.Context, symbol string) (model.Quote, *model.OrderBook, error)
+	GetRatesBatch(ctx context.Context, symbols []string) []service.QuoteResult
+	GetOrderBook(ctx context.Context, symbol string, depth int) (*model.OrderBook, error)
+	SubscribeRates(ctx context.Context, symbols []string) (<-chan model.Rate, error)
+	GetRateHistory(
+		ctx context.Context,
+		symbol string,
+		from, to time.Time,
+		pageSize int,
+		after *model.RateCursor,
+	) ([]model.Rate, *model.RateCursor, error)
+	GetCandles(ctx context.Context, symbol string, interval time.Duration, from, to time.Time) ([]model.Candle, error)
+}
+
+// SymbolCatalog - справочник торгуемых пар, по которому проверяются символы запросов
+type SymbolCatalog interface {
+	// Resolve нормализует символ и проверяет, что курс по нему можно получить
+	Resolve(symbol string) (string, error)
+	// Normalize только нормализует символ, не проверяя наличие пары
+	Normalize(symbol string) (string, error)
+	Symbols() []model.Symbol
+	UpdatedAt() time.Time
+}
+
+// HealthReporter отдает результаты последней проверки зависимостей сервиса
+type HealthReporter interface {
+	Status() (bool, []health.ComponentStatus)
 }
+
 type RateServiceServer struct {
 	pb.UnimplementedRateServiceServer
 	logger      *zap.Logger
 	rateService RateServiceInterface
+	catalog     SymbolCatalog
+	health      HealthReporter
+}
+
+// Option - дополнительная настройка RateServiceServer
+type Option func(*RateServiceServer)
+
+// WithSymbolCatalog включает проверку и нормализацию символов по справочнику.
+// Без справочника символы передаются в сервис как есть.
+func WithSymbolCatalog(catalog SymbolCatalog) Option {
+	return func(s *RateServiceServer) {
+		s.catalog = catalog
+	}
 }
 
-func NewRateServiceServer(logger *zap.Logger, rateService RateServiceInterface) *RateServiceServer {
-	return &RateServiceServer{
+// WithHealthReporter включает состояние зависимостей в ответ HealthCheck
+func WithHealthReporter(reporter HealthReporter) Option {
+	return func(s *RateServiceServer) {
+		s.health = reporter
+	}
+}
+
+func NewRateServiceServer(logger *zap.Logger, rateService RateServiceInterface, opts ...Option) *RateServiceServer {
+	s := &RateServiceServer{
 		logger:      logger,
 		rateService: rateService,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *RateServiceServer) GetRates(ctx context.Context, req *pb.GetRatesRequest) (*pb.GetRatesResponse, error) {
-	if req.Symbol == "" {
-		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	symbol, err := s.resolveSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Notional < 0 || math.IsNaN(req.Notional) || math.IsInf(req.Notional, 0) {
+		return nil, status.Error(codes.InvalidArgument, "notional must be a non-negative number")
+	}
+
+	if req.Notional == 0 {
+		quote, err := s.rateService.GetRates(ctx, symbol)
+		if err != nil {
+			s.logger.Error("Failed to get rates", zap.Error(err), zap.String("symbol", symbol))
+			return nil, errorStatus(err, "failed to get rates").Err()
+		}
+		return toProtoRate(quote), nil
+	}
+
+	// Курс и VWAP считаются по одному снимку стакана
+	quote, book, err := s.rateService.GetRatesWithBook(ctx, symbol)
+	if err != nil {
+		s.logger.Error("Failed to get rates", zap.Error(err), zap.String("symbol", symbol))
+		return nil, errorStatus(err, "failed to get rates").Err()
+	}
+
+	response := toProtoRate(quote)
+	// Для сохраненного курса стакана нет, отдаем курс без VWAP
+	if book != nil {
+		response.BuyVwap = toProtoVWAP(pricing.WalkBook(book.Asks, req.Notional))
+		response.SellVwap = toProtoVWAP(pricing.WalkBook(book.Bids, req.Notional))
+	}
+
+	return response, nil
+}
+
+func (s *RateServiceServer) GetRatesBatch(ctx context.Context, req *pb.GetRatesBatchRequest) (*pb.GetRatesBatchResponse, error) {
+	symbols, err := uniqueSymbols(req.Symbols, maxBatchSymbols)
+	if err != nil {
+		return nil, err
+	}
+
+	// Неизвестный символ - ошибка только этого символа, остальные запрашиваются
+	entries := make([]service.QuoteResult, 0, len(symbols))
+	valid := make([]string, 0, len(symbols))
+	seen := make(map[string]struct{}, len(symbols))
+	for _, symbol := range symbols {
+		resolved, err := s.resolveSymbol(symbol)
+		if err != nil {
+			entries = append(entries, service.QuoteResult{Symbol: symbol, Err: err})
+			continue
+		}
+		if _, ok := seen[resolved]; ok {
+			continue
+		}
+		seen[resolved] = struct{}{}
+		entries = append(entries, service.QuoteResult{Symbol: resolved})
+		valid = append(valid, resolved)
+	}
+
+	fetched := make(map[string]service.QuoteResult, len(valid))
+	if len(valid) > 0 {
+		for _, result := range s.rateService.GetRatesBatch(ctx, valid) {
+			fetched[result.Symbol] = result
+		}
+	}
+
+	response := &pb.GetRatesBatchResponse{
+		Results: make([]*pb.RateResult, 0, len(entries)),
 	}
+	for _, result := range entries {
+		if result.Err == nil {
+			result = fetched[result.Symbol]
+		}
+		if result.Err != nil {
+			s.logger.Warn("Failed to get rate in batch", zap.Error(result.Err), zap.String("symbol", result.Symbol))
+			response.Results = append(response.Results, &pb.RateResult{
+				Symbol: result.Symbol,
+				Result: &pb.RateResult_Error{Error: errorStatus(result.Err, "failed to get rates").Proto()},
+			})
+			continue
+		}
 
-	ask, bid, timestamp, err := s.rateService.GetRates(ctx, req.Symbol)
+		response.Results = append(response.Results, &pb.RateResult{
+			Symbol: result.Symbol,
+			Result: &pb.RateResult_Rate{Rate: toProtoRate(result.Quote)},
+		})
+	}
+
+	return response, nil
+}
+
+func (s *RateServiceServer) GetOrderBook(ctx context.Context, req *pb.GetOrderBookRequest) (*pb.GetOrderBookResponse, error) {
+	symbol, err := s.resolveSymbol(req.Symbol)
 	if err != nil {
-		s.logger.Error("Failed to get rates", zap.Error(err), zap.String("symbol", req.Symbol))
-		return nil, status.Error(codes.Internal, "failed to get rates")
+		return nil, err
+	}
+
+	depth := int(req.Depth)
+	switch {
+	case depth < 0 || depth > maxOrderBookDepth:
+		return nil, status.Errorf(codes.InvalidArgument, "depth must be between 1 and %d", maxOrderBookDepth)
+	case depth == 0:
+		depth = defaultOrderBookDepth
 	}
 
+	book, err := s.rateService.GetOrderBook(ctx, symbol, depth)
+	if err != nil {
+		s.logger.Error("Failed to get order book", zap.Error(err), zap.String("symbol", symbol))
+		return nil, errorStatus(err, "failed to get order book").Err()
+	}
+
+	return &pb.GetOrderBookResponse{
+		Symbol:    symbol,
+		Exchange:  book.Exchange,
+		Sequence:  book.Sequence,
+		Timestamp: timestamppb.New(book.Timestamp),
+		Asks:      toProtoPriceLevels(book.Asks),
+		Bids:      toProtoPriceLevels(book.Bids),
+	}, nil
+}
+
+// toProtoPriceLevels переводит уровни стакана в proto с накопленным объемом
+func toProtoPriceLevels(levels []model.PriceLevel) []*pb.PriceLevel {
+	result := make([]*pb.PriceLevel, 0, len(levels))
+	var cumulative float64
+	for _, level := range levels {
+		cumulative += level.Size
+		result = append(result, &pb.PriceLevel{
+			Price:          level.Price,
+			Size:           level.Size,
+			CumulativeSize: cumulative,
+		})
+	}
+	return result
+}
+
+func toProtoRate(quote model.Quote) *pb.GetRatesResponse {
 	return &pb.GetRatesResponse{
-		Ask:       ask,
-		Bid:       bid,
-		Timestamp: timestamppb.New(timestamp),
+		Ask:       quote.Ask,
+		Bid:       quote.Bid,
+		Timestamp: timestamppb.New(quote.Timestamp),
+		Source:    toProtoRateSource(quote.Source),
+		Stale:     quote.Stale,
+		Mid:       pricing.Mid(quote.Ask, quote.Bid),
+		Spread:    pricing.Spread(quote.Ask, quote.Bid),
+		SpreadBps: pricing.SpreadBps(quote.Ask, quote.Bid),
+		Path:      toProtoPath(quote.Path),
+	}
+}
+
+func toProtoPath(path []model.QuoteLeg) []*pb.PathLeg {
+	if len(path) == 0 {
+		return nil
+	}
+
+	legs := make([]*pb.PathLeg, 0, len(path))
+	for _, leg := range path {
+		legs = append(legs, &pb.PathLeg{Symbol: leg.Symbol, Inverted: leg.Inverted})
+	}
+	return legs
+}
+
+func toProtoVWAP(vwap pricing.VWAP) *pb.VWAP {
+	return &pb.VWAP{
+		Price:        vwap.Price,
+		BaseQuantity: vwap.BaseQuantity,
+		Notional:     vwap.Notional,
+		Complete:     vwap.Complete,
+	}
+}
+
+// toProtoRateSource переводит источник курса в значение proto
+func toProtoRateSource(source model.QuoteSource) pb.RateSource {
+	switch source {
+	case model.QuoteSourceExchange:
+		return pb.RateSource_RATE_SOURCE_EXCHANGE
+	case model.QuoteSourceStream:
+		return pb.RateSource_RATE_SOURCE_STREAM
+	case model.QuoteSourceCache:
+		return pb.RateSource_RATE_SOURCE_CACHE
+	case model.QuoteSourceStorage:
+		return pb.RateSource_RATE_SOURCE_STORAGE
+	default:
+		return pb.RateSource_RATE_SOURCE_UNSPECIFIED
+	}
+}
+
+func (s *RateServiceServer) SubscribeRates(req *pb.SubscribeRatesRequest, stream pb.RateService_SubscribeRatesServer) error {
+	symbols, err := uniqueSymbols(req.Symbols, maxSubscribeSymbols)
+	if err != nil {
+		return err
+	}
+	symbols, err = s.resolveSymbols(symbols)
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	updates, err := s.rateService.SubscribeRates(ctx, symbols)
+	if err != nil {
+		s.logger.Error("Failed to subscribe to rates", zap.Error(err), zap.Strings("symbols", symbols))
+		return errorStatus(err, "failed to subscribe to rates").Err()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case rate, ok := <-updates:
+			if !ok {
+				if ctx.Err() != nil {
+					return status.FromContextError(ctx.Err()).Err()
+				}
+				return status.Error(codes.Unavailable, "service is shutting down")
+			}
+
+			if err := stream.Send(&pb.SubscribeRatesResponse{
+				Symbol:    rate.Symbol,
+				Ask:       rate.Ask,
+				Bid:       rate.Bid,
+				Timestamp: timestamppb.New(rate.Timestamp),
+			}); err != nil {
+				s.logger.Debug("Failed to send rate update", zap.Error(err), zap.String("symbol", rate.Symbol))
+				return err
+			}
+		}
+	}
+}
+
+func (s *RateServiceServer) GetRateHistory(ctx context.Context, req *pb.GetRateHistoryRequest) (*pb.GetRateHistoryResponse, error) {
+	// История доступна и по парам, которые биржа уже сняла с торгов
+	symbol, err := s.normalizeSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if req.From == nil {
+		return nil, status.Error(codes.InvalidArgument, "from is required")
+	}
+
+	from := req.From.AsTime()
+	to := time.Now().UTC()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultHistoryPageSize
+	case pageSize > maxHistoryPageSize:
+		pageSize = maxHistoryPageSize
+	}
+
+	after, err := decodePageToken(symbol, req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	rates, next, err := s.rateService.GetRateHistory(ctx, symbol, from, to, pageSize, after)
+	if err != nil {
+		s.logger.Error("Failed to get rate history", zap.Error(err), zap.String("symbol", symbol))
+		return nil, errorStatus(err, "failed to get rate history").Err()
+	}
+
+	records := make([]*pb.RateRecord, 0, len(rates))
+	for _, rate := range rates {
+		records = append(records, &pb.RateRecord{
+			Ask:       rate.Ask,
+			Bid:       rate.Bid,
+			Timestamp: timestamppb.New(rate.Timestamp),
+		})
+	}
+
+	return &pb.GetRateHistoryResponse{
+		Symbol:        symbol,
+		Rates:         records,
+		NextPageToken: encodePageToken(symbol, next),
+	}, nil
+}
+
+func (s *RateServiceServer) GetCandles(ctx context.Context, req *pb.GetCandlesRequest) (*pb.GetCandlesResponse, error) {
+	symbol, err := s.normalizeSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	interval, ok := candleIntervals[req.Interval]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "interval is required")
+	}
+
+	if req.From == nil {
+		return nil, status.Error(codes.InvalidArgument, "from is required")
+	}
+
+	from := req.From.AsTime()
+	to := time.Now().UTC()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	if to.Sub(from)/interval > maxCandles {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"time range too large: at most %d candles per request", maxCandles)
+	}
+
+	candles, err := s.rateService.GetCandles(ctx, symbol, interval, from, to)
+	if err != nil {
+		s.logger.Error("Failed to get candles", zap.Error(err), zap.String("symbol", symbol))
+		return nil, errorStatus(err, "failed to get candles").Err()
+	}
+
+	result := make([]*pb.Candle, 0, len(candles))
+	for _, candle := range candles {
+		result = append(result, &pb.Candle{
+			OpenTime: timestamppb.New(candle.OpenTime),
+			Mid:      toProtoOHLC(candle.Mid),
+			Ask:      toProtoOHLC(candle.Ask),
+			Bid:      toProtoOHLC(candle.Bid),
+			Count:    candle.Count,
+		})
+	}
+
+	return &pb.GetCandlesResponse{
+		Symbol:   symbol,
+		Interval: req.Interval,
+		Candles:  result,
+	}, nil
+}
+
+func (s *RateServiceServer) ListSymbols(ctx context.Context, req *pb.ListSymbolsRequest) (*pb.ListSymbolsResponse, error) {
+	if s.catalog == nil {
+		return nil, status.Error(codes.Unimplemented, "symbol catalog is disabled")
+	}
+
+	updatedAt := s.catalog.UpdatedAt()
+	if updatedAt.IsZero() {
+		return nil, status.Error(codes.Unavailable, "symbol catalog is not loaded yet")
+	}
+
+	base := strings.ToUpper(strings.TrimSpace(req.BaseCurrency))
+	quote := strings.ToUpper(strings.TrimSpace(req.QuoteCurrency))
+
+	symbols := s.catalog.Symbols()
+	result := make([]*pb.SymbolInfo, 0, len(symbols))
+	for _, symbol := range symbols {
+		if (base != "" && symbol.Base != base) || (quote != "" && symbol.Quote != quote) {
+			continue
+		}
+		result = append(result, &pb.SymbolInfo{
+			Symbol:        symbol.Symbol,
+			BaseCurrency:  symbol.Base,
+			QuoteCurrency: symbol.Quote,
+		})
+	}
+
+	return &pb.ListSymbolsResponse{
+		Symbols:   result,
+		UpdatedAt: timestamppb.New(updatedAt),
 	}, nil
 }
 
+// HealthCheck возвращает результаты последней фоновой проверки зависимостей.
+// Без проверки ответ означает только то, что сервер принимает запросы.
 func (s *RateServiceServer) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
-	healthy := s.rateService.HealthCheck(ctx)
+	if s.health == nil {
+		return &pb.HealthCheckResponse{Healthy: true}, nil
+	}
+
+	healthy, statuses := s.health.Status()
+	components := make([]*pb.ComponentHealth, 0, len(statuses))
+	for _, component := range statuses {
+		components = append(components, &pb.ComponentHealth{
+			Name:      component.Name,
+			Healthy:   component.Healthy,
+			Critical:  component.Critical,
+			Error:     component.Error,
+			CheckedAt: timestamppb.New(component.CheckedAt),
+		})
+	}
+
 	return &pb.HealthCheckResponse{
-		Healthy: healthy,
+		Healthy:    healthy,
+		Components: components,
 	}, nil
 }
+
+// resolveSymbol нормализует символ и проверяет его по справочнику
+func (s *RateServiceServer) resolveSymbol(symbol string) (string, error) {
+	if s.catalog == nil {
+		return requireSymbol(symbol)
+	}
+
+	resolved, err := s.catalog.Resolve(symbol)
+	if err != nil {
+		return "", symbolErrorStatus(err)
+	}
+	return resolved, nil
+}
+
+// normalizeSymbol нормализует символ без проверки наличия пары в справочнике
+func (s *RateServiceServer) normalizeSymbol(symbol string) (string, error) {
+	if s.catalog == nil {
+		return requireSymbol(symbol)
+	}
+
+	normalized, err := s.catalog.Normalize(symbol)
+	if err != nil {
+		return "", symbolErrorStatus(err)
+	}
+	return normalized, nil
+}
+
+// resolveSymbols проверяет символы по справочнику и убирает повторы после нормализации
+func (s *RateServiceServer) resolveSymbols(symbols []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		resolved, err := s.resolveSymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[resolved]; ok {
+			continue
+		}
+		seen[resolved] = struct{}{}
+		result = append(result, resolved)
+	}
+	return result, nil
+}
+
+func requireSymbol(symbol string) (string, error) {
+	symbol = strings.TrimSpace(symbol)
+	if symbol == "" {
+		return "", status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	return symbol, nil
+}
+
+// symbolErrorStatus переводит ошибку проверки символа в gRPC-статус
+func symbolErrorStatus(err error) error {
+	if errors.Is(err, catalog.ErrUnknownSymbol) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+// uniqueSymbols проверяет список символов и убирает повторы
+func uniqueSymbols(symbols []string, maxSymbols int) ([]string, error) {
+	if len(symbols) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one symbol is required")
+	}
+
+	seen := make(map[string]struct{}, len(symbols))
+	result := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			return nil, status.Error(codes.InvalidArgument, "symbol must not be empty")
+		}
+		if _, ok := seen[symbol]; ok {
+			continue
+		}
+		seen[symbol] = struct{}{}
+		result = append(result, symbol)
+	}
+
+	if len(result) > maxSymbols {
+		return nil, status.Errorf(codes.InvalidArgument, "too many symbols: at most %d allowed", maxSymbols)
+	}
+
+	return result, nil
+}
+
+func toProtoOHLC(ohlc model.OHLC) *pb.OHLC {
+	return &pb.OHLC{
+		Open:  ohlc.Open,
+		High:  ohlc.High,
+		Low:   ohlc.Low,
+		Close: ohlc.Close,
+	}
+}
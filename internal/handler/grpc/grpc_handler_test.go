@@ -3,15 +3,20 @@ package grpc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/health"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
 	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
 )
 
@@ -19,14 +24,85 @@ type MockRateService struct {
 	mock.Mock
 }
 
-func (m *MockRateService) GetRates(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+func (m *MockRateService) GetRates(ctx context.Context, symbol string) (model.Quote, error) {
 	args := m.Called(ctx, symbol)
-	return args.Get(0).(float64), args.Get(1).(float64), args.Get(2).(time.Time), args.Error(3)
+	return args.Get(0).(model.Quote), args.Error(1)
 }
 
-func (m *MockRateService) HealthCheck(ctx context.Context) bool {
-	args := m.Called(ctx)
-	return args.Bool(0)
+func (m *MockRateService) GetRatesWithBook(ctx context.Context, symbol string) (model.Quote, *model.OrderBook, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(1) == nil {
+		return args.Get(0).(model.Quote), nil, args.Error(2)
+	}
+	return args.Get(0).(model.Quote), args.Get(1).(*model.OrderBook), args.Error(2)
+}
+
+func (m *MockRateService) GetRatesBatch(ctx context.Context, symbols []string) []service.QuoteResult {
+	args := m.Called(ctx, symbols)
+	return args.Get(0).([]service.QuoteResult)
+}
+
+func (m *MockRateService) GetOrderBook(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	args := m.Called(ctx, symbol, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OrderBook), args.Error(1)
+}
+
+func (m *MockRateService) SubscribeRates(ctx context.Context, symbols []string) (<-chan model.Rate, error) {
+	args := m.Called(ctx, symbols)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(chan model.Rate), args.Error(1)
+}
+
+func (m *MockRateService) GetRateHistory(
+	ctx context.Context,
+	symbol string,
+	from, to time.Time,
+	pageSize int,
+	after *model.RateCursor,
+) ([]model.Rate, *model.RateCursor, error) {
+	args := m.Called(ctx, symbol, from, to, pageSize, after)
+	var next *model.RateCursor
+	if args.Get(1) != nil {
+		next = args.Get(1).(*model.RateCursor)
+	}
+	if args.Get(0) == nil {
+		return nil, next, args.Error(2)
+	}
+	return args.Get(0).([]model.Rate), next, args.Error(2)
+}
+
+func (m *MockRateService) GetCandles(
+	ctx context.Context,
+	symbol string,
+	interval time.Duration,
+	from, to time.Time,
+) ([]model.Candle, error) {
+	args := m.Called(ctx, symbol, interval, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Candle), args.Error(1)
+}
+
+// Заглушка серверного потока, сохраняющая отправленные сообщения
+type mockSubscribeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.SubscribeRatesResponse
+}
+
+func (m *mockSubscribeStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockSubscribeStream) Send(resp *pb.SubscribeRatesResponse) error {
+	m.sent = append(m.sent, resp)
+	return nil
 }
 
 func TestGetRates_Success(t *testing.T) {
@@ -42,7 +118,13 @@ func TestGetRates_Success(t *testing.T) {
 	timestamp := time.Now().UTC()
 
 	// Настраиваем мок
-	mockService.On("GetRates", ctx, symbol).Return(ask, bid, timestamp, nil)
+	mockService.On("GetRates", ctx, symbol).Return(model.Quote{
+		Symbol:    symbol,
+		Ask:       ask,
+		Bid:       bid,
+		Timestamp: timestamp,
+		Source:    model.QuoteSourceExchange,
+	}, nil)
 
 	// Act
 	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: symbol})
@@ -53,6 +135,285 @@ func TestGetRates_Success(t *testing.T) {
 	assert.Equal(t, ask, resp.Ask)
 	assert.Equal(t, bid, resp.Bid)
 	assert.Equal(t, timestamppb.New(timestamp).AsTime(), resp.Timestamp.AsTime())
+	assert.Equal(t, pb.RateSource_RATE_SOURCE_EXCHANGE, resp.Source)
+	assert.False(t, resp.Stale)
+	assert.Equal(t, 40000.0, resp.Mid)
+	assert.Equal(t, 1.0, resp.Spread)
+	assert.Equal(t, 0.25, resp.SpreadBps)
+	assert.Nil(t, resp.BuyVwap)
+	assert.Nil(t, resp.SellVwap)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetOrderBook")
+}
+
+func TestGetRates_VWAP(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetRatesWithBook", ctx, "BTC-USDT").Return(model.Quote{Symbol: "BTC-USDT", Ask: 100, Bid: 99},
+		&model.OrderBook{
+			Asks: []model.PriceLevel{{Price: 100, Size: 1}, {Price: 101, Size: 2}},
+			Bids: []model.PriceLevel{{Price: 99, Size: 1}},
+		}, nil)
+
+	// Act
+	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: "BTC-USDT", Notional: 201})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, resp.Ask)
+	assert.InDelta(t, 201.0/2, resp.BuyVwap.Price, 1e-9)
+	assert.InDelta(t, 2.0, resp.BuyVwap.BaseQuantity, 1e-9)
+	assert.True(t, resp.BuyVwap.Complete)
+
+	// На продажу в стакане только 99 в котируемой валюте
+	assert.Equal(t, 99.0, resp.SellVwap.Price)
+	assert.Equal(t, 99.0, resp.SellVwap.Notional)
+	assert.False(t, resp.SellVwap.Complete)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetRates", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "GetOrderBook", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRates_VWAPStale(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetRatesWithBook", ctx, "BTC-USDT").Return(model.Quote{
+		Symbol: "BTC-USDT",
+		Ask:    101,
+		Bid:    100,
+		Source: model.QuoteSourceStorage,
+		Stale:  true,
+	}, nil, nil)
+
+	// Act
+	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: "BTC-USDT", Notional: 1000})
+
+	// Assert: биржа недоступна, курс отдается без VWAP
+	assert.NoError(t, err)
+	assert.True(t, resp.Stale)
+	assert.Nil(t, resp.BuyVwap)
+	assert.Nil(t, resp.SellVwap)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRates_InvalidNotional(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	// Act
+	resp, err := server.GetRates(context.Background(), &pb.GetRatesRequest{Symbol: "BTC-USDT", Notional: -1})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	mockService.AssertNotCalled(t, "GetRates")
+}
+
+func TestGetRates_Stale(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetRates", ctx, "BTC-USDT").Return(model.Quote{
+		Symbol:    "BTC-USDT",
+		Ask:       101,
+		Bid:       100,
+		Timestamp: time.Now().Add(-5 * time.Second),
+		Source:    model.QuoteSourceStorage,
+		Stale:     true,
+	}, nil)
+
+	// Act
+	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: "BTC-USDT"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, pb.RateSource_RATE_SOURCE_STORAGE, resp.Source)
+	assert.True(t, resp.Stale)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRates_CrossRatePath(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetRates", ctx, "ETH-BTC").Return(model.Quote{
+		Symbol:    "ETH-BTC",
+		Ask:       0.05025,
+		Bid:       0.04987531172,
+		Timestamp: time.Now(),
+		Source:    model.QuoteSourceExchange,
+		Path:      []model.QuoteLeg{{Symbol: "ETH-USDT"}, {Symbol: "BTC-USDT", Inverted: true}},
+	}, nil)
+
+	// Act
+	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: "ETH-BTC"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Path, 2)
+	assert.Equal(t, "ETH-USDT", resp.Path[0].Symbol)
+	assert.False(t, resp.Path[0].Inverted)
+	assert.Equal(t, "BTC-USDT", resp.Path[1].Symbol)
+	assert.True(t, resp.Path[1].Inverted)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRatesBatch(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	timestamp := time.Now().UTC()
+
+	// Повторяющиеся символы запрашиваются один раз
+	mockService.On("GetRatesBatch", ctx, []string{"BTC-USDT", "XXX-USDT", "ETH-USDT"}).Return([]service.QuoteResult{
+		{Symbol: "BTC-USDT", Quote: model.Quote{Ask: 101, Bid: 100, Timestamp: timestamp, Source: model.QuoteSourceExchange}},
+		{Symbol: "XXX-USDT", Err: errors.New("exchange error")},
+		{Symbol: "ETH-USDT", Err: context.DeadlineExceeded},
+	})
+
+	// Act
+	resp, err := server.GetRatesBatch(ctx, &pb.GetRatesBatchRequest{
+		Symbols: []string{"BTC-USDT", "XXX-USDT", "BTC-USDT", "ETH-USDT"},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Results, 3)
+
+	assert.Equal(t, "BTC-USDT", resp.Results[0].Symbol)
+	assert.Equal(t, 101.0, resp.Results[0].GetRate().Ask)
+	assert.Equal(t, pb.RateSource_RATE_SOURCE_EXCHANGE, resp.Results[0].GetRate().Source)
+	assert.Nil(t, resp.Results[0].GetError())
+
+	assert.Equal(t, "XXX-USDT", resp.Results[1].Symbol)
+	assert.Nil(t, resp.Results[1].GetRate())
+	assert.Equal(t, int32(codes.Internal), resp.Results[1].GetError().Code)
+
+	assert.Equal(t, int32(codes.DeadlineExceeded), resp.Results[2].GetError().Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRatesBatch_InvalidSymbols(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	tooMany := make([]string, maxBatchSymbols+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("SYM%d-USDT", i)
+	}
+
+	tests := []struct {
+		name    string
+		symbols []string
+	}{
+		{name: "no symbols", symbols: nil},
+		{name: "empty symbol", symbols: []string{"BTC-USDT", ""}},
+		{name: "too many symbols", symbols: tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			resp, err := server.GetRatesBatch(context.Background(), &pb.GetRatesBatchRequest{Symbols: tt.symbols})
+
+			// Assert
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+
+	mockService.AssertNotCalled(t, "GetRatesBatch")
+}
+
+func TestGetOrderBook(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	timestamp := time.Now().UTC()
+	mockService.On("GetOrderBook", ctx, "BTC-USDT", defaultOrderBookDepth).Return(&model.OrderBook{
+		Symbol:    "BTC-USDT",
+		Exchange:  "kucoin",
+		Sequence:  1234567890,
+		Asks:      []model.PriceLevel{{Price: 101, Size: 0.5}, {Price: 102, Size: 1.5}},
+		Bids:      []model.PriceLevel{{Price: 100, Size: 2}, {Price: 99, Size: 3}},
+		Timestamp: timestamp,
+	}, nil)
+
+	// Act
+	resp, err := server.GetOrderBook(ctx, &pb.GetOrderBookRequest{Symbol: "BTC-USDT"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "kucoin", resp.Exchange)
+	assert.Equal(t, int64(1234567890), resp.Sequence)
+	assert.Equal(t, timestamp, resp.Timestamp.AsTime())
+
+	assert.Len(t, resp.Asks, 2)
+	assert.Equal(t, 102.0, resp.Asks[1].Price)
+	assert.Equal(t, 1.5, resp.Asks[1].Size)
+	assert.Equal(t, 2.0, resp.Asks[1].CumulativeSize)
+	assert.Equal(t, 5.0, resp.Bids[1].CumulativeSize)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetOrderBook_InvalidRequest(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	tests := []struct {
+		name string
+		req  *pb.GetOrderBookRequest
+	}{
+		{name: "empty symbol", req: &pb.GetOrderBookRequest{}},
+		{name: "negative depth", req: &pb.GetOrderBookRequest{Symbol: "BTC-USDT", Depth: -1}},
+		{name: "depth too large", req: &pb.GetOrderBookRequest{Symbol: "BTC-USDT", Depth: maxOrderBookDepth + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			resp, err := server.GetOrderBook(context.Background(), tt.req)
+
+			// Assert
+			assert.Nil(t, resp)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+
+	mockService.AssertNotCalled(t, "GetOrderBook")
+}
+
+func TestGetOrderBook_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	ctx := context.Background()
+	mockService.On("GetOrderBook", ctx, "BTC-USDT", 10).Return(nil, errors.New("exchange error"))
+
+	// Act
+	resp, err := server.GetOrderBook(ctx, &pb.GetOrderBookRequest{Symbol: "BTC-USDT", Depth: 10})
+
+	// Assert
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
 	mockService.AssertExpectations(t)
 }
 
@@ -92,7 +453,7 @@ func TestGetRates_ServiceError(t *testing.T) {
 	expectedErr := errors.New("service error")
 
 	// Настраиваем мок с ошибкой
-	mockService.On("GetRates", ctx, symbol).Return(0.0, 0.0, time.Time{}, expectedErr)
+	mockService.On("GetRates", ctx, symbol).Return(model.Quote{}, expectedErr)
 
 	// Act
 	resp, err := server.GetRates(ctx, &pb.GetRatesRequest{Symbol: symbol})
@@ -110,44 +471,431 @@ func TestGetRates_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
-func TestHealthCheck_Healthy(t *testing.T) {
+func TestSubscribeRates_StreamsUpdates(t *testing.T) {
 	// Arrange
 	mockService := new(MockRateService)
 	logger := zap.NewNop()
 	server := NewRateServiceServer(logger, mockService)
 
 	ctx := context.Background()
+	stream := &mockSubscribeStream{ctx: ctx}
+	timestamp := time.Now().UTC()
 
-	// Настраиваем мок
-	mockService.On("HealthCheck", ctx).Return(true)
+	updates := make(chan model.Rate, 2)
+	updates <- model.Rate{Symbol: "BTC-USDT", Ask: 40001, Bid: 40000, Timestamp: timestamp}
+	updates <- model.Rate{Symbol: "ETH-USDT", Ask: 2001, Bid: 2000, Timestamp: timestamp}
+	close(updates)
+
+	// Повторяющиеся символы должны схлопываться в одну подписку
+	mockService.On("SubscribeRates", ctx, []string{"BTC-USDT", "ETH-USDT"}).Return(updates, nil)
 
 	// Act
-	resp, err := server.HealthCheck(ctx, &pb.HealthCheckRequest{})
+	err := server.SubscribeRates(&pb.SubscribeRatesRequest{
+		Symbols: []string{"BTC-USDT", "ETH-USDT", "BTC-USDT"},
+	}, stream)
+
+	// Assert: закрытие канала сервисом означает остановку сервера
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+
+	assert.Len(t, stream.sent, 2)
+	assert.Equal(t, "BTC-USDT", stream.sent[0].Symbol)
+	assert.Equal(t, 40001.0, stream.sent[0].Ask)
+	assert.Equal(t, 40000.0, stream.sent[0].Bid)
+	assert.Equal(t, timestamp, stream.sent[0].Timestamp.AsTime())
+	assert.Equal(t, "ETH-USDT", stream.sent[1].Symbol)
+	mockService.AssertExpectations(t)
+}
+
+func TestSubscribeRates_ClientCanceled(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream := &mockSubscribeStream{ctx: ctx}
+
+	mockService.On("SubscribeRates", ctx, []string{"BTC-USDT"}).Return(make(chan model.Rate), nil)
+
+	// Act
+	err := server.SubscribeRates(&pb.SubscribeRatesRequest{Symbols: []string{"BTC-USDT"}}, stream)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Canceled, st.Code())
+	assert.Empty(t, stream.sent)
+	mockService.AssertExpectations(t)
+}
+
+func TestSubscribeRates_InvalidSymbols(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	stream := &mockSubscribeStream{ctx: context.Background()}
+
+	tooMany := make([]string, maxSubscribeSymbols+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("SYM%d-USDT", i)
+	}
+
+	tests := []struct {
+		name    string
+		symbols []string
+	}{
+		{name: "no symbols", symbols: nil},
+		{name: "empty symbol", symbols: []string{"BTC-USDT", " "}},
+		{name: "too many symbols", symbols: tooMany},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := server.SubscribeRates(&pb.SubscribeRatesRequest{Symbols: tt.symbols}, stream)
+
+			// Assert
+			st, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+
+	mockService.AssertNotCalled(t, "SubscribeRates")
+}
+
+func TestSubscribeRates_ServiceClosed(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx := context.Background()
+	stream := &mockSubscribeStream{ctx: ctx}
+
+	mockService.On("SubscribeRates", ctx, []string{"BTC-USDT"}).Return(nil, service.ErrServiceClosed)
+
+	// Act
+	err := server.SubscribeRates(&pb.SubscribeRatesRequest{Symbols: []string{"BTC-USDT"}}, stream)
+
+	// Assert
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRateHistory_Pagination(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	cursor := &model.RateCursor{Timestamp: from.Add(time.Minute), ID: 42}
+
+	mockService.On("GetRateHistory", ctx, "BTC-USDT", from, to, 2, (*model.RateCursor)(nil)).
+		Return([]model.Rate{
+			{ID: 41, Symbol: "BTC-USDT", Ask: 40001, Bid: 40000, Timestamp: from},
+			{ID: 42, Symbol: "BTC-USDT", Ask: 40002, Bid: 40001, Timestamp: cursor.Timestamp},
+		}, cursor, nil)
+	mockService.On("GetRateHistory", ctx, "BTC-USDT", from, to, 2, cursor).
+		Return([]model.Rate{}, nil, nil)
+
+	req := &pb.GetRateHistoryRequest{
+		Symbol:   "BTC-USDT",
+		From:     timestamppb.New(from),
+		To:       timestamppb.New(to),
+		PageSize: 2,
+	}
+
+	// Act: первая страница
+	resp, err := server.GetRateHistory(ctx, req)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, resp)
-	assert.True(t, resp.Healthy)
+	assert.Len(t, resp.Rates, 2)
+	assert.Equal(t, 40002.0, resp.Rates[1].Ask)
+	assert.Equal(t, from, resp.Rates[0].Timestamp.AsTime())
+	assert.NotEmpty(t, resp.NextPageToken)
+
+	// Act: следующая страница по токену из ответа
+	req.PageToken = resp.NextPageToken
+	resp, err = server.GetRateHistory(ctx, req)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Rates)
+	assert.Empty(t, resp.NextPageToken)
 	mockService.AssertExpectations(t)
 }
 
-func TestHealthCheck_Unhealthy(t *testing.T) {
+func TestGetRateHistory_InvalidArguments(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	otherSymbolToken := encodePageToken("ETH-USDT", &model.RateCursor{Timestamp: from, ID: 1})
+
+	tests := []struct {
+		name string
+		req  *pb.GetRateHistoryRequest
+	}{
+		{name: "empty symbol", req: &pb.GetRateHistoryRequest{From: timestamppb.New(from)}},
+		{name: "missing from", req: &pb.GetRateHistoryRequest{Symbol: "BTC-USDT"}},
+		{name: "from after to", req: &pb.GetRateHistoryRequest{
+			Symbol: "BTC-USDT", From: timestamppb.New(from), To: timestamppb.New(from.Add(-time.Hour)),
+		}},
+		{name: "negative page size", req: &pb.GetRateHistoryRequest{
+			Symbol: "BTC-USDT", From: timestamppb.New(from), PageSize: -1,
+		}},
+		{name: "malformed page token", req: &pb.GetRateHistoryRequest{
+			Symbol: "BTC-USDT", From: timestamppb.New(from), PageToken: "not-a-token",
+		}},
+		{name: "page token of another symbol", req: &pb.GetRateHistoryRequest{
+			Symbol: "BTC-USDT", From: timestamppb.New(from), PageToken: otherSymbolToken,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			resp, err := server.GetRateHistory(context.Background(), tt.req)
+
+			// Assert
+			assert.Nil(t, resp)
+			st, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+
+	mockService.AssertNotCalled(t, "GetRateHistory")
+}
+
+func TestGetRateHistory_PageSizeLimits(t *testing.T) {
 	// Arrange
 	mockService := new(MockRateService)
 	logger := zap.NewNop()
 	server := NewRateServiceServer(logger, mockService)
 
 	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
 
-	// Настраиваем мок
-	mockService.On("HealthCheck", ctx).Return(false)
+	mockService.On("GetRateHistory", ctx, "BTC-USDT", from, to, defaultHistoryPageSize, (*model.RateCursor)(nil)).
+		Return([]model.Rate{}, nil, nil).Once()
+	mockService.On("GetRateHistory", ctx, "BTC-USDT", from, to, maxHistoryPageSize, (*model.RateCursor)(nil)).
+		Return([]model.Rate{}, nil, nil).Once()
+
+	// Act
+	_, errDefault := server.GetRateHistory(ctx, &pb.GetRateHistoryRequest{
+		Symbol: "BTC-USDT", From: timestamppb.New(from), To: timestamppb.New(to),
+	})
+	_, errMax := server.GetRateHistory(ctx, &pb.GetRateHistoryRequest{
+		Symbol: "BTC-USDT", From: timestamppb.New(from), To: timestamppb.New(to), PageSize: 100000,
+	})
+
+	// Assert
+	assert.NoError(t, errDefault)
+	assert.NoError(t, errMax)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRateHistory_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	mockService.On("GetRateHistory", ctx, "BTC-USDT", from, to, defaultHistoryPageSize, (*model.RateCursor)(nil)).
+		Return(nil, nil, errors.New("database error"))
+
+	// Act
+	resp, err := server.GetRateHistory(ctx, &pb.GetRateHistoryRequest{
+		Symbol: "BTC-USDT", From: timestamppb.New(from), To: timestamppb.New(to),
+	})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+}
+
+func TestGetCandles_Success(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	mockService.On("GetCandles", ctx, "BTC-USDT", 5*time.Minute, from, to).Return([]model.Candle{
+		{
+			OpenTime: from,
+			Mid:      model.OHLC{Open: 100.5, High: 110.5, Low: 90.5, Close: 105.5},
+			Ask:      model.OHLC{Open: 101, High: 111, Low: 91, Close: 106},
+			Bid:      model.OHLC{Open: 100, High: 110, Low: 90, Close: 105},
+			Count:    12,
+		},
+	}, nil)
 
 	// Act
-	resp, err := server.HealthCheck(ctx, &pb.HealthCheckRequest{})
+	resp, err := server.GetCandles(ctx, &pb.GetCandlesRequest{
+		Symbol:   "BTC-USDT",
+		Interval: pb.CandleInterval_CANDLE_INTERVAL_5M,
+		From:     timestamppb.New(from),
+		To:       timestamppb.New(to),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, pb.CandleInterval_CANDLE_INTERVAL_5M, resp.Interval)
+	assert.Len(t, resp.Candles, 1)
+	assert.Equal(t, from, resp.Candles[0].OpenTime.AsTime())
+	assert.Equal(t, 110.5, resp.Candles[0].Mid.High)
+	assert.Equal(t, 106.0, resp.Candles[0].Ask.Close)
+	assert.Equal(t, 90.0, resp.Candles[0].Bid.Low)
+	assert.Equal(t, int64(12), resp.Candles[0].Count)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCandles_InvalidArguments(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  *pb.GetCandlesRequest
+	}{
+		{name: "empty symbol", req: &pb.GetCandlesRequest{
+			Interval: pb.CandleInterval_CANDLE_INTERVAL_1M, From: timestamppb.New(from),
+		}},
+		{name: "unspecified interval", req: &pb.GetCandlesRequest{
+			Symbol: "BTC-USDT", From: timestamppb.New(from),
+		}},
+		{name: "missing from", req: &pb.GetCandlesRequest{
+			Symbol: "BTC-USDT", Interval: pb.CandleInterval_CANDLE_INTERVAL_1M,
+		}},
+		{name: "from after to", req: &pb.GetCandlesRequest{
+			Symbol: "BTC-USDT", Interval: pb.CandleInterval_CANDLE_INTERVAL_1M,
+			From: timestamppb.New(from), To: timestamppb.New(from.Add(-time.Minute)),
+		}},
+		{name: "too many candles", req: &pb.GetCandlesRequest{
+			Symbol: "BTC-USDT", Interval: pb.CandleInterval_CANDLE_INTERVAL_1M,
+			From: timestamppb.New(from), To: timestamppb.New(from.Add(365 * 24 * time.Hour)),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			resp, err := server.GetCandles(context.Background(), tt.req)
+
+			// Assert
+			assert.Nil(t, resp)
+			st, ok := status.FromError(err)
+			assert.True(t, ok)
+			assert.Equal(t, codes.InvalidArgument, st.Code())
+		})
+	}
+
+	mockService.AssertNotCalled(t, "GetCandles")
+}
+
+func TestGetCandles_ServiceError(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	logger := zap.NewNop()
+	server := NewRateServiceServer(logger, mockService)
+
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	mockService.On("GetCandles", ctx, "BTC-USDT", 24*time.Hour, from, to).Return(nil, errors.New("database error"))
+
+	// Act
+	resp, err := server.GetCandles(ctx, &pb.GetCandlesRequest{
+		Symbol:   "BTC-USDT",
+		Interval: pb.CandleInterval_CANDLE_INTERVAL_1D,
+		From:     timestamppb.New(from),
+		To:       timestamppb.New(to),
+	})
+
+	// Assert
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.Internal, st.Code())
+}
+
+type stubHealthReporter struct {
+	healthy  bool
+	statuses []health.ComponentStatus
+}
+
+func (r stubHealthReporter) Status() (bool, []health.ComponentStatus) {
+	return r.healthy, r.statuses
+}
+
+func TestHealthCheck_Components(t *testing.T) {
+	// Arrange
+	checkedAt := time.Now().UTC()
+	server := NewRateServiceServer(zap.NewNop(), new(MockRateService), WithHealthReporter(stubHealthReporter{
+		healthy: false,
+		statuses: []health.ComponentStatus{
+			{Name: "database", Healthy: true, Critical: true, CheckedAt: checkedAt},
+			{Name: "exchange:kucoin", Critical: true, Error: "exchange unavailable", CheckedAt: checkedAt},
+		},
+	}))
+
+	// Act
+	resp, err := server.HealthCheck(context.Background(), &pb.HealthCheckRequest{})
 
 	// Assert
 	assert.NoError(t, err)
-	assert.NotNil(t, resp)
 	assert.False(t, resp.Healthy)
+	assert.Len(t, resp.Components, 2)
+	assert.Equal(t, "database", resp.Components[0].Name)
+	assert.True(t, resp.Components[0].Healthy)
+	assert.Equal(t, "exchange:kucoin", resp.Components[1].Name)
+	assert.False(t, resp.Components[1].Healthy)
+	assert.True(t, resp.Components[1].Critical)
+	assert.Equal(t, "exchange unavailable", resp.Components[1].Error)
+	assert.Equal(t, checkedAt, resp.Components[1].CheckedAt.AsTime())
+}
+
+func TestHealthCheck_WithoutReporter(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService)
+
+	// Act
+	resp, err := server.HealthCheck(context.Background(), &pb.HealthCheckRequest{})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, resp.Healthy)
+	assert.Empty(t, resp.Components)
 	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// pageToken - содержимое непрозрачного токена страницы истории.
+// Символ сохраняется в токене, чтобы токен нельзя было применить к другому запросу.
+type pageToken struct {
+	Symbol    string `json:"s"`
+	Timestamp int64  `json:"t"`
+	ID        int64  `json:"i"`
+}
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+func encodePageToken(symbol string, cursor *model.RateCursor) string {
+	if cursor == nil {
+		return ""
+	}
+
+	data, _ := json.Marshal(pageToken{
+		Symbol:    symbol,
+		Timestamp: cursor.Timestamp.UnixNano(),
+		ID:        cursor.ID,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(symbol, token string) (*model.RateCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+
+	var decoded pageToken
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, errInvalidPageToken
+	}
+	if decoded.Symbol != symbol {
+		return nil, errInvalidPageToken
+	}
+
+	return &model.RateCursor{
+		Timestamp: time.Unix(0, decoded.Timestamp).UTC(),
+		ID:        decoded.ID,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/catalog"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/service"
+	pb "studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1"
+)
+
+type staticSymbolLister []model.Symbol
+
+func (l staticSymbolLister) ListSymbols(context.Context) ([]model.Symbol, error) {
+	return l, nil
+}
+
+func newTestCatalog(t *testing.T) *catalog.Registry {
+	registry, err := catalog.NewRegistry(zap.NewNop(), staticSymbolLister{
+		{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETH-USDT", Base: "ETH", Quote: "USDT"},
+		{Symbol: "ETH-BTC", Base: "ETH", Quote: "BTC"},
+	}, nil, time.Hour)
+	assert.NoError(t, err)
+	assert.NoError(t, registry.Sync(context.Background()))
+	return registry
+}
+
+func TestGetRates_NormalizesSymbol(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService, WithSymbolCatalog(newTestCatalog(t)))
+
+	ctx := context.Background()
+	mockService.On("GetRates", ctx, "BTC-USDT").Return(model.Quote{
+		Symbol: "BTC-USDT", Ask: 101, Bid: 100, Timestamp: time.Now(),
+	}, nil).Twice()
+
+	for _, symbol := range []string{"btcusdt", "BTC/USDT"} {
+		// Act
+		req := &pb.GetRatesRequest{Symbol: symbol}
+		resp, err := server.GetRates(ctx, req)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 101.0, resp.Ask)
+		// Запрос принадлежит вызывающему и не меняется
+		assert.Equal(t, symbol, req.Symbol)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRates_RejectsSymbolByCatalog(t *testing.T) {
+	server := NewRateServiceServer(zap.NewNop(), new(MockRateService), WithSymbolCatalog(newTestCatalog(t)))
+
+	tests := []struct {
+		name     string
+		symbol   string
+		wantCode codes.Code
+	}{
+		{name: "unknown pair", symbol: "DOGE-USDT", wantCode: codes.NotFound},
+		{name: "unknown compact", symbol: "dogeusdt", wantCode: codes.NotFound},
+		{name: "malformed", symbol: "BTC-USDT-ETH", wantCode: codes.InvalidArgument},
+		{name: "empty", symbol: "", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := server.GetRates(context.Background(), &pb.GetRatesRequest{Symbol: tt.symbol})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestGetRatesBatch_UnknownSymbolFailsOnlyItself(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService, WithSymbolCatalog(newTestCatalog(t)))
+
+	ctx := context.Background()
+	timestamp := time.Now().UTC()
+	// btcusdt и BTC-USDT - один символ после нормализации
+	mockService.On("GetRatesBatch", ctx, []string{"BTC-USDT", "ETH-USDT"}).Return([]service.QuoteResult{
+		{Symbol: "BTC-USDT", Quote: model.Quote{Ask: 101, Bid: 100, Timestamp: timestamp}},
+		{Symbol: "ETH-USDT", Quote: model.Quote{Ask: 11, Bid: 10, Timestamp: timestamp}},
+	})
+
+	// Act
+	resp, err := server.GetRatesBatch(ctx, &pb.GetRatesBatchRequest{
+		Symbols: []string{"btcusdt", "DOGE-USDT", "BTC-USDT", "eth/usdt"},
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, resp.Results, 3)
+	assert.Equal(t, "BTC-USDT", resp.Results[0].Symbol)
+	assert.Equal(t, 101.0, resp.Results[0].GetRate().Ask)
+	assert.Equal(t, "DOGE-USDT", resp.Results[1].Symbol)
+	assert.Equal(t, int32(codes.NotFound), resp.Results[1].GetError().Code)
+	assert.Equal(t, "ETH-USDT", resp.Results[2].Symbol)
+	assert.Equal(t, 11.0, resp.Results[2].GetRate().Ask)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRateHistory_AllowsDelistedSymbol(t *testing.T) {
+	// Arrange
+	mockService := new(MockRateService)
+	server := NewRateServiceServer(zap.NewNop(), mockService, WithSymbolCatalog(newTestCatalog(t)))
+
+	ctx := context.Background()
+	mockService.On("GetRateHistory", ctx, "DOGE-USDT", mock.Anything, mock.Anything, defaultHistoryPageSize, (*model.RateCursor)(nil)).
+		Return([]model.Rate{}, (*model.RateCursor)(nil), nil)
+
+	// Act
+	resp, err := server.GetRateHistory(ctx, &pb.GetRateHistoryRequest{
+		Symbol: "doge/usdt",
+		From:   timestamppb.New(time.Now().Add(-time.Hour)),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "DOGE-USDT", resp.Symbol)
+	mockService.AssertExpectations(t)
+}
+
+func TestListSymbols(t *testing.T) {
+	server := NewRateServiceServer(zap.NewNop(), new(MockRateService), WithSymbolCatalog(newTestCatalog(t)))
+
+	t.Run("all symbols", func(t *testing.T) {
+		resp, err := server.ListSymbols(context.Background(), &pb.ListSymbolsRequest{})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Symbols, 3)
+		assert.Equal(t, "BTC-USDT", resp.Symbols[0].Symbol)
+		assert.NotNil(t, resp.UpdatedAt)
+	})
+
+	t.Run("filtered by currencies", func(t *testing.T) {
+		resp, err := server.ListSymbols(context.Background(), &pb.ListSymbolsRequest{
+			BaseCurrency:  "eth",
+			QuoteCurrency: "USDT",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, resp.Symbols, 1)
+		assert.Equal(t, "ETH-USDT", resp.Symbols[0].Symbol)
+		assert.Equal(t, "ETH", resp.Symbols[0].BaseCurrency)
+		assert.Equal(t, "USDT", resp.Symbols[0].QuoteCurrency)
+	})
+}
+
+func TestListSymbols_CatalogDisabled(t *testing.T) {
+	server := NewRateServiceServer(zap.NewNop(), new(MockRateService))
+
+	_, err := server.ListSymbols(context.Background(), &pb.ListSymbolsRequest{})
+
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
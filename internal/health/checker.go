@@ -0,0 +1,288 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ErrInvalidInterval возвращается, если интервал проверок не положительный
+var ErrInvalidInterval = errors.New("health check interval must be positive")
+
+// Probe проверяет состояние компонента; nil означает, что компонент исправен
+type Probe func(ctx context.Context) error
+
+// Component - зависимость сервиса, состояние которой проверяется пробой
+type Component struct {
+	Name  string
+	Probe Probe
+	// Critical - без компонента сервис не может обслуживать запросы
+	Critical bool
+	// Group объединяет взаимозаменяемые критичные компоненты, например биржи
+	// с переключением при отказе: группа исправна, если исправен хотя бы один из них
+	Group string
+}
+
+// ComponentStatus - результат последней проверки компонента
+type ComponentStatus struct {
+	Name      string
+	Healthy   bool
+	Critical  bool
+	Error     string
+	CheckedAt time.Time
+}
+
+// Config содержит настройки проверок
+type Config struct {
+	Interval time.Duration
+	// Timeout - время на одну пробу; по умолчанию равно Interval
+	Timeout time.Duration
+	// Services - gRPC-сервисы, статус которых публикуется вместе с общим статусом сервера
+	Services []string
+}
+
+// Checker периодически опрашивает компоненты и публикует общий статус
+// в стандартном сервисе grpc.health.v1.Health. Сервис в статусе SERVING,
+// если исправны все критичные компоненты (для группы - хотя бы один).
+type Checker struct {
+	logger     *zap.Logger
+	server     *grpchealth.Server
+	config     Config
+	components []Component
+	tracer     trace.Tracer
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// stopped закрывается в Stop и завершает Watch-потоки
+	stopped  chan struct{}
+	stopOnce sync.Once
+
+	mu       sync.RWMutex
+	statuses []ComponentStatus
+	serving  bool
+}
+
+// NewChecker создает проверку. До первого опроса компонентов сервис в статусе NOT_SERVING.
+func NewChecker(logger *zap.Logger, config Config) (*Checker, error) {
+	if config.Interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = config.Interval
+	}
+
+	c := &Checker{
+		logger:  logger,
+		server:  grpchealth.NewServer(),
+		config:  config,
+		tracer:  otel.Tracer("health-checker"),
+		stopped: make(chan struct{}),
+	}
+	c.publish(false)
+	return c, nil
+}
+
+// HealthServer возвращает реализацию grpc.health.v1.Health для регистрации на gRPC-сервере
+func (c *Checker) HealthServer() healthpb.HealthServer {
+	return &watchServer{HealthServer: c.server, stopped: c.stopped}
+}
+
+// Register добавляет компоненты; вызывается до Start
+func (c *Checker) Register(components ...Component) {
+	c.components = append(c.components, components...)
+}
+
+// Start опрашивает компоненты и запускает периодические проверки
+func (c *Checker) Start(ctx context.Context) {
+	c.Check(ctx)
+
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.run(ctx)
+	}()
+}
+
+// Stop останавливает проверки и переводит все сервисы в NOT_SERVING,
+// чтобы балансировщики перестали направлять запросы до остановки сервера
+func (c *Checker) Stop() {
+	if c.cancel != nil {
+		c.cancel()
+		c.wg.Wait()
+	}
+
+	c.server.Shutdown()
+	c.stopOnce.Do(func() { close(c.stopped) })
+	c.logger.Info("Health checker stopped")
+}
+
+func (c *Checker) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// Check опрашивает все компоненты параллельно и обновляет статус сервиса
+func (c *Checker) Check(ctx context.Context) {
+	ctx, span := c.tracer.Start(ctx, "HealthChecker.Check")
+	defer span.End()
+
+	statuses := make([]ComponentStatus, len(c.components))
+	var wg sync.WaitGroup
+	for i, component := range c.components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			statuses[i] = c.probe(ctx, component)
+		}(i, component)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	serving := c.evaluate(statuses)
+
+	c.mu.Lock()
+	previous := c.statuses
+	c.statuses = statuses
+	c.serving = serving
+	c.mu.Unlock()
+
+	c.logChanges(previous, statuses)
+	c.publish(serving)
+
+	span.SetAttributes(attribute.Bool("serving", serving))
+	if !serving {
+		span.SetStatus(codes.Error, "Service is not serving")
+		return
+	}
+	span.SetStatus(codes.Ok, "Service is serving")
+}
+
+func (c *Checker) probe(ctx context.Context, component Component) ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	status := ComponentStatus{
+		Name:     component.Name,
+		Critical: component.Critical,
+	}
+	if err := component.Probe(ctx); err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Healthy = true
+	}
+	status.CheckedAt = time.Now()
+	return status
+}
+
+// evaluate сообщает, исправны ли все критичные компоненты с учетом групп
+func (c *Checker) evaluate(statuses []ComponentStatus) bool {
+	groups := make(map[string]bool)
+	for i, component := range c.components {
+		if !component.Critical {
+			continue
+		}
+		group := component.Group
+		if group == "" {
+			group = component.Name
+		}
+		groups[group] = groups[group] || statuses[i].Healthy
+	}
+
+	for _, healthy := range groups {
+		if !healthy {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Checker) logChanges(previous, current []ComponentStatus) {
+	for i, status := range current {
+		if i < len(previous) && previous[i].Healthy == status.Healthy {
+			continue
+		}
+		if status.Healthy {
+			c.logger.Info("Component is healthy", zap.String("component", status.Name))
+			continue
+		}
+		c.logger.Warn("Component is unhealthy",
+			zap.String("component", status.Name),
+			zap.Bool("critical", status.Critical),
+			zap.String("error", status.Error))
+	}
+}
+
+// publish обновляет статус общего ("") и перечисленных в конфигурации gRPC-сервисов
+func (c *Checker) publish(serving bool) {
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+
+	c.server.SetServingStatus("", servingStatus)
+	for _, service := range c.config.Services {
+		c.server.SetServingStatus(service, servingStatus)
+	}
+}
+
+// Status возвращает общий статус и результаты последней проверки компонентов
+func (c *Checker) Status() (bool, []ComponentStatus) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.serving, c.statuses
+}
+
+// watchServer завершает Watch-потоки после Stop: сами они закрываются только
+// клиентом, и GracefulStop gRPC-сервера ждал бы их бесконечно
+type watchServer struct {
+	healthpb.HealthServer
+	stopped <-chan struct{}
+}
+
+func (s *watchServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.stopped:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return s.HealthServer.Watch(req, &watchStream{Health_WatchServer: stream, ctx: ctx})
+}
+
+type watchStream struct {
+	healthpb.Health_WatchServer
+	ctx context.Context
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,193 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func okProbe(context.Context) error { return nil }
+
+func failingProbe(context.Context) error { return errors.New("connection refused") }
+
+func servingStatus(t *testing.T, checker *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := checker.HealthServer().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return resp.Status
+}
+
+func TestNewChecker_InvalidInterval(t *testing.T) {
+	_, err := NewChecker(zap.NewNop(), Config{})
+
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestChecker_NotServingBeforeFirstCheck(t *testing.T) {
+	checker, err := NewChecker(zap.NewNop(), Config{Interval: time.Minute, Services: []string{"rate_service.v1.RateService"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, servingStatus(t, checker, "rate_service.v1.RateService"))
+}
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		components  []Component
+		wantServing bool
+	}{
+		{
+			name: "all healthy",
+			components: []Component{
+				{Name: "database", Probe: okProbe, Critical: true},
+				{Name: "exchange:kucoin", Probe: okProbe, Critical: true, Group: "exchange"},
+			},
+			wantServing: true,
+		},
+		{
+			name: "critical component down",
+			components: []Component{
+				{Name: "database", Probe: failingProbe, Critical: true},
+				{Name: "exchange:kucoin", Probe: okProbe, Critical: true, Group: "exchange"},
+			},
+			wantServing: false,
+		},
+		{
+			name: "one exchange of group down",
+			components: []Component{
+				{Name: "exchange:kucoin", Probe: failingProbe, Critical: true, Group: "exchange"},
+				{Name: "exchange:okx", Probe: okProbe, Critical: true, Group: "exchange"},
+			},
+			wantServing: true,
+		},
+		{
+			name: "whole group down",
+			components: []Component{
+				{Name: "exchange:kucoin", Probe: failingProbe, Critical: true, Group: "exchange"},
+				{Name: "exchange:okx", Probe: failingProbe, Critical: true, Group: "exchange"},
+			},
+			wantServing: false,
+		},
+		{
+			name: "non-critical component down",
+			components: []Component{
+				{Name: "database", Probe: okProbe, Critical: true},
+				{Name: "collector", Probe: failingProbe},
+			},
+			wantServing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			checker, err := NewChecker(zap.NewNop(), Config{Interval: time.Minute})
+			require.NoError(t, err)
+			checker.Register(tt.components...)
+
+			// Act
+			checker.Check(context.Background())
+
+			// Assert
+			serving, statuses := checker.Status()
+			assert.Equal(t, tt.wantServing, serving)
+			assert.Len(t, statuses, len(tt.components))
+
+			want := healthpb.HealthCheckResponse_NOT_SERVING
+			if tt.wantServing {
+				want = healthpb.HealthCheckResponse_SERVING
+			}
+			assert.Equal(t, want, servingStatus(t, checker, ""))
+		})
+	}
+}
+
+func TestChecker_ComponentStatus(t *testing.T) {
+	// Arrange
+	checker, err := NewChecker(zap.NewNop(), Config{Interval: time.Minute})
+	require.NoError(t, err)
+	checker.Register(
+		Component{Name: "database", Probe: okProbe, Critical: true},
+		Component{Name: "collector", Probe: failingProbe},
+	)
+
+	// Act
+	checker.Check(context.Background())
+
+	// Assert
+	_, statuses := checker.Status()
+	assert.True(t, statuses[0].Healthy)
+	assert.True(t, statuses[0].Critical)
+	assert.Empty(t, statuses[0].Error)
+	assert.False(t, statuses[1].Healthy)
+	assert.Equal(t, "connection refused", statuses[1].Error)
+	assert.False(t, statuses[1].CheckedAt.IsZero())
+}
+
+func TestChecker_ProbeTimeout(t *testing.T) {
+	// Arrange
+	checker, err := NewChecker(zap.NewNop(), Config{Interval: time.Minute, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+	checker.Register(Component{Name: "database", Critical: true, Probe: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	// Act
+	checker.Check(context.Background())
+
+	// Assert
+	serving, statuses := checker.Status()
+	assert.False(t, serving)
+	assert.Equal(t, context.DeadlineExceeded.Error(), statuses[0].Error)
+}
+
+// Заглушка Watch-потока, сохраняющая отправленные статусы
+type stubWatchStream struct {
+	healthpb.Health_WatchServer
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (s *stubWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stubWatchStream) Send(resp *healthpb.HealthCheckResponse) error {
+	s.sent <- resp.Status
+	return nil
+}
+
+func TestChecker_WatchEndsOnStop(t *testing.T) {
+	// Arrange
+	checker, err := NewChecker(zap.NewNop(), Config{Interval: time.Minute})
+	require.NoError(t, err)
+	checker.Register(Component{Name: "database", Probe: okProbe, Critical: true})
+	checker.Start(context.Background())
+
+	stream := &stubWatchStream{ctx: context.Background(), sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- checker.HealthServer().Watch(&healthpb.HealthCheckRequest{}, stream)
+	}()
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, <-stream.sent)
+
+	// Act
+	checker.Stop()
+
+	// Assert
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after Stop")
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// OHLC - цены открытия, максимума, минимума и закрытия за интервал
+type OHLC struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+// Candle - свеча по сохраненным котировкам одного интервала
+type Candle struct {
+	OpenTime time.Time
+	Mid      OHLC
+	Ask      OHLC
+	Bid      OHLC
+	Count    int64
+}
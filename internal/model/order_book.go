@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// PriceLevel - уровень стакана: цена и объем заявок по ней
+type PriceLevel struct {
+	Price float64
+	Size  float64
+}
+
+// OrderBook - верхние уровни стакана по символу.
+// Asks отсортированы по возрастанию цены, Bids - по убыванию, лучшая цена первая.
+type OrderBook struct {
+	Symbol   string
+	Exchange string
+	// Sequence - номер обновления стакана на бирже; 0, если биржа его не сообщает
+	Sequence  int64
+	Asks      []PriceLevel
+	Bids      []PriceLevel
+	Timestamp time.Time
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// QuoteSource - источник, из которого получен курс
+type QuoteSource string
+
+const (
+	QuoteSourceExchange QuoteSource = "exchange"
+	QuoteSourceStream   QuoteSource = "stream"
+	QuoteSourceCache    QuoteSource = "cache"
+	QuoteSourceStorage  QuoteSource = "storage"
+)
+
+// Quote - лучшие цены по символу, отдаваемые клиенту
+type Quote struct {
+	Symbol    string
+	Ask       float64
+	Bid       float64
+	Timestamp time.Time
+	Source    QuoteSource
+	// Stale - курс взят из последних сохраненных, потому что биржа недоступна
+	Stale bool
+	// Path - пары, через которые рассчитан кросс-курс; пусто, если пара торгуется напрямую
+	Path []QuoteLeg
+}
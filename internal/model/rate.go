@@ -10,3 +10,9 @@ type Rate struct {
 	Timestamp time.Time `db:"timestamp"`
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// RateCursor - позиция записи в истории курсов для постраничной выборки
+type RateCursor struct {
+	Timestamp time.Time
+	ID        int64
+}
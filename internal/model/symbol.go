@@ -0,0 +1,15 @@
+package model
+
+// Symbol - торгуемая на бирже пара в едином формате BASE-QUOTE
+type Symbol struct {
+	Symbol string
+	Base   string
+	Quote  string
+}
+
+// QuoteLeg - пара, через которую рассчитан кросс-курс.
+// Inverted - пара используется в обратную сторону (QUOTE -> BASE), ее ask и bid обращены.
+type QuoteLeg struct {
+	Symbol   string
+	Inverted bool
+}
@@ -0,0 +1,169 @@
+package pricing
+
+import (
+	"sort"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// pairEdge - переход от одной валюты к другой через торгуемую пару
+type pairEdge struct {
+	to  string
+	leg model.QuoteLeg
+}
+
+// PairGraph - граф валют, ребра которого - торгуемые пары. Каждая пара BASE-QUOTE
+// дает два ребра: прямое BASE -> QUOTE и обратное QUOTE -> BASE.
+type PairGraph struct {
+	edges map[string][]pairEdge
+	pairs map[string]struct{}
+}
+
+// NewPairGraph строит граф по списку пар. Соседи каждой валюты упорядочены так,
+// что сначала идут hubs в порядке приоритета, затем остальные валюты по алфавиту:
+// среди путей одной длины выбирается путь через более приоритетные валюты.
+func NewPairGraph(symbols []model.Symbol, hubs []string) *PairGraph {
+	g := &PairGraph{
+		edges: make(map[string][]pairEdge),
+		pairs: make(map[string]struct{}, len(symbols)),
+	}
+
+	for _, symbol := range symbols {
+		if symbol.Base == "" || symbol.Quote == "" || symbol.Base == symbol.Quote {
+			continue
+		}
+		if _, ok := g.pairs[symbol.Symbol]; ok {
+			continue
+		}
+		g.pairs[symbol.Symbol] = struct{}{}
+
+		g.edges[symbol.Base] = append(g.edges[symbol.Base], pairEdge{
+			to:  symbol.Quote,
+			leg: model.QuoteLeg{Symbol: symbol.Symbol},
+		})
+		g.edges[symbol.Quote] = append(g.edges[symbol.Quote], pairEdge{
+			to:  symbol.Base,
+			leg: model.QuoteLeg{Symbol: symbol.Symbol, Inverted: true},
+		})
+	}
+
+	priority := make(map[string]int, len(hubs))
+	for i, hub := range hubs {
+		if _, ok := priority[hub]; !ok {
+			priority[hub] = i
+		}
+	}
+	rank := func(currency string) int {
+		if p, ok := priority[currency]; ok {
+			return p
+		}
+		return len(hubs)
+	}
+
+	for currency, edges := range g.edges {
+		sort.Slice(edges, func(i, j int) bool {
+			ri, rj := rank(edges[i].to), rank(edges[j].to)
+			if ri != rj {
+				return ri < rj
+			}
+			if edges[i].to != edges[j].to {
+				return edges[i].to < edges[j].to
+			}
+			// Между одними валютами могут торговаться обе пары (A-B и B-A): прямая первой
+			if edges[i].leg.Inverted != edges[j].leg.Inverted {
+				return !edges[i].leg.Inverted
+			}
+			return edges[i].leg.Symbol < edges[j].leg.Symbol
+		})
+		g.edges[currency] = edges
+	}
+
+	return g
+}
+
+// HasPair сообщает, торгуется ли пара напрямую
+func (g *PairGraph) HasPair(symbol string) bool {
+	_, ok := g.pairs[symbol]
+	return ok
+}
+
+// Path ищет кратчайший путь от base к quote не длиннее maxLegs пар.
+// Для одинаковых исходных данных результат всегда один и тот же.
+func (g *PairGraph) Path(base, quote string, maxLegs int) ([]model.QuoteLeg, bool) {
+	if base == quote || maxLegs <= 0 {
+		return nil, false
+	}
+	if _, ok := g.edges[base]; !ok {
+		return nil, false
+	}
+
+	// Поиск в ширину: первый найденный путь кратчайший, а порядок соседей задает выбор среди равных
+	prev := map[string]pairEdge{base: {}}
+	level := []string{base}
+	for depth := 0; depth < maxLegs && len(level) > 0; depth++ {
+		var next []string
+		for _, currency := range level {
+			for _, edge := range g.edges[currency] {
+				if _, seen := prev[edge.to]; seen {
+					continue
+				}
+				prev[edge.to] = pairEdge{to: currency, leg: edge.leg}
+				if edge.to == quote {
+					return buildPath(prev, base, quote), true
+				}
+				next = append(next, edge.to)
+			}
+		}
+		level = next
+	}
+
+	return nil, false
+}
+
+// buildPath восстанавливает путь по ссылкам на предыдущую валюту
+func buildPath(prev map[string]pairEdge, base, quote string) []model.QuoteLeg {
+	var path []model.QuoteLeg
+	for currency := quote; currency != base; {
+		edge := prev[currency]
+		path = append(path, edge.leg)
+		currency = edge.to
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// LegQuote - лучшие цены пары одного шага кросс-курса
+type LegQuote struct {
+	Ask      float64
+	Bid      float64
+	Inverted bool
+}
+
+// CrossRate перемножает курсы шагов пути. Для обращенной пары цена BASE в QUOTE
+// переворачивается: ask' = 1/bid, bid' = 1/ask, поэтому спред сохраняет знак.
+// ok == false, если у обращенной пары нулевая цена.
+func CrossRate(legs []LegQuote) (ask, bid float64, ok bool) {
+	if len(legs) == 0 {
+		return 0, 0, false
+	}
+
+	ask, bid = 1, 1
+	for _, leg := range legs {
+		if !leg.Inverted {
+			ask *= leg.Ask
+			bid *= leg.Bid
+			continue
+		}
+
+		if leg.Ask <= 0 || leg.Bid <= 0 {
+			return 0, 0, false
+		}
+		ask *= 1 / leg.Bid
+		bid *= 1 / leg.Ask
+	}
+
+	return RoundSignificant(ask, SignificantDigits), RoundSignificant(bid, SignificantDigits), true
+}
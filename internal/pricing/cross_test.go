@@ -0,0 +1,171 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+func symbol(base, quote string) model.Symbol {
+	return model.Symbol{Symbol: base + "-" + quote, Base: base, Quote: quote}
+}
+
+func TestPairGraph_Path(t *testing.T) {
+	graph := NewPairGraph([]model.Symbol{
+		symbol("BTC", "USDT"),
+		symbol("ETH", "USDT"),
+		symbol("SOL", "USDT"),
+		symbol("EUR", "USDT"),
+		symbol("SOL", "BTC"),
+		symbol("EUR", "BTC"),
+		symbol("ADA", "ETH"),
+		symbol("XYZ", "ABC"),
+	}, []string{"USDT", "BTC"})
+
+	tests := []struct {
+		name         string
+		base, quote  string
+		maxLegs      int
+		want         []model.QuoteLeg
+		wantNotFound bool
+	}{
+		{
+			name: "direct pair",
+			base: "BTC", quote: "USDT", maxLegs: 3,
+			want: []model.QuoteLeg{{Symbol: "BTC-USDT"}},
+		},
+		{
+			name: "inverted pair",
+			base: "USDT", quote: "BTC", maxLegs: 3,
+			want: []model.QuoteLeg{{Symbol: "BTC-USDT", Inverted: true}},
+		},
+		{
+			name: "through hub",
+			base: "ETH", quote: "BTC", maxLegs: 3,
+			want: []model.QuoteLeg{{Symbol: "ETH-USDT"}, {Symbol: "BTC-USDT", Inverted: true}},
+		},
+		{
+			// SOL-EUR доступен и через USDT, и через BTC: выбирается USDT как более приоритетный
+			name: "hub priority breaks ties",
+			base: "SOL", quote: "EUR", maxLegs: 3,
+			want: []model.QuoteLeg{{Symbol: "SOL-USDT"}, {Symbol: "EUR-USDT", Inverted: true}},
+		},
+		{
+			name: "three legs",
+			base: "ADA", quote: "SOL", maxLegs: 3,
+			want: []model.QuoteLeg{
+				{Symbol: "ADA-ETH"},
+				{Symbol: "ETH-USDT"},
+				{Symbol: "SOL-USDT", Inverted: true},
+			},
+		},
+		{name: "too many legs", base: "ADA", quote: "SOL", maxLegs: 2, wantNotFound: true},
+		{name: "disconnected", base: "XYZ", quote: "USDT", maxLegs: 3, wantNotFound: true},
+		{name: "unknown currency", base: "DOGE", quote: "USDT", maxLegs: 3, wantNotFound: true},
+		{name: "same currency", base: "USDT", quote: "USDT", maxLegs: 3, wantNotFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := graph.Path(tt.base, tt.quote, tt.maxLegs)
+			if tt.wantNotFound {
+				assert.False(t, ok)
+				assert.Nil(t, path)
+				return
+			}
+
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, path)
+		})
+	}
+}
+
+func TestPairGraph_PathDeterministic(t *testing.T) {
+	// Порядок пар в списке биржи не влияет на выбор пути
+	symbols := []model.Symbol{
+		symbol("SOL", "USDC"),
+		symbol("EUR", "USDC"),
+		symbol("SOL", "DAI"),
+		symbol("EUR", "DAI"),
+	}
+	reversed := []model.Symbol{symbols[3], symbols[2], symbols[1], symbols[0]}
+
+	first, ok := NewPairGraph(symbols, nil).Path("SOL", "EUR", 3)
+	assert.True(t, ok)
+	second, ok := NewPairGraph(reversed, nil).Path("SOL", "EUR", 3)
+	assert.True(t, ok)
+
+	// Без приоритетных валют побеждает алфавит: DAI раньше USDC
+	assert.Equal(t, []model.QuoteLeg{{Symbol: "SOL-DAI"}, {Symbol: "EUR-DAI", Inverted: true}}, first)
+	assert.Equal(t, first, second)
+}
+
+func TestPairGraph_HasPair(t *testing.T) {
+	graph := NewPairGraph([]model.Symbol{symbol("BTC", "USDT")}, nil)
+
+	assert.True(t, graph.HasPair("BTC-USDT"))
+	assert.False(t, graph.HasPair("USDT-BTC"))
+}
+
+func TestCrossRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		legs    []LegQuote
+		wantAsk float64
+		wantBid float64
+		wantOK  bool
+	}{
+		{
+			name:    "direct",
+			legs:    []LegQuote{{Ask: 101, Bid: 100}},
+			wantAsk: 101, wantBid: 100, wantOK: true,
+		},
+		{
+			// USDT-BTC по паре BTC-USDT: ask = 1/bid, bid = 1/ask
+			name:    "inverted",
+			legs:    []LegQuote{{Ask: 50000, Bid: 40000, Inverted: true}},
+			wantAsk: 0.000025, wantBid: 0.00002, wantOK: true,
+		},
+		{
+			// ETH-BTC = ETH-USDT / BTC-USDT
+			name: "direct then inverted",
+			legs: []LegQuote{
+				{Ask: 2010, Bid: 2000},
+				{Ask: 40100, Bid: 40000, Inverted: true},
+			},
+			wantAsk: 0.05025, wantBid: 0.04987531172, wantOK: true,
+		},
+		{
+			name: "three legs",
+			legs: []LegQuote{
+				{Ask: 0.5, Bid: 0.4},
+				{Ask: 3, Bid: 2},
+				{Ask: 5, Bid: 4, Inverted: true},
+			},
+			// ask = 0.5 * 3 / 4, bid = 0.4 * 2 / 5
+			wantAsk: 0.375, wantBid: 0.16, wantOK: true,
+		},
+		{
+			name:   "zero price on inverted leg",
+			legs:   []LegQuote{{Ask: 0, Bid: 0, Inverted: true}},
+			wantOK: false,
+		},
+		{name: "no legs", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ask, bid, ok := CrossRate(tt.legs)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.wantAsk, ask)
+			assert.Equal(t, tt.wantBid, bid)
+			// Обращение не должно переворачивать спред
+			assert.GreaterOrEqual(t, ask, bid)
+		})
+	}
+}
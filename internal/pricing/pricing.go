@@ -0,0 +1,99 @@
+package pricing
+
+import (
+	"math"
+
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// SignificantDigits - точность всех производных величин пакета.
+// Округление до значащих цифр, а не до знаков после запятой, убирает погрешность
+// float64 (3 - 2.9 = 0.10000000000000009) и не обнуляет цены дешевых активов.
+const SignificantDigits = 10
+
+// Mid возвращает среднюю цену между ask и bid
+func Mid(ask, bid float64) float64 {
+	return RoundSignificant((ask+bid)/2, SignificantDigits)
+}
+
+// Spread возвращает абсолютный спред ask - bid
+func Spread(ask, bid float64) float64 {
+	return RoundSignificant(ask-bid, SignificantDigits)
+}
+
+// SpreadBps возвращает спред в базисных пунктах относительно средней цены.
+// При нулевой средней цене возвращает 0.
+func SpreadBps(ask, bid float64) float64 {
+	mid := (ask + bid) / 2
+	if mid == 0 {
+		return 0
+	}
+	return RoundSignificant((ask-bid)/mid*10000, SignificantDigits)
+}
+
+// RoundSignificant округляет значение до digits значащих цифр (половина - от нуля)
+func RoundSignificant(value float64, digits int) float64 {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	exponent := digits - 1 - int(math.Floor(math.Log10(math.Abs(value))))
+	if exponent >= 0 {
+		factor := math.Pow(10, float64(exponent))
+		return math.Round(value*factor) / factor
+	}
+	// Делим, а не умножаем на 10^-n, чтобы не терять точность множителя
+	factor := math.Pow(10, float64(-exponent))
+	return math.Round(value/factor) * factor
+}
+
+// VWAP - результат исполнения объема по уровням стакана
+type VWAP struct {
+	// Price - средневзвешенная цена исполнения
+	Price float64
+	// BaseQuantity - исполненный объем в базовой валюте
+	BaseQuantity float64
+	// Notional - исполненный объем в котируемой валюте
+	Notional float64
+	// Complete - в стакане хватило уровней на весь запрошенный объем
+	Complete bool
+}
+
+// WalkBook рассчитывает VWAP исполнения notional (в котируемой валюте) по уровням стакана.
+// Уровни идут от лучшей цены: для покупки передаются asks, для продажи - bids.
+// Если уровней не хватает, возвращается VWAP исполненной части с Complete == false.
+// При неположительном notional исполнять нечего, возвращается пустой VWAP.
+func WalkBook(levels []model.PriceLevel, notional float64) VWAP {
+	if notional <= 0 {
+		return VWAP{}
+	}
+
+	var result VWAP
+	remaining := notional
+	for _, level := range levels {
+		if level.Price <= 0 || level.Size <= 0 {
+			continue
+		}
+
+		levelNotional := level.Price * level.Size
+		if levelNotional >= remaining {
+			result.BaseQuantity += remaining / level.Price
+			result.Notional += remaining
+			remaining = 0
+			break
+		}
+
+		result.BaseQuantity += level.Size
+		result.Notional += levelNotional
+		remaining -= levelNotional
+	}
+
+	result.Complete = remaining == 0
+	if result.BaseQuantity > 0 {
+		result.Price = RoundSignificant(result.Notional/result.BaseQuantity, SignificantDigits)
+	}
+	result.BaseQuantity = RoundSignificant(result.BaseQuantity, SignificantDigits)
+	result.Notional = RoundSignificant(result.Notional, SignificantDigits)
+
+	return result
+}
@@ -0,0 +1,118 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+func TestMidAndSpread(t *testing.T) {
+	tests := []struct {
+		name       string
+		ask, bid   float64
+		wantMid    float64
+		wantSpread float64
+		wantBps    float64
+	}{
+		{name: "regular book", ask: 40001, bid: 39999, wantMid: 40000, wantSpread: 2, wantBps: 0.5},
+		{name: "wide spread", ask: 101, bid: 99, wantMid: 100, wantSpread: 2, wantBps: 200},
+		{name: "float noise removed", ask: 3, bid: 2.9, wantMid: 2.95, wantSpread: 0.1, wantBps: 338.9830508},
+		{name: "cheap asset", ask: 0.000012345678912, bid: 0.000012345, wantMid: 0.00001234533946, wantSpread: 0.000000000678912, wantBps: 0.5499338454},
+		{name: "locked book", ask: 100, bid: 100, wantMid: 100, wantSpread: 0, wantBps: 0},
+		{name: "zero prices", ask: 0, bid: 0, wantMid: 0, wantSpread: 0, wantBps: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantMid, Mid(tt.ask, tt.bid))
+			assert.Equal(t, tt.wantSpread, Spread(tt.ask, tt.bid))
+			assert.Equal(t, tt.wantBps, SpreadBps(tt.ask, tt.bid))
+		})
+	}
+}
+
+func TestRoundSignificant(t *testing.T) {
+	tests := []struct {
+		value  float64
+		digits int
+		want   float64
+	}{
+		{value: 0.10000000000000009, digits: 10, want: 0.1},
+		{value: 1.25, digits: 2, want: 1.3},
+		{value: -1.25, digits: 2, want: -1.3},
+		{value: 338.983050847, digits: 5, want: 338.98},
+		{value: 0.000012345678, digits: 3, want: 0.0000123},
+		{value: 123456789, digits: 3, want: 123000000},
+		{value: 0, digits: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RoundSignificant(tt.value, tt.digits))
+	}
+}
+
+func TestWalkBook(t *testing.T) {
+	asks := []model.PriceLevel{
+		{Price: 100, Size: 1},
+		{Price: 101, Size: 2},
+		{Price: 102, Size: 5},
+	}
+
+	tests := []struct {
+		name     string
+		levels   []model.PriceLevel
+		notional float64
+		want     VWAP
+	}{
+		{
+			name:     "filled by best level",
+			levels:   asks,
+			notional: 50,
+			want:     VWAP{Price: 100, BaseQuantity: 0.5, Notional: 50, Complete: true},
+		},
+		{
+			name:     "walks several levels",
+			levels:   asks,
+			notional: 302,
+			// 100 по 100 + 202 по 101 = 3 единицы
+			want: VWAP{Price: 302.0 / 3, BaseQuantity: 3, Notional: 302, Complete: true},
+		},
+		{
+			name:     "insufficient depth",
+			levels:   asks,
+			notional: 10000,
+			want:     VWAP{Price: 812.0 / 8, BaseQuantity: 8, Notional: 812, Complete: false},
+		},
+		{
+			name:     "skips empty levels",
+			levels:   []model.PriceLevel{{Price: 99, Size: 0}, {Price: 100, Size: 1}},
+			notional: 100,
+			want:     VWAP{Price: 100, BaseQuantity: 1, Notional: 100, Complete: true},
+		},
+		{
+			name:     "empty book",
+			levels:   nil,
+			notional: 100,
+			want:     VWAP{},
+		},
+		{
+			name:     "non-positive notional",
+			levels:   asks,
+			notional: 0,
+			want:     VWAP{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WalkBook(tt.levels, tt.notional)
+
+			// Результат округлен до SignificantDigits, поэтому сравниваем точно
+			assert.Equal(t, RoundSignificant(tt.want.Price, SignificantDigits), got.Price)
+			assert.Equal(t, tt.want.BaseQuantity, got.BaseQuantity)
+			assert.Equal(t, tt.want.Notional, got.Notional)
+			assert.Equal(t, tt.want.Complete, got.Complete)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+)
+
+// SQLSTATE-коды, означающие недоступность сервера, а не ошибку запроса
+const (
+	sqlStateQueryCanceled         = "57014"
+	sqlStateAdminShutdown         = "57P01"
+	sqlStateCrashShutdown         = "57P02"
+	sqlStateCannotConnectNow      = "57P03"
+	sqlStateConnectionException   = "08"
+	sqlStateInsufficientResources = "53"
+)
+
+// classifyError оборачивает ошибку драйвера в вид ошибки хранилища.
+// Ошибки, не относящиеся ни к одному виду, возвращаются как есть.
+func classifyError(err error) error {
+	if kind := errorKind(err); kind != nil {
+		return fmt.Errorf("%w: %w", kind, err)
+	}
+	return err
+}
+
+func errorKind(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err) {
+		return repository.ErrTimeout
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == sqlStateQueryCanceled:
+			return repository.ErrTimeout
+		case pgErr.Code == sqlStateAdminShutdown,
+			pgErr.Code == sqlStateCrashShutdown,
+			pgErr.Code == sqlStateCannotConnectNow,
+			strings.HasPrefix(pgErr.Code, sqlStateConnectionException),
+			strings.HasPrefix(pgErr.Code, sqlStateInsufficientResources):
+			return repository.ErrUnavailable
+		}
+		return nil
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) {
+		return repository.ErrUnavailable
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind error
+	}{
+		{name: "no rows", err: sql.ErrNoRows, wantKind: repository.ErrNotFound},
+		{name: "deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), wantKind: repository.ErrTimeout},
+		{name: "statement timeout", err: &pgconn.PgError{Code: "57014"}, wantKind: repository.ErrTimeout},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, wantKind: repository.ErrUnavailable},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, wantKind: repository.ErrUnavailable},
+		{name: "bad connection", err: driver.ErrBadConn, wantKind: repository.ErrUnavailable},
+		{name: "closed connection", err: sql.ErrConnDone, wantKind: repository.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+
+			assert.ErrorIs(t, err, tt.wantKind)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestClassifyError_Unclassified(t *testing.T) {
+	for _, err := range []error{
+		context.Canceled,
+		&pgconn.PgError{Code: "23505"},
+		errors.New("syntax error"),
+	} {
+		got := classifyError(err)
+
+		assert.Equal(t, err, got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -14,6 +15,7 @@ import (
 	"go.uber.org/zap"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
 )
 
 type Repository struct {
@@ -73,7 +75,7 @@ func (r *Repository) SaveRate(ctx context.Context, rate model.Rate) error {
 	)
 
 	if err != nil {
-		r.logger.Error("Failed to save rate",
+		logger.WithContext(ctx, r.logger).Error("Failed to save rate",
 			zap.String("symbol", rate.Symbol),
 			zap.Float64("ask", rate.Ask),
 			zap.Float64("bid", rate.Bid),
@@ -84,10 +86,10 @@ func (r *Repository) SaveRate(ctx context.Context, rate model.Rate) error {
 			span.RecordError(err)
 		}
 
-		return fmt.Errorf("failed to execute insert query: %w", err)
+		return fmt.Errorf("failed to execute insert query: %w", classifyError(err))
 	}
 
-	r.logger.Debug("Rate saved successfully",
+	logger.WithContext(ctx, r.logger).Debug("Rate saved successfully",
 		zap.String("symbol", rate.Symbol),
 		zap.Float64("ask", rate.Ask),
 		zap.Float64("bid", rate.Bid))
@@ -98,6 +100,53 @@ func (r *Repository) SaveRate(ctx context.Context, rate model.Rate) error {
 	return nil
 }
 
+// SaveRates сохраняет курсы одним многострочным INSERT
+func (r *Repository) SaveRates(ctx context.Context, rates []model.Rate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	var span trace.Span
+	if r.tracer != nil {
+		ctx, span = r.tracer.Start(ctx, "Repository.SaveRates",
+			trace.WithAttributes(attribute.Int("count", len(rates))))
+		defer span.End()
+	}
+
+	const columns = 5
+	var query strings.Builder
+	query.WriteString("INSERT INTO rates (symbol, ask, bid, timestamp, created_at) VALUES ")
+
+	args := make([]interface{}, 0, len(rates)*columns)
+	createdAt := time.Now()
+	for i, rate := range rates {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		n := i * columns
+		fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5)
+		args = append(args, rate.Symbol, rate.Ask, rate.Bid, rate.Timestamp, createdAt)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query.String(), args...); err != nil {
+		logger.WithContext(ctx, r.logger).Error("Failed to save rates", zap.Int("count", len(rates)), zap.Error(err))
+
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to save rates to database")
+			span.RecordError(err)
+		}
+
+		return fmt.Errorf("failed to execute batch insert query: %w", classifyError(err))
+	}
+
+	logger.WithContext(ctx, r.logger).Debug("Rates saved successfully", zap.Int("count", len(rates)))
+
+	if span != nil {
+		span.SetStatus(codes.Ok, "Rates saved successfully")
+	}
+	return nil
+}
+
 func (r *Repository) GetLatestRate(ctx context.Context, symbol string) (*model.Rate, error) {
 	// Создаем спан для трассировки только если трассировщик инициализирован
 	var span trace.Span
@@ -125,7 +174,7 @@ func (r *Repository) GetLatestRate(ctx context.Context, symbol string) (*model.R
 	)
 
 	if err != nil {
-		r.logger.Error("Failed to get latest rate",
+		logger.WithContext(ctx, r.logger).Error("Failed to get latest rate",
 			zap.String("symbol", symbol),
 			zap.Error(err))
 
@@ -133,10 +182,10 @@ func (r *Repository) GetLatestRate(ctx context.Context, symbol string) (*model.R
 			span.SetStatus(codes.Error, "Failed to get latest rate from database")
 			span.RecordError(err)
 		}
-		return nil, fmt.Errorf("failed to get latest rate: %w", err)
+		return nil, fmt.Errorf("failed to get latest rate: %w", classifyError(err))
 	}
 
-	r.logger.Debug("Retrieved latest rate",
+	logger.WithContext(ctx, r.logger).Debug("Retrieved latest rate",
 		zap.String("symbol", rate.Symbol),
 		zap.Float64("ask", rate.Ask),
 		zap.Float64("bid", rate.Bid),
@@ -154,6 +203,202 @@ func (r *Repository) GetLatestRate(ctx context.Context, symbol string) (*model.R
 	return &rate, nil
 }
 
+func (r *Repository) GetRateHistory(ctx context.Context, query repository.HistoryQuery) ([]model.Rate, error) {
+	// Создаем спан для трассировки только если трассировщик инициализирован
+	var span trace.Span
+	if r.tracer != nil {
+		ctx, span = r.tracer.Start(ctx, "Repository.GetRateHistory",
+			trace.WithAttributes(
+				attribute.String("symbol", query.Symbol),
+				attribute.String("from", query.From.Format(time.RFC3339)),
+				attribute.String("to", query.To.Format(time.RFC3339)),
+				attribute.Int("limit", query.Limit),
+			))
+		defer span.End()
+	}
+
+	// Для первой страницы курсор ставится перед первой записью интервала:
+	// id всегда положительный, поэтому (from, 0) меньше любой записи с timestamp >= from
+	after := model.RateCursor{Timestamp: query.From}
+	if query.After != nil {
+		after = *query.After
+	}
+
+	sqlQuery := `
+		SELECT id, symbol, ask, bid, timestamp, created_at
+		FROM rates
+		WHERE symbol = $1
+		  AND timestamp >= $2
+		  AND timestamp < $3
+		  AND (timestamp, id) > ($4, $5)
+		ORDER BY timestamp, id
+		LIMIT $6
+	`
+	rows, err := r.db.QueryContext(ctx, sqlQuery,
+		query.Symbol,
+		query.From,
+		query.To,
+		after.Timestamp,
+		after.ID,
+		query.Limit,
+	)
+	if err != nil {
+		logger.WithContext(ctx, r.logger).Error("Failed to get rate history",
+			zap.String("symbol", query.Symbol),
+			zap.Error(err))
+
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to get rate history from database")
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to get rate history: %w", classifyError(err))
+	}
+	defer rows.Close()
+
+	rates := make([]model.Rate, 0, query.Limit)
+	for rows.Next() {
+		var rate model.Rate
+		if err := rows.Scan(
+			&rate.ID,
+			&rate.Symbol,
+			&rate.Ask,
+			&rate.Bid,
+			&rate.Timestamp,
+			&rate.CreatedAt,
+		); err != nil {
+			if span != nil {
+				span.SetStatus(codes.Error, "Failed to scan rate history row")
+				span.RecordError(err)
+			}
+			return nil, fmt.Errorf("failed to scan rate history row: %w", err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to iterate rate history rows")
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to iterate rate history rows: %w", classifyError(err))
+	}
+
+	logger.WithContext(ctx, r.logger).Debug("Retrieved rate history",
+		zap.String("symbol", query.Symbol),
+		zap.Int("count", len(rates)))
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("count", len(rates)))
+		span.SetStatus(codes.Ok, "Rate history retrieved successfully")
+	}
+
+	return rates, nil
+}
+
+func (r *Repository) GetCandles(ctx context.Context, query repository.CandleQuery) ([]model.Candle, error) {
+	// Создаем спан для трассировки только если трассировщик инициализирован
+	var span trace.Span
+	if r.tracer != nil {
+		ctx, span = r.tracer.Start(ctx, "Repository.GetCandles",
+			trace.WithAttributes(
+				attribute.String("symbol", query.Symbol),
+				attribute.String("interval", query.Interval.String()),
+				attribute.String("from", query.From.Format(time.RFC3339)),
+				attribute.String("to", query.To.Format(time.RFC3339)),
+			))
+		defer span.End()
+	}
+
+	// Открытие и закрытие берутся из первой и последней котировки свечи по (timestamp, id).
+	// Интервалы без котировок в выборку не попадают.
+	sqlQuery := `
+		SELECT
+			date_bin($2::interval, timestamp, TIMESTAMP '1970-01-01') AS open_time,
+			(array_agg((ask + bid) / 2 ORDER BY timestamp, id))[1],
+			max((ask + bid) / 2),
+			min((ask + bid) / 2),
+			(array_agg((ask + bid) / 2 ORDER BY timestamp DESC, id DESC))[1],
+			(array_agg(ask ORDER BY timestamp, id))[1],
+			max(ask),
+			min(ask),
+			(array_agg(ask ORDER BY timestamp DESC, id DESC))[1],
+			(array_agg(bid ORDER BY timestamp, id))[1],
+			max(bid),
+			min(bid),
+			(array_agg(bid ORDER BY timestamp DESC, id DESC))[1],
+			count(*)
+		FROM rates
+		WHERE symbol = $1
+		  AND timestamp >= $3
+		  AND timestamp < $4
+		GROUP BY open_time
+		ORDER BY open_time
+	`
+	interval := fmt.Sprintf("%d seconds", int64(query.Interval/time.Second))
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query.Symbol, interval, query.From, query.To)
+	if err != nil {
+		logger.WithContext(ctx, r.logger).Error("Failed to get candles",
+			zap.String("symbol", query.Symbol),
+			zap.Duration("interval", query.Interval),
+			zap.Error(err))
+
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to get candles from database")
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to get candles: %w", classifyError(err))
+	}
+	defer rows.Close()
+
+	candles := make([]model.Candle, 0)
+	for rows.Next() {
+		var candle model.Candle
+		if err := rows.Scan(
+			&candle.OpenTime,
+			&candle.Mid.Open, &candle.Mid.High, &candle.Mid.Low, &candle.Mid.Close,
+			&candle.Ask.Open, &candle.Ask.High, &candle.Ask.Low, &candle.Ask.Close,
+			&candle.Bid.Open, &candle.Bid.High, &candle.Bid.Low, &candle.Bid.Close,
+			&candle.Count,
+		); err != nil {
+			if span != nil {
+				span.SetStatus(codes.Error, "Failed to scan candle row")
+				span.RecordError(err)
+			}
+			return nil, fmt.Errorf("failed to scan candle row: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+
+	if err := rows.Err(); err != nil {
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to iterate candle rows")
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to iterate candle rows: %w", classifyError(err))
+	}
+
+	logger.WithContext(ctx, r.logger).Debug("Retrieved candles",
+		zap.String("symbol", query.Symbol),
+		zap.Duration("interval", query.Interval),
+		zap.Int("count", len(candles)))
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("count", len(candles)))
+		span.SetStatus(codes.Ok, "Candles retrieved successfully")
+	}
+
+	return candles, nil
+}
+
+// Ping проверяет соединение с базой данных
+func (r *Repository) Ping(ctx context.Context) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", classifyError(err))
+	}
+	return nil
+}
+
 func (r *Repository) Close() error {
 	r.logger.Info("Closing database connection")
 	if err := r.db.Close(); err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
 )
 
 func TestNewRepository(t *testing.T) {
@@ -79,6 +81,64 @@ func TestSaveRate(t *testing.T) {
 	})
 }
 
+func TestSaveRates(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Repository{
+		db:     db,
+		logger: zap.NewNop(),
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	rates := []model.Rate{
+		{Symbol: "BTC-USDT", Ask: 40000.5, Bid: 39999.5, Timestamp: now},
+		{Symbol: "ETH-USDT", Ask: 2001.5, Bid: 2000.5, Timestamp: now},
+	}
+
+	t.Run("single multi-row insert", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO rates (symbol, ask, bid, timestamp, created_at) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)")).
+			WithArgs(
+				"BTC-USDT", 40000.5, 39999.5, now, sqlmock.AnyArg(),
+				"ETH-USDT", 2001.5, 2000.5, now, sqlmock.AnyArg(),
+			).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		// Act
+		err := repo.SaveRates(ctx, rates)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		// Act
+		err := repo.SaveRates(ctx, nil)
+
+		// Assert: запрос к БД не выполняется
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO rates").WillReturnError(errors.New("database error"))
+
+		// Act
+		err := repo.SaveRates(ctx, rates)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to execute batch insert query")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestGetLatestRate(t *testing.T) {
 	// Arrange
 	db, mock, err := sqlmock.New()
@@ -134,7 +194,8 @@ func TestGetLatestRate(t *testing.T) {
 		// Assert
 		assert.Error(t, err)
 		assert.Nil(t, rate)
-		assert.Equal(t, sql.ErrNoRows, errors.Unwrap(err))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
@@ -206,3 +267,157 @@ func TestClose(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestGetRateHistory(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := zap.NewNop()
+	repo := &Repository{
+		db:     db,
+		logger: logger,
+	}
+
+	ctx := context.Background()
+	symbol := "BTC-USDT"
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	columns := []string{"id", "symbol", "ask", "bid", "timestamp", "created_at"}
+
+	t.Run("first page starts at interval beginning", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow(1, symbol, 40001.0, 40000.0, from, from).
+			AddRow(2, symbol, 40002.0, 40001.0, from.Add(time.Minute), from)
+
+		mock.ExpectQuery("SELECT (.+) FROM rates (.+) ORDER BY timestamp, id").
+			WithArgs(symbol, from, to, from, int64(0), 2).
+			WillReturnRows(rows)
+
+		// Act
+		rates, err := repo.GetRateHistory(ctx, repository.HistoryQuery{
+			Symbol: symbol,
+			From:   from,
+			To:     to,
+			Limit:  2,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, rates, 2)
+		assert.Equal(t, int64(1), rates[0].ID)
+		assert.Equal(t, 40002.0, rates[1].Ask)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("next page continues after cursor", func(t *testing.T) {
+		cursor := &model.RateCursor{Timestamp: from.Add(time.Minute), ID: 2}
+		rows := sqlmock.NewRows(columns).
+			AddRow(3, symbol, 40003.0, 40002.0, from.Add(2*time.Minute), from)
+
+		mock.ExpectQuery("SELECT (.+) FROM rates").
+			WithArgs(symbol, from, to, cursor.Timestamp, cursor.ID, 2).
+			WillReturnRows(rows)
+
+		// Act
+		rates, err := repo.GetRateHistory(ctx, repository.HistoryQuery{
+			Symbol: symbol,
+			From:   from,
+			To:     to,
+			After:  cursor,
+			Limit:  2,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, rates, 1)
+		assert.Equal(t, int64(3), rates[0].ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM rates").
+			WillReturnError(errors.New("database error"))
+
+		// Act
+		rates, err := repo.GetRateHistory(ctx, repository.HistoryQuery{Symbol: symbol, From: from, To: to, Limit: 10})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, rates)
+		assert.Contains(t, err.Error(), "failed to get rate history")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetCandles(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	logger := zap.NewNop()
+	repo := &Repository{
+		db:     db,
+		logger: logger,
+	}
+
+	ctx := context.Background()
+	symbol := "BTC-USDT"
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	query := repository.CandleQuery{Symbol: symbol, Interval: 5 * time.Minute, From: from, To: to}
+
+	t.Run("successful aggregation", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{
+			"open_time",
+			"mid_open", "mid_high", "mid_low", "mid_close",
+			"ask_open", "ask_high", "ask_low", "ask_close",
+			"bid_open", "bid_high", "bid_low", "bid_close",
+			"count",
+		}).
+			AddRow(from, 100.5, 110.5, 90.5, 105.5, 101, 111, 91, 106, 100, 110, 90, 105, 12).
+			AddRow(from.Add(10*time.Minute), 200.5, 200.5, 200.5, 200.5, 201, 201, 201, 201, 200, 200, 200, 200, 1)
+
+		mock.ExpectQuery(`SELECT date_bin\(\$2::interval, timestamp(.+) FROM rates (.+) GROUP BY open_time`).
+			WithArgs(symbol, "300 seconds", from, to).
+			WillReturnRows(rows)
+
+		// Act
+		candles, err := repo.GetCandles(ctx, query)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, candles, 2)
+		assert.Equal(t, from, candles[0].OpenTime)
+		assert.Equal(t, 100.5, candles[0].Mid.Open)
+		assert.Equal(t, 110.5, candles[0].Mid.High)
+		assert.Equal(t, 90.5, candles[0].Mid.Low)
+		assert.Equal(t, 105.5, candles[0].Mid.Close)
+		assert.Equal(t, 111.0, candles[0].Ask.High)
+		assert.Equal(t, 90.0, candles[0].Bid.Low)
+		assert.Equal(t, int64(12), candles[0].Count)
+		// Пустой интервал между свечами не дополняется
+		assert.Equal(t, from.Add(10*time.Minute), candles[1].OpenTime)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM rates").
+			WillReturnError(errors.New("database error"))
+
+		// Act
+		candles, err := repo.GetCandles(ctx, query)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, candles)
+		assert.Contains(t, err.Error(), "failed to get candles")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
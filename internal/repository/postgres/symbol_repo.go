@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
+)
+
+// symbolInsertBatch - число пар в одном INSERT, чтобы не упереться в лимит параметров запроса
+const symbolInsertBatch = 1000
+
+// ReplaceSymbols заменяет справочник пар в одной транзакции:
+// читатели видят либо старый, либо новый список целиком
+func (r *Repository) ReplaceSymbols(ctx context.Context, symbols []model.Symbol) error {
+	var span trace.Span
+	if r.tracer != nil {
+		ctx, span = r.tracer.Start(ctx, "Repository.ReplaceSymbols",
+			trace.WithAttributes(attribute.Int("count", len(symbols))))
+		defer span.End()
+	}
+
+	err := r.replaceSymbols(ctx, symbols)
+	if err != nil {
+		logger.WithContext(ctx, r.logger).Error("Failed to replace symbols", zap.Int("count", len(symbols)), zap.Error(err))
+
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to replace symbols")
+			span.RecordError(err)
+		}
+		return err
+	}
+
+	logger.WithContext(ctx, r.logger).Debug("Symbols replaced successfully", zap.Int("count", len(symbols)))
+
+	if span != nil {
+		span.SetStatus(codes.Ok, "Symbols replaced successfully")
+	}
+	return nil
+}
+
+func (r *Repository) replaceSymbols(ctx context.Context, symbols []model.Symbol) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", classifyError(err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM symbols"); err != nil {
+		return fmt.Errorf("failed to delete symbols: %w", classifyError(err))
+	}
+
+	const columns = 4
+	updatedAt := time.Now()
+	for start := 0; start < len(symbols); start += symbolInsertBatch {
+		batch := symbols[start:min(start+symbolInsertBatch, len(symbols))]
+
+		var query strings.Builder
+		query.WriteString("INSERT INTO symbols (symbol, base_currency, quote_currency, updated_at) VALUES ")
+
+		args := make([]interface{}, 0, len(batch)*columns)
+		for i, symbol := range batch {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			n := i * columns
+			fmt.Fprintf(&query, "($%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4)
+			args = append(args, symbol.Symbol, symbol.Base, symbol.Quote, updatedAt)
+		}
+		query.WriteString(" ON CONFLICT (symbol) DO NOTHING")
+
+		if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+			return fmt.Errorf("failed to insert symbols: %w", classifyError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", classifyError(err))
+	}
+	return nil
+}
+
+// ListSymbols возвращает сохраненный справочник пар, упорядоченный по символу
+func (r *Repository) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	var span trace.Span
+	if r.tracer != nil {
+		ctx, span = r.tracer.Start(ctx, "Repository.ListSymbols")
+		defer span.End()
+	}
+
+	query := `
+		SELECT symbol, base_currency, quote_currency
+		FROM symbols
+		ORDER BY symbol
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		logger.WithContext(ctx, r.logger).Error("Failed to list symbols", zap.Error(err))
+
+		if span != nil {
+			span.SetStatus(codes.Error, "Failed to list symbols")
+			span.RecordError(err)
+		}
+		return nil, fmt.Errorf("failed to query symbols: %w", classifyError(err))
+	}
+	defer rows.Close()
+
+	var symbols []model.Symbol
+	for rows.Next() {
+		var symbol model.Symbol
+		if err := rows.Scan(&symbol.Symbol, &symbol.Base, &symbol.Quote); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol: %w", err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read symbols: %w", classifyError(err))
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("count", len(symbols)))
+		span.SetStatus(codes.Ok, "Symbols listed successfully")
+	}
+	return symbols, nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+func TestReplaceSymbols(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Repository{
+		db:     db,
+		logger: zap.NewNop(),
+	}
+
+	ctx := context.Background()
+	symbols := []model.Symbol{
+		{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETH-USDT", Base: "ETH", Quote: "USDT"},
+	}
+
+	t.Run("replaces catalog in transaction", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM symbols").WillReturnResult(sqlmock.NewResult(0, 5))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO symbols (symbol, base_currency, quote_currency, updated_at) VALUES ($1, $2, $3, $4), ($5, $6, $7, $8)")).
+			WithArgs(
+				"BTC-USDT", "BTC", "USDT", sqlmock.AnyArg(),
+				"ETH-USDT", "ETH", "USDT", sqlmock.AnyArg(),
+			).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		// Act
+		err := repo.ReplaceSymbols(ctx, symbols)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("insert error rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM symbols").WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("INSERT INTO symbols").WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		// Act
+		err := repo.ReplaceSymbols(ctx, symbols)
+
+		// Assert
+		assert.ErrorContains(t, err, "failed to insert symbols")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestListSymbols(t *testing.T) {
+	// Arrange
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := &Repository{
+		db:     db,
+		logger: zap.NewNop(),
+	}
+
+	mock.ExpectQuery("SELECT symbol, base_currency, quote_currency FROM symbols").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "base_currency", "quote_currency"}).
+			AddRow("BTC-USDT", "BTC", "USDT").
+			AddRow("ETH-USDT", "ETH", "USDT"))
+
+	// Act
+	symbols, err := repo.ListSymbols(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Symbol{
+		{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETH-USDT", Base: "ETH", Quote: "USDT"},
+	}, symbols)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,12 +2,58 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
 )
 
+// Виды ошибок хранилища. Реализации оборачивают в них ошибки драйвера,
+// проверяются через errors.Is.
+var (
+	ErrNotFound    = errors.New("not found in storage")
+	ErrUnavailable = errors.New("storage unavailable")
+	ErrTimeout     = errors.New("storage request timed out")
+)
+
 type RateRepository interface {
 	SaveRate(ctx context.Context, rate model.Rate) error
+	// SaveRates сохраняет несколько курсов одним запросом
+	SaveRates(ctx context.Context, rates []model.Rate) error
 	GetLatestRate(ctx context.Context, symbol string) (*model.Rate, error)
+	GetRateHistory(ctx context.Context, query HistoryQuery) ([]model.Rate, error)
+	GetCandles(ctx context.Context, query CandleQuery) ([]model.Candle, error)
 	Close() error
 }
+
+// Pinger - хранилище, доступность которого можно проверить без запроса данных.
+// Реализуется опционально, проверяется приведением типа.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// SymbolRepository хранит справочник торгуемых пар
+type SymbolRepository interface {
+	// ReplaceSymbols заменяет весь справочник новым списком пар
+	ReplaceSymbols(ctx context.Context, symbols []model.Symbol) error
+	ListSymbols(ctx context.Context) ([]model.Symbol, error)
+}
+
+// HistoryQuery - параметры постраничной выборки истории курсов.
+// Записи упорядочены по (timestamp, id), After - последняя запись предыдущей страницы.
+type HistoryQuery struct {
+	Symbol string
+	From   time.Time
+	To     time.Time
+	After  *model.RateCursor
+	Limit  int
+}
+
+// CandleQuery - параметры агрегации котировок в свечи за интервал [From, To).
+// Свечи выравниваются по границам Interval, отсчитанным от начала эпохи Unix в UTC.
+type CandleQuery struct {
+	Symbol   string
+	Interval time.Duration
+	From     time.Time
+	To       time.Time
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/pricing"
+)
+
+// pairGraphRetryInterval - пауза перед повторной загрузкой списка пар после ошибки,
+// чтобы недоступная биржа не получала запрос списка на каждый курс
+const pairGraphRetryInterval = 30 * time.Second
+
+// pairGraphLoadTimeout ограничивает загрузку списка пар: она не зависит от
+// контекста запроса, который ее начал
+const pairGraphLoadTimeout = 30 * time.Second
+
+// ErrInvalidCrossRate возвращается, если у обращаемой пары пути нулевая цена
+var ErrInvalidCrossRate = errors.New("cannot invert zero price for cross rate")
+
+var errPairGraphUnavailable = errors.New("exchange symbols are not loaded yet")
+
+// SymbolSource - уже загруженный и обновляемый список пар биржи (catalog.Registry)
+type SymbolSource interface {
+	Symbols() []model.Symbol
+	// UpdatedAt возвращает время последнего обновления; нулевое, если список не загружен
+	UpdatedAt() time.Time
+}
+
+// pairGraphCache хранит граф пар биржи. Если задан source, граф перестраивается
+// при обновлении справочника, иначе список пар загружается с биржи не чаще раза
+// в maxAge. Если обновить список пар не удалось, используется предыдущий граф.
+type pairGraphCache struct {
+	source SymbolSource
+	lister exchange.SymbolLister
+	hubs   []string
+	maxAge time.Duration
+	group  singleflight.Group
+
+	mu       sync.Mutex
+	graph    *pricing.PairGraph
+	loadedAt time.Time
+	retryAt  time.Time
+	// sourceUpdatedAt - время обновления справочника, по которому построен graph
+	sourceUpdatedAt time.Time
+}
+
+func newPairGraphCache(lister exchange.SymbolLister, hubs []string, maxAge time.Duration) *pairGraphCache {
+	return &pairGraphCache{
+		lister: lister,
+		hubs:   hubs,
+		maxAge: maxAge,
+	}
+}
+
+func newSourcePairGraphCache(source SymbolSource, hubs []string) *pairGraphCache {
+	return &pairGraphCache{
+		source: source,
+		hubs:   hubs,
+	}
+}
+
+// get возвращает граф пар. Устаревший граф отдается сразу, а новый загружается
+// в фоне; ждут загрузки только запросы, пришедшие до первой успешной загрузки.
+func (c *pairGraphCache) get(ctx context.Context) (*pricing.PairGraph, error) {
+	if c.source != nil {
+		return c.fromSource()
+	}
+
+	c.mu.Lock()
+	now := time.Now()
+	graph, fresh, retrying := c.graph, now.Sub(c.loadedAt) < c.maxAge, now.Before(c.retryAt)
+	c.mu.Unlock()
+
+	if graph != nil && fresh {
+		return graph, nil
+	}
+	if retrying {
+		if graph != nil {
+			return graph, nil
+		}
+		return nil, errPairGraphUnavailable
+	}
+
+	// Загрузку выполняет только один из одновременных запросов, а отмена его
+	// контекста не прерывает загрузку для остальных
+	resultCh := c.group.DoChan("symbols", func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pairGraphLoadTimeout)
+		defer cancel()
+		return c.load(loadCtx)
+	})
+	if graph != nil {
+		return graph, nil
+	}
+
+	select {
+	case result := <-resultCh:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*pricing.PairGraph), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// load загружает список пар с биржи и заменяет граф
+func (c *pairGraphCache) load(ctx context.Context) (*pricing.PairGraph, error) {
+	symbols, err := c.lister.ListSymbols(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.retryAt = time.Now().Add(pairGraphRetryInterval)
+		return nil, err
+	}
+
+	c.graph = pricing.NewPairGraph(symbols, c.hubs)
+	c.loadedAt = time.Now()
+	return c.graph, nil
+}
+
+// fromSource перестраивает граф, если справочник обновился с прошлого вызова
+func (c *pairGraphCache) fromSource() (*pricing.PairGraph, error) {
+	// Время читается до списка: если справочник обновится между вызовами,
+	// граф будет перестроен еще раз при следующем запросе
+	updatedAt := c.source.UpdatedAt()
+	if updatedAt.IsZero() {
+		return nil, errPairGraphUnavailable
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.graph == nil || !updatedAt.Equal(c.sourceUpdatedAt) {
+		c.graph = pricing.NewPairGraph(c.source.Symbols(), c.hubs)
+		c.sourceUpdatedAt = updatedAt
+	}
+	return c.graph, nil
+}
+
+// crossPath возвращает путь через промежуточные пары, если symbol не торгуется напрямую.
+// cross == false означает, что курс нужно запрашивать у биржи как есть.
+func (s *RateService) crossPath(ctx context.Context, symbol string) (path []model.QuoteLeg, cross bool) {
+	if s.pairGraph == nil {
+		return nil, false
+	}
+
+	parts := strings.Split(symbol, "-")
+	if len(parts) != 2 {
+		return nil, false
+	}
+
+	graph, err := s.pairGraph.get(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load exchange symbols for cross rates", zap.Error(err))
+		return nil, false
+	}
+	if graph.HasPair(symbol) {
+		return nil, false
+	}
+
+	path, ok := graph.Path(parts[0], parts[1], s.crossRateMaxLegs)
+	if !ok {
+		return nil, false
+	}
+	return path, true
+}
+
+// resolveQuote получает курс напрямую или, если пара не торгуется, через промежуточные пары.
+// Курсы промежуточных пар сохраняются сразу, поэтому для кросс-курса fetched всегда false.
+func (s *RateService) resolveQuote(ctx context.Context, symbol string) (model.Quote, bool, error) {
+	path, cross := s.crossPath(ctx, symbol)
+	if !cross {
+		return s.getQuote(ctx, symbol)
+	}
+
+	quote, err := s.getCrossQuote(ctx, symbol, path)
+	return quote, false, err
+}
+
+// getCrossQuote рассчитывает курс symbol по курсам пар пути
+func (s *RateService) getCrossQuote(ctx context.Context, symbol string, path []model.QuoteLeg) (model.Quote, error) {
+	legSymbols := make([]string, 0, len(path))
+	for _, leg := range path {
+		legSymbols = append(legSymbols, leg.Symbol)
+	}
+
+	ctx, span := s.tracer.Start(ctx, "RateService.GetCrossQuote",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.StringSlice("path", legSymbols),
+		))
+	defer span.End()
+
+	legs := make([]pricing.LegQuote, 0, len(path))
+	result := model.Quote{Symbol: symbol, Path: path}
+	sources := make(map[model.QuoteSource]struct{}, len(path))
+	for i, leg := range path {
+		quote, fetched, err := s.getQuote(ctx, leg.Symbol)
+		if err != nil {
+			span.SetStatus(codes.Error, "Failed to get cross rate leg")
+			span.RecordError(err)
+			return model.Quote{}, fmt.Errorf("cross rate leg %s: %w", leg.Symbol, err)
+		}
+		if fetched {
+			s.saveQuote(ctx, quote)
+		}
+
+		legs = append(legs, pricing.LegQuote{Ask: quote.Ask, Bid: quote.Bid, Inverted: leg.Inverted})
+		sources[quote.Source] = struct{}{}
+		result.Stale = result.Stale || quote.Stale
+		// Кросс-курс не свежее самой старой из пар
+		if i == 0 || quote.Timestamp.Before(result.Timestamp) {
+			result.Timestamp = quote.Timestamp
+		}
+	}
+
+	ask, bid, ok := pricing.CrossRate(legs)
+	if !ok {
+		span.SetStatus(codes.Error, ErrInvalidCrossRate.Error())
+		return model.Quote{}, ErrInvalidCrossRate
+	}
+	result.Ask = ask
+	result.Bid = bid
+	result.Source = crossSource(sources, result.Stale)
+
+	span.SetAttributes(
+		attribute.Float64("ask", ask),
+		attribute.Float64("bid", bid),
+		attribute.Bool("stale", result.Stale),
+	)
+	span.SetStatus(codes.Ok, "Cross rate calculated")
+
+	return result, nil
+}
+
+// crossSource выбирает источник кросс-курса: общий источник всех пар,
+// сохраненный курс, если хотя бы одна пара устарела, иначе - биржа
+func crossSource(sources map[model.QuoteSource]struct{}, stale bool) model.QuoteSource {
+	if stale {
+		return model.QuoteSourceStorage
+	}
+	if len(sources) == 1 {
+		for source := range sources {
+			return source
+		}
+	}
+	return model.QuoteSourceExchange
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// MockListingExchange - мок биржи, которая умеет отдавать список пар
+type MockListingExchange struct {
+	MockExchange
+}
+
+func (m *MockListingExchange) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Symbol), args.Error(1)
+}
+
+func testSymbols() []model.Symbol {
+	return []model.Symbol{
+		{Symbol: "BTC-USDT", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETH-USDT", Base: "ETH", Quote: "USDT"},
+	}
+}
+
+func newCrossRateService(repo *MockRateRepository, mockExchange *MockListingExchange) *RateService {
+	return NewRateService(zap.NewNop(), repo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		CrossRateMaxLegs:      3,
+		CrossRateHubs:         []string{"USDT"},
+		PairGraphMaxAge:       time.Hour,
+	})
+}
+
+func TestGetRates_CrossRate(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	service := newCrossRateService(mockRepo, mockExchange)
+
+	older := time.Now().Add(-time.Second).UTC()
+	newer := time.Now().UTC()
+	mockExchange.On("ListSymbols", mock.Anything).Return(testSymbols(), nil).Once()
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-USDT").Return(2010.0, 2000.0, newer, nil)
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(40100.0, 40000.0, older, nil)
+	// Сохраняются курсы торгуемых пар, а не рассчитанный кросс-курс
+	mockRepo.On("SaveRate", mock.Anything, mock.MatchedBy(func(rate model.Rate) bool {
+		return rate.Symbol == "ETH-USDT" || rate.Symbol == "BTC-USDT"
+	})).Return(nil).Twice()
+
+	// Act
+	quote, err := service.GetRates(context.Background(), "ETH-BTC")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH-BTC", quote.Symbol)
+	assert.Equal(t, 0.05025, quote.Ask)
+	assert.Equal(t, 0.04987531172, quote.Bid)
+	assert.Equal(t, older, quote.Timestamp)
+	assert.False(t, quote.Stale)
+	assert.Equal(t, []model.QuoteLeg{{Symbol: "ETH-USDT"}, {Symbol: "BTC-USDT", Inverted: true}}, quote.Path)
+
+	mockExchange.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRates_DirectPairSkipsCrossRate(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	service := newCrossRateService(mockRepo, mockExchange)
+
+	timestamp := time.Now().UTC()
+	mockExchange.On("ListSymbols", mock.Anything).Return(testSymbols(), nil).Once()
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(40100.0, 40000.0, timestamp, nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	quote, err := service.GetRates(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 40100.0, quote.Ask)
+	assert.Empty(t, quote.Path)
+	mockExchange.AssertExpectations(t)
+}
+
+func TestGetRates_CrossRateLegError(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	service := newCrossRateService(mockRepo, mockExchange)
+
+	legErr := errors.New("kucoin error")
+	mockExchange.On("ListSymbols", mock.Anything).Return(testSymbols(), nil).Once()
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-USDT").Return(0.0, 0.0, time.Time{}, legErr)
+
+	// Act
+	_, err := service.GetRates(context.Background(), "ETH-BTC")
+
+	// Assert
+	assert.ErrorIs(t, err, legErr)
+	assert.Contains(t, err.Error(), "cross rate leg ETH-USDT")
+	mockRepo.AssertNotCalled(t, "SaveRate")
+}
+
+func TestGetRates_SymbolListUnavailable(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	service := newCrossRateService(mockRepo, mockExchange)
+
+	timestamp := time.Now().UTC()
+	mockExchange.On("ListSymbols", mock.Anything).Return(nil, errors.New("kucoin error")).Once()
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-BTC").Return(0.05, 0.049, timestamp, nil).Twice()
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	_, firstErr := service.GetRates(context.Background(), "ETH-BTC")
+	_, secondErr := service.GetRates(context.Background(), "ETH-BTC")
+
+	// Assert: без списка пар символ запрашивается у биржи как есть,
+	// а повторная загрузка списка откладывается
+	assert.NoError(t, firstErr)
+	assert.NoError(t, secondErr)
+	mockExchange.AssertExpectations(t)
+}
+
+func TestGetRatesWithBook_CrossRateHasNoBook(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	service := newCrossRateService(mockRepo, mockExchange)
+
+	timestamp := time.Now().UTC()
+	mockExchange.On("ListSymbols", mock.Anything).Return(testSymbols(), nil).Once()
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(50000.0, 40000.0, timestamp, nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	quote, book, err := service.GetRatesWithBook(context.Background(), "USDT-BTC")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Nil(t, book)
+	assert.Equal(t, 0.000025, quote.Ask)
+	assert.Equal(t, 0.00002, quote.Bid)
+	assert.Equal(t, []model.QuoteLeg{{Symbol: "BTC-USDT", Inverted: true}}, quote.Path)
+	mockExchange.AssertNotCalled(t, "GetOrderBookDepth", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// fakeSymbolSource - справочник пар с заданным списком
+type fakeSymbolSource struct {
+	symbols   []model.Symbol
+	updatedAt time.Time
+}
+
+func (f *fakeSymbolSource) Symbols() []model.Symbol {
+	return f.symbols
+}
+
+func (f *fakeSymbolSource) UpdatedAt() time.Time {
+	return f.updatedAt
+}
+
+// blockingLister отдает список пар только после закрытия release
+type blockingLister struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (l *blockingLister) ListSymbols(ctx context.Context) ([]model.Symbol, error) {
+	l.calls.Add(1)
+	select {
+	case <-l.release:
+		return testSymbols(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestGetRates_CrossRateFromSymbolSource(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockListingExchange)
+	source := &fakeSymbolSource{symbols: testSymbols(), updatedAt: time.Now()}
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		CrossRateMaxLegs:      3,
+		CrossRateHubs:         []string{"USDT"},
+		Symbols:               source,
+	})
+
+	timestamp := time.Now().UTC()
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(50000.0, 40000.0, timestamp, nil)
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil)
+
+	// Act
+	quote, err := service.GetRates(context.Background(), "USDT-BTC")
+
+	// Assert: граф построен по справочнику без отдельной загрузки списка пар
+	assert.NoError(t, err)
+	assert.Equal(t, []model.QuoteLeg{{Symbol: "BTC-USDT", Inverted: true}}, quote.Path)
+	mockExchange.AssertNotCalled(t, "ListSymbols", mock.Anything)
+}
+
+func TestPairGraphCache_CallerCancelDoesNotAbortLoad(t *testing.T) {
+	// Arrange
+	lister := &blockingLister{release: make(chan struct{})}
+	cache := newPairGraphCache(lister, []string{"USDT"}, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Act: первый запрос уходит, не дождавшись списка пар
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := cache.get(ctx)
+		canceled <- err
+	}()
+	assert.Eventually(t, func() bool { return lister.calls.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	canceledErr := <-canceled
+
+	close(lister.release)
+	graph, err := cache.get(context.Background())
+
+	// Assert: загрузка завершилась для остальных и не отложила кросс-курсы
+	assert.ErrorIs(t, canceledErr, context.Canceled)
+	assert.NoError(t, err)
+	assert.True(t, graph.HasPair("BTC-USDT"))
+	assert.Equal(t, int32(1), lister.calls.Load())
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheFetchFunc получает актуальное значение (курс, стакан) по символу
+type cacheFetchFunc[T any] func(ctx context.Context, symbol string) (T, error)
+
+// cachePersistFunc сохраняет значение, полученное с биржи
+type cachePersistFunc[T any] func(ctx context.Context, value T)
+
+// cachedValue - значение по символу и момент его получения с биржи
+type cachedValue[T any] struct {
+	value     T
+	fetchedAt time.Time
+}
+
+// quoteCache хранит последние значения по символам и объединяет одновременные
+// запросы одного символа в один запрос к бирже
+type quoteCache[T any] struct {
+	maxAge time.Duration
+	fetch  cacheFetchFunc[T]
+	// persist сохраняет значение, если инициатор запроса к бирже ушел, не дождавшись ответа
+	persist cachePersistFunc[T]
+	group   singleflight.Group
+
+	mu      sync.RWMutex
+	entries map[string]cachedValue[T]
+}
+
+// newQuoteCache создает кэш. При maxAge <= 0 значения не кэшируются,
+// но одновременные запросы все равно объединяются. persist может быть nil.
+func newQuoteCache[T any](maxAge time.Duration, fetch cacheFetchFunc[T], persist cachePersistFunc[T]) *quoteCache[T] {
+	return &quoteCache[T]{
+		maxAge:  maxAge,
+		fetch:   fetch,
+		persist: persist,
+		entries: make(map[string]cachedValue[T]),
+	}
+}
+
+// get возвращает свежее значение из кэша или запрашивает его у биржи.
+// fetched == true только у того вызова, который сам выполнил запрос к бирже.
+// Если этот вызов отменен раньше ответа биржи, значение сохраняется через persist.
+func (c *quoteCache[T]) get(ctx context.Context, symbol string) (value T, hit bool, fetched bool, err error) {
+	if cached, ok := c.lookup(symbol); ok {
+		return cached.value, true, false, nil
+	}
+
+	// Функцию запроса выполняет только один из одновременных вызовов
+	var leader atomic.Bool
+	resultCh := c.group.DoChan(symbol, func() (interface{}, error) {
+		// Повторная проверка: кэш мог обновиться, пока мы ждали
+		if cached, ok := c.lookup(symbol); ok {
+			return cached.value, nil
+		}
+
+		leader.Store(true)
+
+		// Отмена контекста одного клиента не должна прерывать запрос для остальных
+		value, err := c.fetch(context.WithoutCancel(ctx), symbol)
+		if err != nil {
+			return value, err
+		}
+
+		if c.maxAge > 0 {
+			c.mu.Lock()
+			c.entries[symbol] = cachedValue[T]{value: value, fetchedAt: time.Now()}
+			c.mu.Unlock()
+		}
+
+		return value, nil
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		// Запрос к бирже продолжается без нас: если его начали мы, сохранять значение
+		// больше некому, поэтому дожидаемся ответа в фоне
+		if c.persist != nil {
+			go func() {
+				result := <-resultCh
+				if result.Err == nil && leader.Load() {
+					c.persist(context.WithoutCancel(ctx), result.Val.(T))
+				}
+			}()
+		}
+		return zero, false, false, ctx.Err()
+	case result := <-resultCh:
+		if result.Err != nil {
+			return zero, false, false, result.Err
+		}
+		return result.Val.(T), false, leader.Load(), nil
+	}
+}
+
+func (c *quoteCache[T]) lookup(symbol string) (cachedValue[T], bool) {
+	if c.maxAge <= 0 {
+		return cachedValue[T]{}, false
+	}
+
+	c.mu.RLock()
+	cached, ok := c.entries[symbol]
+	c.mu.RUnlock()
+
+	if !ok || time.Since(cached.fetchedAt) > c.maxAge {
+		return cachedValue[T]{}, false
+	}
+	return cached, true
+}
+
+// snapshot возвращает записи кэша, упорядоченные по символу, включая устаревшие
+func (c *quoteCache[T]) snapshot(now time.Time) []CacheEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]CacheEntry, 0, len(c.entries))
+	for symbol, cached := range c.entries {
+		entries = append(entries, CacheEntry{
+			Symbol:    symbol,
+			FetchedAt: cached.fetchedAt,
+			Fresh:     now.Sub(cached.fetchedAt) <= c.maxAge,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Symbol < entries[j].Symbol })
+	return entries
+}
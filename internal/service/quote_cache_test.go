@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+func TestGetRates_CacheHit(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		QuoteCacheMaxAge:      time.Minute,
+	})
+
+	ctx := context.Background()
+	timestamp := time.Now().UTC()
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, timestamp, nil).Once()
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	first, err := service.GetRates(ctx, "BTC-USDT")
+	assert.NoError(t, err)
+	quote, err := service.GetRates(ctx, "BTC-USDT")
+
+	// Assert: второй запрос обслужен из кэша без обращения к бирже и БД
+	assert.NoError(t, err)
+	assert.Equal(t, model.QuoteSourceExchange, first.Source)
+	assert.Equal(t, model.QuoteSourceCache, quote.Source)
+	assert.Equal(t, 101.0, quote.Ask)
+	assert.Equal(t, 100.0, quote.Bid)
+	assert.Equal(t, timestamp, quote.Timestamp)
+
+	mockExchange.AssertNumberOfCalls(t, "GetOrderBook", 1)
+	mockRepo.AssertNumberOfCalls(t, "SaveRate", 1)
+}
+
+func TestCacheSnapshot(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		QuoteCacheMaxAge:      time.Minute,
+	})
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, time.Now().UTC(), nil).Once()
+	mockRepo.On("SaveRate", mock.Anything, mock.Anything).Return(nil).Once()
+
+	// Act
+	_, err := service.GetRates(context.Background(), "BTC-USDT")
+	snapshot := service.CacheSnapshot()
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, snapshot.MaxAge)
+	if assert.Len(t, snapshot.Quotes, 1) {
+		assert.Equal(t, "BTC-USDT", snapshot.Quotes[0].Symbol)
+		assert.True(t, snapshot.Quotes[0].Fresh)
+	}
+	assert.Empty(t, snapshot.OrderBooks)
+	assert.Empty(t, snapshot.Subscriptions)
+}
+
+func TestQuoteCache_Expires(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	cache := newQuoteCache(time.Minute, func(ctx context.Context, symbol string) (model.Quote, error) {
+		calls.Add(1)
+		return model.Quote{Symbol: symbol, Ask: 101, Bid: 100, Timestamp: time.Now()}, nil
+	}, nil)
+
+	_, _, fetched, err := cache.get(context.Background(), "BTC-USDT")
+	assert.NoError(t, err)
+	assert.True(t, fetched)
+
+	// Состариваем запись
+	cache.entries["BTC-USDT"] = cachedValue[model.Quote]{value: model.Quote{Ask: 101, Bid: 100}, fetchedAt: time.Now().Add(-2 * time.Minute)}
+
+	// Act
+	_, hit, fetched, err := cache.get(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, hit)
+	assert.True(t, fetched)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestQuoteCache_Disabled(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	cache := newQuoteCache(0, func(ctx context.Context, symbol string) (model.Quote, error) {
+		calls.Add(1)
+		return model.Quote{Symbol: symbol, Ask: 101, Bid: 100, Timestamp: time.Now()}, nil
+	}, nil)
+
+	// Act
+	_, _, _, _ = cache.get(context.Background(), "BTC-USDT")
+	_, hit, _, err := cache.get(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, hit)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestQuoteCache_SingleFlight(t *testing.T) {
+	// Arrange
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cache := newQuoteCache(time.Minute, func(ctx context.Context, symbol string) (model.Quote, error) {
+		calls.Add(1)
+		<-release
+		return model.Quote{Symbol: symbol, Ask: 101, Bid: 100, Timestamp: time.Now()}, nil
+	}, nil)
+
+	const clients = 10
+	var wg sync.WaitGroup
+	var fetchedCount atomic.Int32
+
+	// Act
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quote, _, fetched, err := cache.get(context.Background(), "BTC-USDT")
+			assert.NoError(t, err)
+			assert.Equal(t, 101.0, quote.Ask)
+			if fetched {
+				fetchedCount.Add(1)
+			}
+		}()
+	}
+
+	// Даем клиентам дойти до ожидания общего запроса
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Assert: один запрос к бирже, и только его инициатор сохраняет курс
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, int32(1), fetchedCount.Load())
+}
+
+func TestQuoteCache_CallerCancelDoesNotAbortFetch(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	fetchErr := make(chan error, 1)
+	cache := newQuoteCache(time.Minute, func(ctx context.Context, symbol string) (model.Quote, error) {
+		<-release
+		fetchErr <- ctx.Err()
+		return model.Quote{Symbol: symbol, Ask: 101, Bid: 100, Timestamp: time.Now()}, nil
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, _, _, err := cache.get(ctx, "BTC-USDT")
+	close(release)
+
+	// Assert
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.NoError(t, <-fetchErr)
+	assert.Eventually(t, func() bool {
+		_, ok := cache.lookup("BTC-USDT")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestQuoteCache_PersistsWhenLeaderCanceled(t *testing.T) {
+	// Arrange
+	release := make(chan struct{})
+	persisted := make(chan model.Quote, 1)
+	cache := newQuoteCache(time.Minute, func(ctx context.Context, symbol string) (model.Quote, error) {
+		<-release
+		return model.Quote{Symbol: symbol, Ask: 101, Bid: 100, Timestamp: time.Now()}, nil
+	}, func(ctx context.Context, quote model.Quote) {
+		persisted <- quote
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _, err := cache.get(ctx, "BTC-USDT")
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+
+	// Act: инициатор запроса уходит до ответа биржи
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+	close(release)
+
+	// Assert: курс сохранен, хотя ни один вызов не получил fetched == true
+	select {
+	case quote := <-persisted:
+		assert.Equal(t, "BTC-USDT", quote.Symbol)
+	case <-time.After(time.Second):
+		t.Fatal("quote was not persisted")
+	}
+
+	_, _, fetched, err := cache.get(context.Background(), "BTC-USDT")
+	assert.NoError(t, err)
+	assert.False(t, fetched)
+}
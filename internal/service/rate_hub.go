@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// fetchFunc получает лучшие цены ask/bid по символу
+type fetchFunc func(ctx context.Context, symbol string) (float64, float64, time.Time, error)
+
+// rateHub раздает обновления курсов подписчикам.
+// На каждый символ с активными подписчиками запускается один общий опрос биржи,
+// который останавливается, когда отписывается последний подписчик.
+type rateHub struct {
+	logger   *zap.Logger
+	fetch    fetchFunc
+	interval time.Duration
+
+	mu     sync.Mutex
+	feeds  map[string]*symbolFeed
+	closed bool
+}
+
+// symbolFeed - состояние опроса одного символа
+type symbolFeed struct {
+	cancel      context.CancelFunc
+	subscribers map[chan<- model.Rate]struct{}
+	last        *model.Rate
+}
+
+func newRateHub(logger *zap.Logger, fetch fetchFunc, interval time.Duration) *rateHub {
+	return &rateHub{
+		logger:   logger,
+		fetch:    fetch,
+		interval: interval,
+		feeds:    make(map[string]*symbolFeed),
+	}
+}
+
+// subscribe подписывает канал на обновления символа и возвращает функцию отписки.
+// Если по символу уже известен курс, он сразу отправляется в канал.
+func (h *rateHub) subscribe(symbol string, ch chan<- model.Rate) (func(), bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return func() {}, false
+	}
+
+	feed, ok := h.feeds[symbol]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		feed = &symbolFeed{
+			cancel:      cancel,
+			subscribers: make(map[chan<- model.Rate]struct{}),
+		}
+		h.feeds[symbol] = feed
+
+		go h.run(ctx, symbol, feed)
+		h.logger.Debug("Started shared rate feed", zap.String("symbol", symbol))
+	}
+
+	feed.subscribers[ch] = struct{}{}
+	if feed.last != nil {
+		trySend(ch, *feed.last)
+	}
+
+	return func() { h.unsubscribe(symbol, ch) }, true
+}
+
+func (h *rateHub) unsubscribe(symbol string, ch chan<- model.Rate) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	feed, ok := h.feeds[symbol]
+	if !ok {
+		return
+	}
+
+	delete(feed.subscribers, ch)
+	if len(feed.subscribers) == 0 {
+		feed.cancel()
+		delete(h.feeds, symbol)
+		h.logger.Debug("Stopped shared rate feed", zap.String("symbol", symbol))
+	}
+}
+
+// subscriptions возвращает число подписчиков по символам с активным опросом
+func (h *rateHub) subscriptions() map[string]int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := make(map[string]int, len(h.feeds))
+	for symbol, feed := range h.feeds {
+		result[symbol] = len(feed.subscribers)
+	}
+	return result
+}
+
+// close останавливает все опросы и запрещает новые подписки
+func (h *rateHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for symbol, feed := range h.feeds {
+		feed.cancel()
+		delete(h.feeds, symbol)
+	}
+}
+
+// run опрашивает биржу с заданным интервалом и рассылает курс,
+// только если изменилась верхушка стакана
+func (h *rateHub) run(ctx context.Context, symbol string, feed *symbolFeed) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.poll(ctx, symbol, feed)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *rateHub) poll(ctx context.Context, symbol string, feed *symbolFeed) {
+	ask, bid, timestamp, err := h.fetch(ctx, symbol)
+	if err != nil {
+		if ctx.Err() == nil {
+			h.logger.Warn("Failed to poll rate for subscribers", zap.Error(err), zap.String("symbol", symbol))
+		}
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+	if feed.last != nil && feed.last.Ask == ask && feed.last.Bid == bid {
+		return
+	}
+
+	rate := model.Rate{
+		Symbol:    symbol,
+		Ask:       ask,
+		Bid:       bid,
+		Timestamp: timestamp,
+	}
+	feed.last = &rate
+
+	for ch := range feed.subscribers {
+		trySend(ch, rate)
+	}
+}
+
+// trySend отправляет курс без блокировки: медленный подписчик пропускает
+// промежуточные обновления, но не тормозит остальных
+func trySend(ch chan<- model.Rate, rate model.Rate) {
+	select {
+	case ch <- rate:
+	default:
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
+)
+
+// Заглушка биржи, отдающая заранее заданную последовательность цен
+type fakeFeed struct {
+	mu     sync.Mutex
+	calls  map[string]int
+	prices []float64
+	err    error
+}
+
+func newFakeFeed(prices ...float64) *fakeFeed {
+	return &fakeFeed{calls: make(map[string]int), prices: prices}
+}
+
+func (f *fakeFeed) fetch(_ context.Context, symbol string) (float64, float64, time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return 0, 0, time.Time{}, f.err
+	}
+
+	i := f.calls[symbol]
+	f.calls[symbol]++
+	if i >= len(f.prices) {
+		i = len(f.prices) - 1
+	}
+	price := f.prices[i]
+
+	return price + 1, price, time.Now().UTC(), nil
+}
+
+func (f *fakeFeed) callCount(symbol string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[symbol]
+}
+
+func receive(t *testing.T, ch <-chan model.Rate) model.Rate {
+	t.Helper()
+	select {
+	case rate := <-ch:
+		return rate
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for rate update")
+		return model.Rate{}
+	}
+}
+
+func TestRateHub_PublishesOnlyChanges(t *testing.T) {
+	// Arrange: цена повторяется, затем меняется
+	feed := newFakeFeed(100, 100, 100, 105)
+	hub := newRateHub(zap.NewNop(), feed.fetch, 5*time.Millisecond)
+	defer hub.close()
+
+	ch := make(chan model.Rate, 10)
+
+	// Act
+	unsubscribe, ok := hub.subscribe("BTC-USDT", ch)
+	require.True(t, ok)
+	defer unsubscribe()
+
+	// Assert
+	first := receive(t, ch)
+	assert.Equal(t, "BTC-USDT", first.Symbol)
+	assert.Equal(t, 101.0, first.Ask)
+	assert.Equal(t, 100.0, first.Bid)
+
+	second := receive(t, ch)
+	assert.Equal(t, 106.0, second.Ask)
+	assert.Equal(t, 105.0, second.Bid)
+	assert.Eventually(t, func() bool { return feed.callCount("BTC-USDT") > 4 }, time.Second, time.Millisecond)
+
+	select {
+	case rate := <-ch:
+		t.Fatalf("unexpected update without price change: %+v", rate)
+	default:
+	}
+}
+
+func TestRateHub_SharesFeedBetweenSubscribers(t *testing.T) {
+	// Arrange
+	var fetches atomic.Int32
+	fetch := func(_ context.Context, _ string) (float64, float64, time.Time, error) {
+		n := fetches.Add(1)
+		return float64(n) + 1, float64(n), time.Now(), nil
+	}
+	hub := newRateHub(zap.NewNop(), fetch, time.Hour)
+	defer hub.close()
+
+	first := make(chan model.Rate, 1)
+	second := make(chan model.Rate, 1)
+
+	// Act
+	unsubscribeFirst, _ := hub.subscribe("BTC-USDT", first)
+	rate := receive(t, first)
+	unsubscribeSecond, _ := hub.subscribe("BTC-USDT", second)
+
+	// Assert: второй подписчик сразу получает последний известный курс без нового запроса
+	assert.Equal(t, rate, receive(t, second))
+	assert.Equal(t, int32(1), fetches.Load())
+
+	unsubscribeFirst()
+	unsubscribeSecond()
+
+	hub.mu.Lock()
+	assert.Empty(t, hub.feeds)
+	hub.mu.Unlock()
+}
+
+func TestRateHub_FetchErrorKeepsFeedRunning(t *testing.T) {
+	// Arrange
+	feed := newFakeFeed(100)
+	feed.err = errors.New("kucoin error")
+	hub := newRateHub(zap.NewNop(), feed.fetch, 5*time.Millisecond)
+	defer hub.close()
+
+	ch := make(chan model.Rate, 1)
+	unsubscribe, _ := hub.subscribe("BTC-USDT", ch)
+	defer unsubscribe()
+
+	// Act: биржа восстанавливается
+	time.Sleep(20 * time.Millisecond)
+	feed.mu.Lock()
+	feed.err = nil
+	feed.mu.Unlock()
+
+	// Assert
+	rate := receive(t, ch)
+	assert.Equal(t, 100.0, rate.Bid)
+}
+
+func TestRateHub_SubscribeAfterClose(t *testing.T) {
+	// Arrange
+	hub := newRateHub(zap.NewNop(), newFakeFeed(100).fetch, time.Hour)
+	hub.close()
+
+	// Act
+	_, ok := hub.subscribe("BTC-USDT", make(chan model.Rate, 1))
+
+	// Assert
+	assert.False(t, ok)
+}
+
+func TestSubscribeRates_ClosesOnContextCancel(t *testing.T) {
+	// Arrange
+	feed := newFakeFeed(100)
+	service := &RateService{
+		logger: zap.NewNop(),
+		hub:    newRateHub(zap.NewNop(), feed.fetch, time.Hour),
+		done:   make(chan struct{}),
+	}
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Act
+	updates, err := service.SubscribeRates(ctx, []string{"BTC-USDT", "ETH-USDT"})
+	require.NoError(t, err)
+
+	symbols := map[string]bool{}
+	symbols[receive(t, updates).Symbol] = true
+	symbols[receive(t, updates).Symbol] = true
+	cancel()
+
+	// Assert
+	assert.Equal(t, map[string]bool{"BTC-USDT": true, "ETH-USDT": true}, symbols)
+	assert.Eventually(t, func() bool {
+		_, ok := <-updates
+		return !ok
+	}, time.Second, time.Millisecond)
+}
+
+func TestSubscribeRates_AfterClose(t *testing.T) {
+	// Arrange
+	service := &RateService{
+		logger: zap.NewNop(),
+		hub:    newRateHub(zap.NewNop(), newFakeFeed(100).fetch, time.Hour),
+		done:   make(chan struct{}),
+	}
+	service.Close()
+
+	// Act
+	updates, err := service.SubscribeRates(context.Background(), []string{"BTC-USDT"})
+
+	// Assert
+	assert.ErrorIs(t, err, ErrServiceClosed)
+	assert.Nil(t, updates)
+}
+
+func TestNewRateService_NonPositivePollInterval(t *testing.T) {
+	// Arrange
+	mockExchange := new(MockExchange)
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(100.0, 99.0, time.Now(), nil)
+
+	service := NewRateService(zap.NewNop(), new(MockRateRepository), mockExchange, nil, Config{SubscribePollInterval: 0})
+	defer service.Close()
+
+	// Act: нулевой интервал не должен ронять опрос подписки
+	updates, err := service.SubscribeRates(context.Background(), []string{"BTC-USDT"})
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, defaultSubscribePollInterval, service.hub.interval)
+	assert.Equal(t, "BTC-USDT", receive(t, updates).Symbol)
+}
@@ -2,8 +2,8 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -11,76 +11,199 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange/kucoin"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/exchange"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/model"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/internal/repository"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
 	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/telemetry"
 )
 
+const (
+	// subscriberBuffer - размер буфера канала обновлений на один символ подписки
+	subscriberBuffer = 16
+	// defaultSubscribePollInterval - интервал опроса подписок, если в конфигурации он не положительный
+	defaultSubscribePollInterval = time.Second
+	// BookDepth - число уровней стакана в GetRatesWithBook
+	BookDepth = 100
+)
+
+// ErrServiceClosed возвращается при подписке на курсы после остановки сервиса
+var ErrServiceClosed = errors.New("rate service is closed")
+
+// QuoteFeed - источник лучших цен в реальном времени, например WebSocket биржи.
+// ok == false означает, что свежей цены по символу нет.
+type QuoteFeed interface {
+	BestQuote(symbol string) (ask float64, bid float64, timestamp time.Time, ok bool)
+}
+
+// Config содержит настройки сервиса курсов
+type Config struct {
+	// SubscribePollInterval - интервал опроса биржи для потоковых подписок
+	SubscribePollInterval time.Duration
+	// QuoteCacheMaxAge - сколько GetRates отдает цену из кэша без запроса к бирже; 0 отключает кэш
+	QuoteCacheMaxAge time.Duration
+	// StaleFallbackMaxAge - максимальный возраст сохраненного курса, которым GetRates
+	// отвечает при недоступности биржи; 0 отключает такой ответ
+	StaleFallbackMaxAge time.Duration
+	// BatchConcurrency - максимальное число одновременных запросов к бирже в GetRatesBatch
+	BatchConcurrency int
+	// CrossRateMaxLegs - максимальное число пар в пути кросс-курса; 0 отключает кросс-курсы
+	CrossRateMaxLegs int
+	// CrossRateHubs - валюты, через которые кросс-курс считается в первую очередь
+	CrossRateHubs []string
+	// PairGraphMaxAge - как часто перезагружается список пар биржи для кросс-курсов
+	PairGraphMaxAge time.Duration
+	// Symbols - справочник пар; если задан, граф кросс-курсов строится по нему
+	// вместо отдельной загрузки списка пар с биржи
+	Symbols SymbolSource
+}
+
 type RateService struct {
-	logger       *zap.Logger
-	repo         repository.RateRepository
-	kuCoinClient *kucoin.KuCoinClient
-	tracer       trace.Tracer
+	logger    *zap.Logger
+	repo      repository.RateRepository
+	exchange  exchange.Exchange
+	quoteFeed QuoteFeed
+	tracer    trace.Tracer
+	hub       *rateHub
+	cache     *quoteCache[model.Quote]
+	books     *quoteCache[*model.OrderBook]
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// pairGraph - граф пар биржи для кросс-курсов; nil, если они отключены
+	pairGraph *pairGraphCache
+
+	staleFallbackMaxAge time.Duration
+	batchConcurrency    int
+	crossRateMaxLegs    int
 }
 
-func NewRateService(logger *zap.Logger, repo repository.RateRepository, kuCoinClient *kucoin.KuCoinClient) *RateService {
-	return &RateService{
-		logger:       logger,
-		repo:         repo,
-		kuCoinClient: kuCoinClient,
-		tracer:       otel.Tracer("rate-service"),
+// NewRateService создает сервис курсов. quoteFeed может быть nil,
+// тогда все цены запрашиваются через REST API биржи. Кросс-курсы работают,
+// только если задан справочник пар или биржа умеет отдавать список пар (exchange.SymbolLister).
+func NewRateService(
+	logger *zap.Logger,
+	repo repository.RateRepository,
+	rateExchange exchange.Exchange,
+	quoteFeed QuoteFeed,
+	config Config,
+) *RateService {
+	if config.BatchConcurrency <= 0 {
+		config.BatchConcurrency = 1
+	}
+	// time.NewTicker паникует на неположительном интервале
+	if config.SubscribePollInterval <= 0 {
+		config.SubscribePollInterval = defaultSubscribePollInterval
+	}
+
+	s := &RateService{
+		logger:    logger,
+		repo:      repo,
+		exchange:  rateExchange,
+		quoteFeed: quoteFeed,
+		tracer:    otel.Tracer("rate-service"),
+		done:      make(chan struct{}),
+
+		staleFallbackMaxAge: config.StaleFallbackMaxAge,
+		batchConcurrency:    config.BatchConcurrency,
+		crossRateMaxLegs:    config.CrossRateMaxLegs,
+	}
+	if config.CrossRateMaxLegs > 0 {
+		if config.Symbols != nil {
+			s.pairGraph = newSourcePairGraphCache(config.Symbols, config.CrossRateHubs)
+		} else if lister, ok := rateExchange.(exchange.SymbolLister); ok {
+			s.pairGraph = newPairGraphCache(lister, config.CrossRateHubs, config.PairGraphMaxAge)
+		}
 	}
+	s.hub = newRateHub(logger, s.fetchOrderBook, config.SubscribePollInterval)
+	s.cache = newQuoteCache(config.QuoteCacheMaxAge, s.fetchQuote, s.saveQuote)
+	s.books = newQuoteCache(config.QuoteCacheMaxAge, s.fetchBook, func(ctx context.Context, book *model.OrderBook) {
+		s.saveQuote(ctx, quoteFromBook(book.Symbol, book, model.QuoteSourceExchange))
+	})
+
+	return s
 }
 
-func (s *RateService) GetRates(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+// GetRates возвращает лучшие цены по символу. Если биржа недоступна и включен
+// StaleFallbackMaxAge, отдается последний сохраненный курс с пометкой Stale.
+// Если пара не торгуется на бирже, курс рассчитывается через промежуточные пары.
+func (s *RateService) GetRates(ctx context.Context, symbol string) (model.Quote, error) {
 	// Создаем спан для трассировки
 	ctx, span := s.tracer.Start(ctx, "RateService.GetRates",
 		trace.WithAttributes(attribute.String("symbol", symbol)))
 	defer span.End()
 
-	ask, bid, timestamp, err := s.kuCoinClient.GetOrderBook(ctx, symbol)
+	quote, fetched, err := s.resolveQuote(ctx, symbol)
 	if err != nil {
-		s.logger.Error("Failed to get order book", zap.Error(err), zap.String("symbol", symbol))
+		return model.Quote{}, err
+	}
 
-		// Отмечаем ошибку в трассировке
-		span.SetStatus(codes.Error, "Failed to get order book")
-		span.RecordError(err)
+	// Курс из кэша или из чужого запроса к бирже уже сохранен, повторно не пишем
+	if !fetched {
+		return quote, nil
+	}
 
-		// Обновляем метрику
-		telemetry.RateFetchCounter.WithLabelValues(symbol, "error").Inc()
+	s.saveQuote(ctx, quote)
 
-		return 0, 0, time.Time{}, err
+	return quote, nil
+}
+
+// GetRatesWithBook возвращает лучшие цены и стакан глубиной BookDepth из одного снимка,
+// чтобы курс и рассчитанный по стакану VWAP не расходились. Если биржа недоступна
+// и включен StaleFallbackMaxAge, отдается сохраненный курс без стакана (book == nil).
+// Для кросс-курса стакана тоже нет.
+func (s *RateService) GetRatesWithBook(ctx context.Context, symbol string) (model.Quote, *model.OrderBook, error) {
+	ctx, span := s.tracer.Start(ctx, "RateService.GetRatesWithBook",
+		trace.WithAttributes(attribute.String("symbol", symbol)))
+	defer span.End()
+
+	if path, cross := s.crossPath(ctx, symbol); cross {
+		quote, err := s.getCrossQuote(ctx, symbol, path)
+		return quote, nil, err
 	}
 
-	// Обновляем информацию в спане
-	span.SetAttributes(
-		attribute.Float64("ask", ask),
-		attribute.Float64("bid", bid),
-		attribute.String("timestamp", timestamp.Format(time.RFC3339)),
-	)
+	book, hit, fetched, err := s.books.get(ctx, symbol)
+	s.recordCacheLookup(span, symbol, hit)
+	if err != nil {
+		quote, _, err := s.handleFetchError(ctx, span, symbol, err)
+		return quote, nil, err
+	}
 
-	// Обновляем метрику успешного получения курса
-	telemetry.RateFetchCounter.WithLabelValues(symbol, "success").Inc()
+	source := model.QuoteSourceExchange
+	if hit {
+		source = model.QuoteSourceCache
+	}
+	quote := quoteFromBook(symbol, book, source)
+	s.recordQuote(span, symbol, quote)
 
+	if fetched {
+		s.saveQuote(ctx, quote)
+	}
+
+	return quote, book, nil
+}
+
+// saveQuote сохраняет полученный с биржи курс. Ошибка сохранения только логируется,
+// чтобы клиент все равно получил данные о курсе.
+func (s *RateService) saveQuote(ctx context.Context, quote model.Quote) {
 	// Сохраняем данные о курсе в БД
 	rate := model.Rate{
-		Symbol:    symbol,
-		Ask:       ask,
-		Bid:       bid,
-		Timestamp: timestamp,
+		Symbol:    quote.Symbol,
+		Ask:       quote.Ask,
+		Bid:       quote.Bid,
+		Timestamp: quote.Timestamp,
 		CreatedAt: time.Now(),
 	}
 
 	// Создаем вложенный спан для сохранения в БД
 	ctxSave, spanSave := s.tracer.Start(ctx, "RateService.SaveRate")
 	if err := s.repo.SaveRate(ctxSave, rate); err != nil {
-		s.logger.Error("Failed to save rate",
+		logger.WithContext(ctx, s.logger).Error("Failed to save rate",
 			zap.Error(err),
-			zap.String("symbol", symbol),
-			zap.Float64("ask", ask),
-			zap.Float64("bid", bid),
-			zap.Time("timestamp", timestamp))
+			zap.String("symbol", quote.Symbol),
+			zap.Float64("ask", quote.Ask),
+			zap.Float64("bid", quote.Bid),
+			zap.Time("timestamp", quote.Timestamp))
 
 		// Отмечаем ошибку в трассировке
 		spanSave.SetStatus(codes.Error, "Failed to save rate to database")
@@ -88,33 +211,445 @@ func (s *RateService) GetRates(ctx context.Context, symbol string) (float64, flo
 
 		// Не возвращаем ошибку, чтобы клиент все равно получил данные о курсе
 	} else {
-		s.logger.Info("Successfully saved rate to database",
-			zap.String("symbol", symbol),
-			zap.Float64("ask", ask),
-			zap.Float64("bid", bid))
+		logger.WithContext(ctx, s.logger).Info("Successfully saved rate to database",
+			zap.String("symbol", quote.Symbol),
+			zap.Float64("ask", quote.Ask),
+			zap.Float64("bid", quote.Bid))
 
 		spanSave.SetStatus(codes.Ok, "Successfully saved rate to database")
 	}
 	spanSave.End()
+}
 
-	return ask, bid, timestamp, nil
+// GetOrderBook возвращает до depth верхних уровней стакана с биржи
+func (s *RateService) GetOrderBook(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	ctx, span := s.tracer.Start(ctx, "RateService.GetOrderBook",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.Int("depth", depth),
+		))
+	defer span.End()
+
+	book, err := s.exchange.GetOrderBookDepth(ctx, symbol, depth)
+	if err != nil {
+		logger.WithContext(ctx, s.logger).Error("Failed to get order book depth", zap.Error(err), zap.String("symbol", symbol))
+		span.SetStatus(codes.Error, "Failed to get order book depth")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("exchange", book.Exchange),
+		attribute.Int64("sequence", book.Sequence),
+	)
+	span.SetStatus(codes.Ok, "Order book retrieved")
+
+	return book, nil
 }
 
-func (s *RateService) HealthCheck(ctx context.Context) bool {
-	// Создаем спан для трассировки
-	ctx, span := s.tracer.Start(ctx, "RateService.HealthCheck")
+// QuoteResult - результат получения курса по одному символу пакетного запроса
+type QuoteResult struct {
+	Symbol string
+	Quote  model.Quote
+	Err    error
+}
+
+// GetRatesBatch получает курсы по нескольким символам параллельно, не более
+// BatchConcurrency запросов одновременно. Ошибка по символу не прерывает остальные.
+// Полученные с биржи курсы сохраняются одним запросом.
+func (s *RateService) GetRatesBatch(ctx context.Context, symbols []string) []QuoteResult {
+	ctx, span := s.tracer.Start(ctx, "RateService.GetRatesBatch",
+		trace.WithAttributes(attribute.Int("symbols", len(symbols))))
+	defer span.End()
+
+	results := make([]QuoteResult, len(symbols))
+	fetched := make([]bool, len(symbols))
+
+	workers := s.batchConcurrency
+	if workers > len(symbols) {
+		workers = len(symbols)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				symbol := symbols[i]
+				symbolCtx, symbolSpan := s.tracer.Start(ctx, "RateService.GetQuote",
+					trace.WithAttributes(attribute.String("symbol", symbol)))
+				quote, isFetched, err := s.resolveQuote(symbolCtx, symbol)
+				symbolSpan.End()
+
+				results[i] = QuoteResult{Symbol: symbol, Quote: quote, Err: err}
+				fetched[i] = isFetched
+			}
+		}()
+	}
+
+	for i := range symbols {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Сохраняем только курсы, которые этот запрос сам получил с биржи
+	rates := make([]model.Rate, 0, len(symbols))
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+			continue
+		}
+		if !fetched[i] {
+			continue
+		}
+		rates = append(rates, model.Rate{
+			Symbol:    result.Symbol,
+			Ask:       result.Quote.Ask,
+			Bid:       result.Quote.Bid,
+			Timestamp: result.Quote.Timestamp,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	if err := s.repo.SaveRates(ctx, rates); err != nil {
+		// Как и в GetRates, клиент все равно получает курсы
+		logger.WithContext(ctx, s.logger).Error("Failed to save rates", zap.Error(err), zap.Int("count", len(rates)))
+		span.RecordError(err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("failed", failed),
+		attribute.Int("saved", len(rates)),
+	)
+	span.SetStatus(codes.Ok, "Batch processed")
+
+	return results
+}
+
+// getQuote берет курс из кэша или с биржи, а при ошибке биржи - из последних
+// сохраненных. fetched == true, если курс получен с биржи этим вызовом и его нужно сохранить.
+func (s *RateService) getQuote(ctx context.Context, symbol string) (model.Quote, bool, error) {
+	span := trace.SpanFromContext(ctx)
+
+	quote, hit, fetched, err := s.cache.get(ctx, symbol)
+	s.recordCacheLookup(span, symbol, hit)
+	if err != nil {
+		return s.handleFetchError(ctx, span, symbol, err)
+	}
+
+	if hit {
+		quote.Source = model.QuoteSourceCache
+	}
+	s.recordQuote(span, symbol, quote)
+
+	return quote, fetched, nil
+}
+
+// recordCacheLookup обновляет метрики кэша и отмечает попадание в спане
+func (s *RateService) recordCacheLookup(span trace.Span, symbol string, hit bool) {
+	if hit {
+		telemetry.QuoteCacheHitCounter.WithLabelValues(symbol).Inc()
+	} else {
+		telemetry.QuoteCacheMissCounter.WithLabelValues(symbol).Inc()
+	}
+	span.SetAttributes(attribute.Bool("cache_hit", hit))
+}
+
+// recordQuote добавляет полученный курс в спан и учитывает его в метрике
+func (s *RateService) recordQuote(span trace.Span, symbol string, quote model.Quote) {
+	// Обновляем информацию в спане
+	span.SetAttributes(
+		attribute.Float64("ask", quote.Ask),
+		attribute.Float64("bid", quote.Bid),
+		attribute.String("timestamp", quote.Timestamp.Format(time.RFC3339)),
+		attribute.String("source", string(quote.Source)),
+	)
+
+	// Обновляем метрику успешного получения курса
+	telemetry.RateFetchCounter.WithLabelValues(symbol, "success").Inc()
+}
+
+// handleFetchError пробует ответить последним сохраненным курсом вместо ошибки биржи
+func (s *RateService) handleFetchError(
+	ctx context.Context,
+	span trace.Span,
+	symbol string,
+	err error,
+) (model.Quote, bool, error) {
+	logger.WithContext(ctx, s.logger).Error("Failed to get order book", zap.Error(err), zap.String("symbol", symbol))
+
+	// Отмечаем ошибку в трассировке
+	span.SetStatus(codes.Error, "Failed to get order book")
+	span.RecordError(err)
+
+	// Пробуем ответить последним сохраненным курсом
+	if staleQuote, ok := s.staleQuote(ctx, symbol); ok {
+		telemetry.RateFetchCounter.WithLabelValues(symbol, "stale").Inc()
+		span.SetAttributes(attribute.Bool("stale", true))
+		return staleQuote, false, nil
+	}
+
+	// Обновляем метрику
+	telemetry.RateFetchCounter.WithLabelValues(symbol, "error").Inc()
+
+	return model.Quote{}, false, err
+}
+
+// staleQuote возвращает последний сохраненный курс, если он не старше StaleFallbackMaxAge
+func (s *RateService) staleQuote(ctx context.Context, symbol string) (model.Quote, bool) {
+	if s.staleFallbackMaxAge <= 0 {
+		return model.Quote{}, false
+	}
+
+	ctx, span := s.tracer.Start(ctx, "RateService.GetLatestRate")
+	defer span.End()
+
+	rate, err := s.repo.GetLatestRate(ctx, symbol)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			logger.WithContext(ctx, s.logger).Error("Failed to get latest rate for fallback", zap.Error(err), zap.String("symbol", symbol))
+			span.SetStatus(codes.Error, "Failed to get latest rate")
+			span.RecordError(err)
+		}
+		return model.Quote{}, false
+	}
+
+	age := time.Since(rate.Timestamp)
+	if age > s.staleFallbackMaxAge {
+		logger.WithContext(ctx, s.logger).Debug("Stored rate is too old for fallback",
+			zap.String("symbol", symbol),
+			zap.Duration("age", age))
+		return model.Quote{}, false
+	}
+
+	logger.WithContext(ctx, s.logger).Warn("Exchange unavailable, serving last stored rate",
+		zap.String("symbol", symbol),
+		zap.Duration("age", age))
+
+	return model.Quote{
+		Symbol:    symbol,
+		Ask:       rate.Ask,
+		Bid:       rate.Bid,
+		Timestamp: rate.Timestamp,
+		Source:    model.QuoteSourceStorage,
+		Stale:     true,
+	}, true
+}
+
+// GetRateHistory возвращает страницу сохраненных курсов за интервал [from, to)
+// и курсор следующей страницы (nil, если страниц больше нет)
+func (s *RateService) GetRateHistory(
+	ctx context.Context,
+	symbol string,
+	from, to time.Time,
+	pageSize int,
+	after *model.RateCursor,
+) ([]model.Rate, *model.RateCursor, error) {
+	ctx, span := s.tracer.Start(ctx, "RateService.GetRateHistory",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.Int("page_size", pageSize),
+		))
+	defer span.End()
+
+	// Запрашиваем на одну запись больше, чтобы узнать, есть ли следующая страница
+	rates, err := s.repo.GetRateHistory(ctx, repository.HistoryQuery{
+		Symbol: symbol,
+		From:   from,
+		To:     to,
+		After:  after,
+		Limit:  pageSize + 1,
+	})
+	if err != nil {
+		logger.WithContext(ctx, s.logger).Error("Failed to get rate history", zap.Error(err), zap.String("symbol", symbol))
+		span.SetStatus(codes.Error, "Failed to get rate history")
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	var next *model.RateCursor
+	if len(rates) > pageSize {
+		rates = rates[:pageSize]
+		last := rates[len(rates)-1]
+		next = &model.RateCursor{Timestamp: last.Timestamp, ID: last.ID}
+	}
+
+	span.SetAttributes(attribute.Int("count", len(rates)))
+	span.SetStatus(codes.Ok, "Rate history retrieved")
+
+	return rates, next, nil
+}
+
+// GetCandles агрегирует сохраненные котировки в свечи заданного интервала за [from, to)
+func (s *RateService) GetCandles(
+	ctx context.Context,
+	symbol string,
+	interval time.Duration,
+	from, to time.Time,
+) ([]model.Candle, error) {
+	ctx, span := s.tracer.Start(ctx, "RateService.GetCandles",
+		trace.WithAttributes(
+			attribute.String("symbol", symbol),
+			attribute.String("interval", interval.String()),
+		))
 	defer span.End()
 
-	// Проверяем доступность репозитория
-	_, err := s.repo.GetLatestRate(ctx, "BTC-USDT")
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		s.logger.Error("Repository health check failed", zap.Error(err))
-		span.SetStatus(codes.Error, "Repository health check failed")
+	candles, err := s.repo.GetCandles(ctx, repository.CandleQuery{
+		Symbol:   symbol,
+		Interval: interval,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		logger.WithContext(ctx, s.logger).Error("Failed to get candles", zap.Error(err), zap.String("symbol", symbol))
+		span.SetStatus(codes.Error, "Failed to get candles")
 		span.RecordError(err)
-		return false
+		return nil, err
 	}
 
-	span.SetStatus(codes.Ok, "Health check successful")
+	span.SetAttributes(attribute.Int("count", len(candles)))
+	span.SetStatus(codes.Ok, "Candles retrieved")
+
+	return candles, nil
+}
+
+// fetchQuote берет лучшие цены из потока реального времени,
+// а при его отсутствии или устаревании - из REST API биржи
+func (s *RateService) fetchQuote(ctx context.Context, symbol string) (model.Quote, error) {
+	if s.quoteFeed != nil {
+		if ask, bid, timestamp, ok := s.quoteFeed.BestQuote(symbol); ok {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("source", "websocket"))
+			return model.Quote{
+				Symbol:    symbol,
+				Ask:       ask,
+				Bid:       bid,
+				Timestamp: timestamp,
+				Source:    model.QuoteSourceStream,
+			}, nil
+		}
+	}
+
+	ask, bid, timestamp, err := s.exchange.GetOrderBook(ctx, symbol)
+	if err != nil {
+		return model.Quote{}, err
+	}
+
+	return model.Quote{
+		Symbol:    symbol,
+		Ask:       ask,
+		Bid:       bid,
+		Timestamp: timestamp,
+		Source:    model.QuoteSourceExchange,
+	}, nil
+}
+
+// fetchBook запрашивает стакан глубиной BookDepth для GetRatesWithBook
+func (s *RateService) fetchBook(ctx context.Context, symbol string) (*model.OrderBook, error) {
+	book, err := s.exchange.GetOrderBookDepth(ctx, symbol, BookDepth)
+	if err != nil {
+		return nil, err
+	}
+	if len(book.Asks) == 0 || len(book.Bids) == 0 {
+		return nil, exchange.NewError(s.exchange.Name(), exchange.ErrBadResponse, errors.New("empty order book data"))
+	}
+	return book, nil
+}
+
+// quoteFromBook берет лучшие цены из верхних уровней стакана
+func quoteFromBook(symbol string, book *model.OrderBook, source model.QuoteSource) model.Quote {
+	return model.Quote{
+		Symbol:    symbol,
+		Ask:       book.Asks[0].Price,
+		Bid:       book.Bids[0].Price,
+		Timestamp: book.Timestamp,
+		Source:    source,
+	}
+}
+
+// fetchOrderBook - fetchQuote в формате, который использует опрос подписок
+func (s *RateService) fetchOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	quote, err := s.fetchQuote(ctx, symbol)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+	return quote.Ask, quote.Bid, quote.Timestamp, nil
+}
+
+// SubscribeRates подписывает клиента на изменения курсов по списку символов.
+// Канал закрывается после отмены контекста или остановки сервиса.
+func (s *RateService) SubscribeRates(ctx context.Context, symbols []string) (<-chan model.Rate, error) {
+	updates := make(chan model.Rate, len(symbols)*subscriberBuffer)
+
+	unsubscribes := make([]func(), 0, len(symbols))
+	unsubscribeAll := func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+
+	for _, symbol := range symbols {
+		unsubscribe, ok := s.hub.subscribe(symbol, updates)
+		if !ok {
+			unsubscribeAll()
+			return nil, ErrServiceClosed
+		}
+		unsubscribes = append(unsubscribes, unsubscribe)
+	}
+
+	logger.WithContext(ctx, s.logger).Debug("Client subscribed to rates", zap.Strings("symbols", symbols))
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+		}
+
+		// После отписки хаб больше не пишет в канал, поэтому его можно закрыть
+		unsubscribeAll()
+		close(updates)
+
+		logger.WithContext(ctx, s.logger).Debug("Client unsubscribed from rates", zap.Strings("symbols", symbols))
+	}()
+
+	return updates, nil
+}
+
+// CacheEntry - запись кэша курсов или стаканов по символу
+type CacheEntry struct {
+	Symbol    string
+	FetchedAt time.Time
+	// Fresh - запись моложе QuoteCacheMaxAge и будет отдана без запроса к бирже
+	Fresh bool
+}
+
+// CacheSnapshot - содержимое кэшей и общих опросов биржи для диагностики
+type CacheSnapshot struct {
+	MaxAge     time.Duration
+	Quotes     []CacheEntry
+	OrderBooks []CacheEntry
+	// Subscriptions - число подписчиков SubscribeRates по символам
+	Subscriptions map[string]int
+}
+
+// CacheSnapshot возвращает текущее содержимое кэшей и подписок
+func (s *RateService) CacheSnapshot() CacheSnapshot {
+	now := time.Now()
+	return CacheSnapshot{
+		MaxAge:        s.cache.maxAge,
+		Quotes:        s.cache.snapshot(now),
+		OrderBooks:    s.books.snapshot(now),
+		Subscriptions: s.hub.subscriptions(),
+	}
+}
 
-	return true
+// Close останавливает общие опросы биржи и завершает активные подписки
+func (s *RateService) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.hub.close()
+	})
 }
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -23,6 +22,11 @@ func (m *MockRateRepository) SaveRate(ctx context.Context, rate model.Rate) erro
 	return args.Error(0)
 }
 
+func (m *MockRateRepository) SaveRates(ctx context.Context, rates []model.Rate) error {
+	args := m.Called(ctx, rates)
+	return args.Error(0)
+}
+
 func (m *MockRateRepository) GetLatestRate(ctx context.Context, symbol string) (*model.Rate, error) {
 	args := m.Called(ctx, symbol)
 	if args.Get(0) == nil {
@@ -31,93 +35,59 @@ func (m *MockRateRepository) GetLatestRate(ctx context.Context, symbol string) (
 	return args.Get(0).(*model.Rate), args.Error(1)
 }
 
+func (m *MockRateRepository) GetRateHistory(ctx context.Context, query repository.HistoryQuery) ([]model.Rate, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Rate), args.Error(1)
+}
+
+func (m *MockRateRepository) GetCandles(ctx context.Context, query repository.CandleQuery) ([]model.Candle, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Candle), args.Error(1)
+}
+
 func (m *MockRateRepository) Close() error {
 	args := m.Called()
 	return args.Error(0)
 }
 
-// Мок для KuCoin клиента
-type MockKuCoinClient struct {
+// Мок для биржи
+type MockExchange struct {
 	mock.Mock
 }
 
-func (m *MockKuCoinClient) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
-	args := m.Called(ctx, symbol)
-	return args.Get(0).(float64), args.Get(1).(float64), args.Get(2).(time.Time), args.Error(3)
-}
-
-// Тестовая структура для внедрения мока KuCoin клиента
-type testRateService struct {
-	RateService
-	mockKuCoin *MockKuCoinClient
+func (m *MockExchange) Name() string {
+	return "mock"
 }
 
-func newTestRateService(logger *zap.Logger, repo repository.RateRepository) *testRateService {
-	mockKuCoin := new(MockKuCoinClient)
-	return &testRateService{
-		RateService: RateService{
-			logger: logger,
-			repo:   repo,
-			// kuCoinClient поле не инициализируем, так как переопределяем его методы
-		},
-		mockKuCoin: mockKuCoin,
-	}
+func (m *MockExchange) GetOrderBook(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
+	args := m.Called(ctx, symbol)
+	return args.Get(0).(float64), args.Get(1).(float64), args.Get(2).(time.Time), args.Error(3)
 }
 
-func (s *testRateService) GetRates(ctx context.Context, symbol string) (float64, float64, time.Time, error) {
-	ask, bid, timestamp, err := s.mockKuCoin.GetOrderBook(ctx, symbol)
-	if err != nil {
-		s.logger.Error("Failed to get order book", zap.Error(err), zap.String("symbol", symbol))
-		return 0, 0, time.Time{}, err
-	}
-
-	// Сохраняем данные о курсе в БД
-	rate := model.Rate{
-		Symbol:    symbol,
-		Ask:       ask,
-		Bid:       bid,
-		Timestamp: timestamp,
-		CreatedAt: time.Now(),
-	}
-
-	if err := s.repo.SaveRate(ctx, rate); err != nil {
-		s.logger.Error("Failed to save rate",
-			zap.Error(err),
-			zap.String("symbol", symbol),
-			zap.Float64("ask", ask),
-			zap.Float64("bid", bid),
-			zap.Time("timestamp", timestamp))
+func (m *MockExchange) GetOrderBookDepth(ctx context.Context, symbol string, depth int) (*model.OrderBook, error) {
+	args := m.Called(ctx, symbol, depth)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
 	}
-
-	return ask, bid, timestamp, nil
+	return args.Get(0).(*model.OrderBook), args.Error(1)
 }
 
-func (s *testRateService) HealthCheck(ctx context.Context) bool {
-	// Проверяем соединение с базой данных
-	_, err := s.repo.GetLatestRate(ctx, "BTC-USDT")
-	if err != nil {
-		// Пустая база не считается ошибкой, только проверяем, есть ли соединение с БД
-		if !errors.Is(err, sql.ErrNoRows) {
-			s.logger.Error("Health check failed: database connection error", zap.Error(err))
-			return false
-		}
-	}
-
-	// Проверяем соединение с API KuCoin
-	_, _, _, err = s.mockKuCoin.GetOrderBook(ctx, "BTC-USDT")
-	if err != nil {
-		s.logger.Error("Health check failed: KuCoin API connection error", zap.Error(err))
-		return false
-	}
-
-	return true
+func newTestRateService(repo repository.RateRepository) (*RateService, *MockExchange) {
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), repo, mockExchange, nil, Config{SubscribePollInterval: time.Second})
+	return service, mockExchange
 }
 
 func TestGetRates(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	service, mockExchange := newTestRateService(mockRepo)
 
 	ctx := context.Background()
 	symbol := "BTC-USDT"
@@ -125,57 +95,55 @@ func TestGetRates(t *testing.T) {
 	bid := 39999.5
 	timestamp := time.Now().UTC()
 
-	// Настраиваем мок KuCoin клиента
-	service.mockKuCoin.On("GetOrderBook", ctx, symbol).Return(ask, bid, timestamp, nil)
+	// Настраиваем мок биржи
+	mockExchange.On("GetOrderBook", mock.Anything, symbol).Return(ask, bid, timestamp, nil)
 
 	// Настраиваем мок репозитория
-	mockRepo.On("SaveRate", ctx, mock.MatchedBy(func(rate model.Rate) bool {
+	mockRepo.On("SaveRate", mock.Anything, mock.MatchedBy(func(rate model.Rate) bool {
 		return rate.Symbol == symbol && rate.Ask == ask && rate.Bid == bid && rate.Timestamp == timestamp
 	})).Return(nil)
 
 	// Act
-	resultAsk, resultBid, resultTimestamp, err := service.GetRates(ctx, symbol)
+	quote, err := service.GetRates(ctx, symbol)
 
 	// Assert
 	assert.NoError(t, err)
-	assert.Equal(t, ask, resultAsk)
-	assert.Equal(t, bid, resultBid)
-	assert.Equal(t, timestamp, resultTimestamp)
+	assert.Equal(t, ask, quote.Ask)
+	assert.Equal(t, bid, quote.Bid)
+	assert.Equal(t, timestamp, quote.Timestamp)
 
-	service.mockKuCoin.AssertExpectations(t)
+	mockExchange.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestGetRates_KuCoinError(t *testing.T) {
+func TestGetRates_ExchangeError(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	service, mockExchange := newTestRateService(mockRepo)
 
 	ctx := context.Background()
 	symbol := "BTC-USDT"
 	expectedErr := errors.New("kucoin error")
 
-	// Настраиваем мок KuCoin клиента с ошибкой
-	service.mockKuCoin.On("GetOrderBook", ctx, symbol).Return(0.0, 0.0, time.Time{}, expectedErr)
+	// Настраиваем мок биржи с ошибкой
+	mockExchange.On("GetOrderBook", mock.Anything, symbol).Return(0.0, 0.0, time.Time{}, expectedErr)
 
 	// Act
-	_, _, _, err := service.GetRates(ctx, symbol)
+	_, err := service.GetRates(ctx, symbol)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 
-	service.mockKuCoin.AssertExpectations(t)
-	// Репозиторий не должен быть вызван при ошибке KuCoin
+	mockExchange.AssertExpectations(t)
+	// Репозиторий не должен быть вызван при ошибке биржи
 	mockRepo.AssertNotCalled(t, "SaveRate")
 }
 
 func TestGetRates_RepoError(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	service, mockExchange := newTestRateService(mockRepo)
 
 	ctx := context.Background()
 	symbol := "BTC-USDT"
@@ -184,117 +152,358 @@ func TestGetRates_RepoError(t *testing.T) {
 	timestamp := time.Now().UTC()
 	repoError := errors.New("database error")
 
-	// Настраиваем мок KuCoin клиента
-	service.mockKuCoin.On("GetOrderBook", ctx, symbol).Return(ask, bid, timestamp, nil)
+	// Настраиваем мок биржи
+	mockExchange.On("GetOrderBook", mock.Anything, symbol).Return(ask, bid, timestamp, nil)
 
 	// Настраиваем мок репозитория с ошибкой
-	mockRepo.On("SaveRate", ctx, mock.MatchedBy(func(rate model.Rate) bool {
+	mockRepo.On("SaveRate", mock.Anything, mock.MatchedBy(func(rate model.Rate) bool {
 		return rate.Symbol == symbol && rate.Ask == ask && rate.Bid == bid && rate.Timestamp == timestamp
 	})).Return(repoError)
 
 	// Act
-	resultAsk, resultBid, resultTimestamp, err := service.GetRates(ctx, symbol)
+	quote, err := service.GetRates(ctx, symbol)
 
 	// Assert
 	assert.NoError(t, err) // Ошибка сохранения не возвращается клиенту
-	assert.Equal(t, ask, resultAsk)
-	assert.Equal(t, bid, resultBid)
-	assert.Equal(t, timestamp, resultTimestamp)
+	assert.Equal(t, ask, quote.Ask)
+	assert.Equal(t, bid, quote.Bid)
+	assert.Equal(t, timestamp, quote.Timestamp)
 
-	service.mockKuCoin.AssertExpectations(t)
+	mockExchange.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestHealthCheck_AllOk(t *testing.T) {
+func TestGetRates_StaleFallback(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		StaleFallbackMaxAge:   time.Minute,
+	})
 
 	ctx := context.Background()
-	rate := &model.Rate{Symbol: "BTC-USDT", Ask: 40000.5, Bid: 39999.5}
+	stored := &model.Rate{Symbol: "BTC-USDT", Ask: 101, Bid: 100, Timestamp: time.Now().Add(-10 * time.Second)}
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(0.0, 0.0, time.Time{}, errors.New("kucoin error"))
+	mockRepo.On("GetLatestRate", mock.Anything, "BTC-USDT").Return(stored, nil)
 
-	// Настраиваем мок репозитория
-	mockRepo.On("GetLatestRate", ctx, "BTC-USDT").Return(rate, nil)
+	// Act
+	quote, err := service.GetRates(ctx, "BTC-USDT")
 
-	// Настраиваем мок KuCoin клиента
-	service.mockKuCoin.On("GetOrderBook", ctx, "BTC-USDT").Return(40000.5, 39999.5, time.Now(), nil)
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 101.0, quote.Ask)
+	assert.Equal(t, 100.0, quote.Bid)
+	assert.Equal(t, stored.Timestamp, quote.Timestamp)
+	assert.Equal(t, model.QuoteSourceStorage, quote.Source)
+	assert.True(t, quote.Stale)
+
+	// Устаревший курс не должен сохраняться повторно
+	mockRepo.AssertNotCalled(t, "SaveRate", mock.Anything, mock.Anything)
+}
+
+func TestGetRates_StaleFallbackTooOld(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		StaleFallbackMaxAge:   time.Minute,
+	})
+
+	ctx := context.Background()
+	expectedErr := errors.New("kucoin error")
+	stored := &model.Rate{Symbol: "BTC-USDT", Ask: 101, Bid: 100, Timestamp: time.Now().Add(-time.Hour)}
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(0.0, 0.0, time.Time{}, expectedErr)
+	mockRepo.On("GetLatestRate", mock.Anything, "BTC-USDT").Return(stored, nil)
 
 	// Act
-	result := service.HealthCheck(ctx)
+	_, err := service.GetRates(ctx, "BTC-USDT")
 
 	// Assert
-	assert.True(t, result)
+	assert.Equal(t, expectedErr, err)
 	mockRepo.AssertExpectations(t)
-	service.mockKuCoin.AssertExpectations(t)
 }
 
-func TestHealthCheck_EmptyDB(t *testing.T) {
+func TestGetRates_StaleFallbackDisabled(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	service, mockExchange := newTestRateService(mockRepo)
 
-	ctx := context.Background()
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(0.0, 0.0, time.Time{}, errors.New("kucoin error"))
 
-	// Настраиваем мок репозитория с отсутствующими данными
-	mockRepo.On("GetLatestRate", ctx, "BTC-USDT").Return(nil, sql.ErrNoRows)
+	// Act
+	_, err := service.GetRates(context.Background(), "BTC-USDT")
 
-	// Настраиваем мок KuCoin клиента
-	service.mockKuCoin.On("GetOrderBook", ctx, "BTC-USDT").Return(40000.5, 39999.5, time.Now(), nil)
+	// Assert
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "GetLatestRate", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesWithBook(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		QuoteCacheMaxAge:      time.Minute,
+	})
+
+	timestamp := time.Now().UTC()
+	book := &model.OrderBook{
+		Symbol:    "BTC-USDT",
+		Asks:      []model.PriceLevel{{Price: 101, Size: 1}, {Price: 102, Size: 1}},
+		Bids:      []model.PriceLevel{{Price: 100, Size: 1}},
+		Timestamp: timestamp,
+	}
+	mockExchange.On("GetOrderBookDepth", mock.Anything, "BTC-USDT", BookDepth).Return(book, nil).Once()
+	mockRepo.On("SaveRate", mock.Anything, mock.MatchedBy(func(rate model.Rate) bool {
+		return rate.Symbol == "BTC-USDT" && rate.Ask == 101 && rate.Bid == 100 && rate.Timestamp == timestamp
+	})).Return(nil).Once()
 
 	// Act
-	result := service.HealthCheck(ctx)
+	quote, gotBook, err := service.GetRatesWithBook(context.Background(), "BTC-USDT")
+	cachedQuote, cachedBook, cachedErr := service.GetRatesWithBook(context.Background(), "BTC-USDT")
 
-	// Assert
-	assert.True(t, result)
+	// Assert: курс взят из верхних уровней того же стакана, повтор обслужен из кэша
+	assert.NoError(t, err)
+	assert.Equal(t, book, gotBook)
+	assert.Equal(t, 101.0, quote.Ask)
+	assert.Equal(t, 100.0, quote.Bid)
+	assert.Equal(t, model.QuoteSourceExchange, quote.Source)
+
+	assert.NoError(t, cachedErr)
+	assert.Equal(t, book, cachedBook)
+	assert.Equal(t, model.QuoteSourceCache, cachedQuote.Source)
+
+	mockExchange.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
-	service.mockKuCoin.AssertExpectations(t)
+	mockExchange.AssertNotCalled(t, "GetOrderBook", mock.Anything, mock.Anything)
 }
 
-func TestHealthCheck_DBError(t *testing.T) {
+func TestGetRatesWithBook_StaleFallback(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		StaleFallbackMaxAge:   time.Minute,
+	})
+
+	stored := &model.Rate{Symbol: "BTC-USDT", Ask: 101, Bid: 100, Timestamp: time.Now().Add(-10 * time.Second)}
+	mockExchange.On("GetOrderBookDepth", mock.Anything, "BTC-USDT", BookDepth).Return(nil, errors.New("kucoin error"))
+	mockRepo.On("GetLatestRate", mock.Anything, "BTC-USDT").Return(stored, nil)
+
+	// Act
+	quote, book, err := service.GetRatesWithBook(context.Background(), "BTC-USDT")
+
+	// Assert: сохраненный курс без стакана
+	assert.NoError(t, err)
+	assert.Nil(t, book)
+	assert.True(t, quote.Stale)
+	assert.Equal(t, 101.0, quote.Ask)
+	mockRepo.AssertNotCalled(t, "SaveRate", mock.Anything, mock.Anything)
+}
+
+func TestGetRatesBatch(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), mockRepo, mockExchange, nil, Config{
+		SubscribePollInterval: time.Second,
+		BatchConcurrency:      2,
+	})
 
 	ctx := context.Background()
-	dbError := errors.New("database connection error")
+	timestamp := time.Now().UTC()
+	expectedErr := errors.New("kucoin error")
 
-	// Настраиваем мок репозитория с ошибкой
-	mockRepo.On("GetLatestRate", ctx, "BTC-USDT").Return(nil, dbError)
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, timestamp, nil)
+	mockExchange.On("GetOrderBook", mock.Anything, "ETH-USDT").Return(0.0, 0.0, time.Time{}, expectedErr)
+	mockExchange.On("GetOrderBook", mock.Anything, "SOL-USDT").Return(21.0, 20.0, timestamp, nil)
+
+	// Сохраняются только успешно полученные курсы, одним вызовом
+	mockRepo.On("SaveRates", mock.Anything, mock.MatchedBy(func(rates []model.Rate) bool {
+		return len(rates) == 2 && rates[0].Symbol == "BTC-USDT" && rates[1].Symbol == "SOL-USDT"
+	})).Return(nil).Once()
 
 	// Act
-	result := service.HealthCheck(ctx)
+	results := service.GetRatesBatch(ctx, []string{"BTC-USDT", "ETH-USDT", "SOL-USDT"})
 
-	// Assert
-	assert.False(t, result)
+	// Assert: результаты в порядке запроса
+	assert.Len(t, results, 3)
+	assert.Equal(t, "BTC-USDT", results[0].Symbol)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, 101.0, results[0].Quote.Ask)
+
+	assert.Equal(t, "ETH-USDT", results[1].Symbol)
+	assert.Equal(t, expectedErr, results[1].Err)
+
+	assert.Equal(t, "SOL-USDT", results[2].Symbol)
+	assert.Equal(t, 20.0, results[2].Quote.Bid)
+
+	mockExchange.AssertExpectations(t)
 	mockRepo.AssertExpectations(t)
-	// KuCoin клиент не должен быть вызван при ошибке базы данных
-	service.mockKuCoin.AssertNotCalled(t, "GetOrderBook")
+	mockRepo.AssertNotCalled(t, "SaveRate", mock.Anything, mock.Anything)
 }
 
-func TestHealthCheck_KuCoinError(t *testing.T) {
+func TestGetRatesBatch_SaveErrorIgnored(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockRateRepository)
-	logger := zap.NewNop()
-	service := newTestRateService(logger, mockRepo)
+	service, mockExchange := newTestRateService(mockRepo)
 
+	mockExchange.On("GetOrderBook", mock.Anything, "BTC-USDT").Return(101.0, 100.0, time.Now(), nil)
+	mockRepo.On("SaveRates", mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+	// Act
+	results := service.GetRatesBatch(context.Background(), []string{"BTC-USDT"})
+
+	// Assert: ошибка сохранения не влияет на ответ клиенту
+	assert.Len(t, results, 1)
+	assert.NoError(t, results[0].Err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetOrderBook(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	service, mockExchange := newTestRateService(mockRepo)
+
+	book := &model.OrderBook{
+		Symbol:   "BTC-USDT",
+		Exchange: "kucoin",
+		Sequence: 7,
+		Asks:     []model.PriceLevel{{Price: 101, Size: 1}},
+		Bids:     []model.PriceLevel{{Price: 100, Size: 2}},
+	}
+	mockExchange.On("GetOrderBookDepth", mock.Anything, "BTC-USDT", 5).Return(book, nil)
+
+	// Act
+	result, err := service.GetOrderBook(context.Background(), "BTC-USDT", 5)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, book, result)
+	mockExchange.AssertExpectations(t)
+	// Стакан не сохраняется в БД
+	mockRepo.AssertNotCalled(t, "SaveRate", mock.Anything, mock.Anything)
+}
+
+// Заглушка потока цен реального времени
+type stubQuoteFeed struct {
+	ask, bid  float64
+	timestamp time.Time
+	ok        bool
+}
+
+func (f stubQuoteFeed) BestQuote(string) (float64, float64, time.Time, bool) {
+	return f.ask, f.bid, f.timestamp, f.ok
+}
+
+func TestFetchOrderBook_UsesQuoteFeed(t *testing.T) {
+	// Arrange
+	timestamp := time.Now().UTC()
+	mockExchange := new(MockExchange)
+	service := NewRateService(zap.NewNop(), new(MockRateRepository), mockExchange,
+		stubQuoteFeed{ask: 40001, bid: 40000, timestamp: timestamp, ok: true}, Config{SubscribePollInterval: time.Second})
+
+	// Act
+	ask, bid, resultTimestamp, err := service.fetchOrderBook(context.Background(), "BTC-USDT")
+
+	// Assert
+	assert.NoError(t, err)
+	mockExchange.AssertNotCalled(t, "GetOrderBook")
+	assert.Equal(t, 40001.0, ask)
+	assert.Equal(t, 40000.0, bid)
+	assert.Equal(t, timestamp, resultTimestamp)
+}
+
+func TestGetRateHistory(t *testing.T) {
 	ctx := context.Background()
-	rate := &model.Rate{Symbol: "BTC-USDT", Ask: 40000.5, Bid: 39999.5}
-	kuCoinError := errors.New("kucoin api error")
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
 
-	// Настраиваем мок репозитория
-	mockRepo.On("GetLatestRate", ctx, "BTC-USDT").Return(rate, nil)
+	rates := []model.Rate{
+		{ID: 1, Symbol: "BTC-USDT", Ask: 1, Bid: 1, Timestamp: from},
+		{ID: 2, Symbol: "BTC-USDT", Ask: 2, Bid: 2, Timestamp: from.Add(time.Minute)},
+		{ID: 3, Symbol: "BTC-USDT", Ask: 3, Bid: 3, Timestamp: from.Add(2 * time.Minute)},
+	}
+
+	t.Run("more rows than page size returns cursor", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRateRepository)
+		service, _ := newTestRateService(mockRepo)
+
+		mockRepo.On("GetRateHistory", mock.Anything, repository.HistoryQuery{
+			Symbol: "BTC-USDT", From: from, To: to, Limit: 3,
+		}).Return(rates, nil)
+
+		// Act
+		page, next, err := service.GetRateHistory(ctx, "BTC-USDT", from, to, 2, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rates[:2], page)
+		assert.Equal(t, &model.RateCursor{Timestamp: rates[1].Timestamp, ID: 2}, next)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("last page has no cursor", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRateRepository)
+		service, _ := newTestRateService(mockRepo)
+		after := &model.RateCursor{Timestamp: rates[1].Timestamp, ID: 2}
+
+		mockRepo.On("GetRateHistory", mock.Anything, repository.HistoryQuery{
+			Symbol: "BTC-USDT", From: from, To: to, After: after, Limit: 3,
+		}).Return(rates[2:], nil)
+
+		// Act
+		page, next, err := service.GetRateHistory(ctx, "BTC-USDT", from, to, 2, after)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, rates[2:], page)
+		assert.Nil(t, next)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRateRepository)
+		service, _ := newTestRateService(mockRepo)
+		repoErr := errors.New("database error")
+
+		mockRepo.On("GetRateHistory", mock.Anything, mock.Anything).Return(nil, repoErr)
+
+		// Act
+		page, next, err := service.GetRateHistory(ctx, "BTC-USDT", from, to, 2, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, repoErr)
+		assert.Nil(t, page)
+		assert.Nil(t, next)
+	})
+}
 
-	// Настраиваем мок KuCoin клиента с ошибкой
-	service.mockKuCoin.On("GetOrderBook", ctx, "BTC-USDT").Return(0.0, 0.0, time.Time{}, kuCoinError)
+func TestGetCandles(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockRateRepository)
+	service, _ := newTestRateService(mockRepo)
+
+	ctx := context.Background()
+	from := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	candles := []model.Candle{{OpenTime: from, Mid: model.OHLC{Open: 1, High: 2, Low: 0.5, Close: 1.5}, Count: 3}}
+
+	mockRepo.On("GetCandles", mock.Anything, repository.CandleQuery{
+		Symbol: "BTC-USDT", Interval: time.Minute, From: from, To: to,
+	}).Return(candles, nil)
 
 	// Act
-	result := service.HealthCheck(ctx)
+	result, err := service.GetCandles(ctx, "BTC-USDT", time.Minute, from, to)
 
 	// Assert
-	assert.False(t, result)
+	assert.NoError(t, err)
+	assert.Equal(t, candles, result)
 	mockRepo.AssertExpectations(t)
-	service.mockKuCoin.AssertExpectations(t)
 }
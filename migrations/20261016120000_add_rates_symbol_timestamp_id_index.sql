@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_rates_symbol_timestamp_id ON rates(symbol, timestamp, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_rates_symbol_timestamp_id;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE symbols (
+    symbol VARCHAR(40) PRIMARY KEY,
+    base_currency VARCHAR(20) NOT NULL,
+    quote_currency VARCHAR(20) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS symbols;
+-- +goose StatementEnd
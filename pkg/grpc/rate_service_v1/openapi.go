@@ -0,0 +1,9 @@
+package rate_service_v1
+
+import _ "embed"
+
+// OpenAPI - описание REST-шлюза RateService в формате OpenAPI 2.0,
+// сгенерированное protoc-gen-openapiv2 из rate.proto
+//
+//go:embed rate.swagger.json
+var OpenAPI []byte
@@ -8,6 +8,8 @@ package rate_service_v1
 
 import (
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -22,28 +24,1293 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Источник, из которого получен курс
+type RateSource int32
+
+const (
+	RateSource_RATE_SOURCE_UNSPECIFIED RateSource = 0
+	// REST API биржи
+	RateSource_RATE_SOURCE_EXCHANGE RateSource = 1
+	// Поток цен реального времени (WebSocket)
+	RateSource_RATE_SOURCE_STREAM RateSource = 2
+	// Кэш сервиса
+	RateSource_RATE_SOURCE_CACHE RateSource = 3
+	// Последний сохраненный в БД курс (биржа недоступна)
+	RateSource_RATE_SOURCE_STORAGE RateSource = 4
+)
+
+// Enum value maps for RateSource.
+var (
+	RateSource_name = map[int32]string{
+		0: "RATE_SOURCE_UNSPECIFIED",
+		1: "RATE_SOURCE_EXCHANGE",
+		2: "RATE_SOURCE_STREAM",
+		3: "RATE_SOURCE_CACHE",
+		4: "RATE_SOURCE_STORAGE",
+	}
+	RateSource_value = map[string]int32{
+		"RATE_SOURCE_UNSPECIFIED": 0,
+		"RATE_SOURCE_EXCHANGE":    1,
+		"RATE_SOURCE_STREAM":      2,
+		"RATE_SOURCE_CACHE":       3,
+		"RATE_SOURCE_STORAGE":     4,
+	}
+)
+
+func (x RateSource) Enum() *RateSource {
+	p := new(RateSource)
+	*p = x
+	return p
+}
+
+func (x RateSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RateSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_rate_proto_enumTypes[0].Descriptor()
+}
+
+func (RateSource) Type() protoreflect.EnumType {
+	return &file_rate_proto_enumTypes[0]
+}
+
+func (x RateSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RateSource.Descriptor instead.
+func (RateSource) EnumDescriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{0}
+}
+
+type CandleInterval int32
+
+const (
+	CandleInterval_CANDLE_INTERVAL_UNSPECIFIED CandleInterval = 0
+	CandleInterval_CANDLE_INTERVAL_1M          CandleInterval = 1
+	CandleInterval_CANDLE_INTERVAL_5M          CandleInterval = 2
+	CandleInterval_CANDLE_INTERVAL_1H          CandleInterval = 3
+	CandleInterval_CANDLE_INTERVAL_1D          CandleInterval = 4
+)
+
+// Enum value maps for CandleInterval.
+var (
+	CandleInterval_name = map[int32]string{
+		0: "CANDLE_INTERVAL_UNSPECIFIED",
+		1: "CANDLE_INTERVAL_1M",
+		2: "CANDLE_INTERVAL_5M",
+		3: "CANDLE_INTERVAL_1H",
+		4: "CANDLE_INTERVAL_1D",
+	}
+	CandleInterval_value = map[string]int32{
+		"CANDLE_INTERVAL_UNSPECIFIED": 0,
+		"CANDLE_INTERVAL_1M":          1,
+		"CANDLE_INTERVAL_5M":          2,
+		"CANDLE_INTERVAL_1H":          3,
+		"CANDLE_INTERVAL_1D":          4,
+	}
+)
+
+func (x CandleInterval) Enum() *CandleInterval {
+	p := new(CandleInterval)
+	*p = x
+	return p
+}
+
+func (x CandleInterval) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CandleInterval) Descriptor() protoreflect.EnumDescriptor {
+	return file_rate_proto_enumTypes[1].Descriptor()
+}
+
+func (CandleInterval) Type() protoreflect.EnumType {
+	return &file_rate_proto_enumTypes[1]
+}
+
+func (x CandleInterval) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CandleInterval.Descriptor instead.
+func (CandleInterval) EnumDescriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{1}
+}
+
 type GetRatesRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Объем в котируемой валюте для расчета VWAP по стакану; 0 - не рассчитывать
+	Notional      float64 `protobuf:"fixed64,2,opt,name=notional,proto3" json:"notional,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesRequest) Reset() {
+	*x = GetRatesRequest{}
+	mi := &file_rate_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesRequest) ProtoMessage() {}
+
+func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetRatesRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetRatesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetRatesRequest) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+type GetRatesResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Ask       float64                `protobuf:"fixed64,1,opt,name=ask,proto3" json:"ask,omitempty"`
+	Bid       float64                `protobuf:"fixed64,2,opt,name=bid,proto3" json:"bid,omitempty"`
+	Timestamp *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    RateSource             `protobuf:"varint,4,opt,name=source,proto3,enum=rate_service.v1.RateSource" json:"source,omitempty"`
+	// true, если биржа недоступна и курс взят из последних сохраненных
+	Stale bool `protobuf:"varint,5,opt,name=stale,proto3" json:"stale,omitempty"`
+	// (ask + bid) / 2. Производные величины (mid, spread, spread_bps, VWAP)
+	// округлены до 10 значащих цифр
+	Mid float64 `protobuf:"fixed64,6,opt,name=mid,proto3" json:"mid,omitempty"`
+	// ask - bid
+	Spread float64 `protobuf:"fixed64,7,opt,name=spread,proto3" json:"spread,omitempty"`
+	// Спред в базисных пунктах от mid
+	SpreadBps float64 `protobuf:"fixed64,8,opt,name=spread_bps,json=spreadBps,proto3" json:"spread_bps,omitempty"`
+	// VWAP покупки на notional по asks; только если notional задан.
+	// Считается по тому же снимку стакана, что и ask/bid; для stale-курса не заполняется
+	BuyVwap *VWAP `protobuf:"bytes,9,opt,name=buy_vwap,json=buyVwap,proto3" json:"buy_vwap,omitempty"`
+	// VWAP продажи на notional по bids; только если notional задан и курс не stale
+	SellVwap *VWAP `protobuf:"bytes,10,opt,name=sell_vwap,json=sellVwap,proto3" json:"sell_vwap,omitempty"`
+	// Пары, через которые рассчитан кросс-курс; пусто, если пара торгуется напрямую
+	Path          []*PathLeg `protobuf:"bytes,11,rep,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesResponse) Reset() {
+	*x = GetRatesResponse{}
+	mi := &file_rate_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesResponse) ProtoMessage() {}
+
+func (x *GetRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetRatesResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetRatesResponse) GetAsk() float64 {
+	if x != nil {
+		return x.Ask
+	}
+	return 0
+}
+
+func (x *GetRatesResponse) GetBid() float64 {
+	if x != nil {
+		return x.Bid
+	}
+	return 0
+}
+
+func (x *GetRatesResponse) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *GetRatesResponse) GetSource() RateSource {
+	if x != nil {
+		return x.Source
+	}
+	return RateSource_RATE_SOURCE_UNSPECIFIED
+}
+
+func (x *GetRatesResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetRatesResponse) GetMid() float64 {
+	if x != nil {
+		return x.Mid
+	}
+	return 0
+}
+
+func (x *GetRatesResponse) GetSpread() float64 {
+	if x != nil {
+		return x.Spread
+	}
+	return 0
+}
+
+func (x *GetRatesResponse) GetSpreadBps() float64 {
+	if x != nil {
+		return x.SpreadBps
+	}
+	return 0
+}
+
+func (x *GetRatesResponse) GetBuyVwap() *VWAP {
+	if x != nil {
+		return x.BuyVwap
+	}
+	return nil
+}
+
+func (x *GetRatesResponse) GetSellVwap() *VWAP {
+	if x != nil {
+		return x.SellVwap
+	}
+	return nil
+}
+
+func (x *GetRatesResponse) GetPath() []*PathLeg {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+// Шаг пути кросс-курса
+type PathLeg struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// true, если курс пары перевернут: ask = 1/bid, bid = 1/ask
+	Inverted      bool `protobuf:"varint,2,opt,name=inverted,proto3" json:"inverted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathLeg) Reset() {
+	*x = PathLeg{}
+	mi := &file_rate_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathLeg) ProtoMessage() {}
+
+func (x *PathLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathLeg.ProtoReflect.Descriptor instead.
+func (*PathLeg) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PathLeg) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PathLeg) GetInverted() bool {
+	if x != nil {
+		return x.Inverted
+	}
+	return false
+}
+
+// Средневзвешенная цена исполнения объема по уровням стакана
+type VWAP struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Price float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	// Исполненный объем в базовой валюте
+	BaseQuantity float64 `protobuf:"fixed64,2,opt,name=base_quantity,json=baseQuantity,proto3" json:"base_quantity,omitempty"`
+	// Исполненный объем в котируемой валюте
+	Notional float64 `protobuf:"fixed64,3,opt,name=notional,proto3" json:"notional,omitempty"`
+	// false, если в стакане не хватило уровней на весь объем
+	Complete      bool `protobuf:"varint,4,opt,name=complete,proto3" json:"complete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VWAP) Reset() {
+	*x = VWAP{}
+	mi := &file_rate_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VWAP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VWAP) ProtoMessage() {}
+
+func (x *VWAP) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VWAP.ProtoReflect.Descriptor instead.
+func (*VWAP) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VWAP) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *VWAP) GetBaseQuantity() float64 {
+	if x != nil {
+		return x.BaseQuantity
+	}
+	return 0
+}
+
+func (x *VWAP) GetNotional() float64 {
+	if x != nil {
+		return x.Notional
+	}
+	return 0
+}
+
+func (x *VWAP) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+type GetRatesBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesBatchRequest) Reset() {
+	*x = GetRatesBatchRequest{}
+	mi := &file_rate_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesBatchRequest) ProtoMessage() {}
+
+func (x *GetRatesBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetRatesBatchRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetRatesBatchRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+// Результат по одному символу пакетного запроса
+type RateResult struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*RateResult_Rate
+	//	*RateResult_Error
+	Result        isRateResult_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateResult) Reset() {
+	*x = RateResult{}
+	mi := &file_rate_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateResult) ProtoMessage() {}
+
+func (x *RateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateResult.ProtoReflect.Descriptor instead.
+func (*RateResult) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RateResult) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *RateResult) GetResult() isRateResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *RateResult) GetRate() *GetRatesResponse {
+	if x != nil {
+		if x, ok := x.Result.(*RateResult_Rate); ok {
+			return x.Rate
+		}
+	}
+	return nil
+}
+
+func (x *RateResult) GetError() *status.Status {
+	if x != nil {
+		if x, ok := x.Result.(*RateResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isRateResult_Result interface {
+	isRateResult_Result()
+}
+
+type RateResult_Rate struct {
+	Rate *GetRatesResponse `protobuf:"bytes,2,opt,name=rate,proto3,oneof"`
+}
+
+type RateResult_Error struct {
+	// Ошибка получения курса по символу
+	Error *status.Status `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*RateResult_Rate) isRateResult_Result() {}
+
+func (*RateResult_Error) isRateResult_Result() {}
+
+type GetRatesBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Результаты в порядке символов запроса (без повторов)
+	Results       []*RateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesBatchResponse) Reset() {
+	*x = GetRatesBatchResponse{}
+	mi := &file_rate_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesBatchResponse) ProtoMessage() {}
+
+func (x *GetRatesBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesBatchResponse.ProtoReflect.Descriptor instead.
+func (*GetRatesBatchResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetRatesBatchResponse) GetResults() []*RateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetOrderBookRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Число уровней с каждой стороны, по умолчанию 20
+	Depth         int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookRequest) Reset() {
+	*x = GetOrderBookRequest{}
+	mi := &file_rate_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookRequest) ProtoMessage() {}
+
+func (x *GetOrderBookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderBookRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetOrderBookRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetOrderBookRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type PriceLevel struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Price float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	Size  float64                `protobuf:"fixed64,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Суммарный объем от лучшей цены до этого уровня включительно
+	CumulativeSize float64 `protobuf:"fixed64,3,opt,name=cumulative_size,json=cumulativeSize,proto3" json:"cumulative_size,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PriceLevel) Reset() {
+	*x = PriceLevel{}
+	mi := &file_rate_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceLevel) ProtoMessage() {}
+
+func (x *PriceLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceLevel.ProtoReflect.Descriptor instead.
+func (*PriceLevel) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PriceLevel) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceLevel) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PriceLevel) GetCumulativeSize() float64 {
+	if x != nil {
+		return x.CumulativeSize
+	}
+	return 0
+}
+
+type GetOrderBookResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Биржа, с которой получен стакан
+	Exchange string `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	// Номер обновления стакана на бирже
+	Sequence  int64                `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Timestamp *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Лучшая цена первая: asks по возрастанию, bids по убыванию
+	Asks          []*PriceLevel `protobuf:"bytes,5,rep,name=asks,proto3" json:"asks,omitempty"`
+	Bids          []*PriceLevel `protobuf:"bytes,6,rep,name=bids,proto3" json:"bids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderBookResponse) Reset() {
+	*x = GetOrderBookResponse{}
+	mi := &file_rate_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderBookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderBookResponse) ProtoMessage() {}
+
+func (x *GetOrderBookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderBookResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderBookResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderBookResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetOrderBookResponse) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *GetOrderBookResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *GetOrderBookResponse) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *GetOrderBookResponse) GetAsks() []*PriceLevel {
+	if x != nil {
+		return x.Asks
+	}
+	return nil
+}
+
+func (x *GetOrderBookResponse) GetBids() []*PriceLevel {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+type SubscribeRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRatesRequest) Reset() {
+	*x = SubscribeRatesRequest{}
+	mi := &file_rate_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRatesRequest) ProtoMessage() {}
+
+func (x *SubscribeRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRatesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRatesRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SubscribeRatesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type SubscribeRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Ask           float64                `protobuf:"fixed64,2,opt,name=ask,proto3" json:"ask,omitempty"`
+	Bid           float64                `protobuf:"fixed64,3,opt,name=bid,proto3" json:"bid,omitempty"`
+	Timestamp     *timestamp.Timestamp   `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRatesResponse) Reset() {
+	*x = SubscribeRatesResponse{}
+	mi := &file_rate_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRatesResponse) ProtoMessage() {}
+
+func (x *SubscribeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRatesResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscribeRatesResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SubscribeRatesResponse) GetAsk() float64 {
+	if x != nil {
+		return x.Ask
+	}
+	return 0
+}
+
+func (x *SubscribeRatesResponse) GetBid() float64 {
+	if x != nil {
+		return x.Bid
+	}
+	return 0
+}
+
+func (x *SubscribeRatesResponse) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type GetRateHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// Начало интервала (включительно)
+	From *timestamp.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// Конец интервала (не включительно), по умолчанию - текущее время
+	To       *timestamp.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	PageSize int32                `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Токен следующей страницы из предыдущего ответа
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateHistoryRequest) Reset() {
+	*x = GetRateHistoryRequest{}
+	mi := &file_rate_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateHistoryRequest) ProtoMessage() {}
+
+func (x *GetRateHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetRateHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRateHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetRateHistoryRequest) GetFrom() *timestamp.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetRateHistoryRequest) GetTo() *timestamp.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetRateHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetRateHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type RateRecord struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Ask           float64                `protobuf:"fixed64,1,opt,name=ask,proto3" json:"ask,omitempty"`
+	Bid           float64                `protobuf:"fixed64,2,opt,name=bid,proto3" json:"bid,omitempty"`
+	Timestamp     *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateRecord) Reset() {
+	*x = RateRecord{}
+	mi := &file_rate_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateRecord) ProtoMessage() {}
+
+func (x *RateRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateRecord.ProtoReflect.Descriptor instead.
+func (*RateRecord) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RateRecord) GetAsk() float64 {
+	if x != nil {
+		return x.Ask
+	}
+	return 0
+}
+
+func (x *RateRecord) GetBid() float64 {
+	if x != nil {
+		return x.Bid
+	}
+	return 0
+}
+
+func (x *RateRecord) GetTimestamp() *timestamp.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type GetRateHistoryResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Rates  []*RateRecord          `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty"`
+	// Пустой токен означает, что страниц больше нет
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRateHistoryResponse) Reset() {
+	*x = GetRateHistoryResponse{}
+	mi := &file_rate_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRateHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateHistoryResponse) ProtoMessage() {}
+
+func (x *GetRateHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetRateHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetRateHistoryResponse) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetRateHistoryResponse) GetRates() []*RateRecord {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *GetRateHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetCandlesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Symbol   string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval CandleInterval         `protobuf:"varint,2,opt,name=interval,proto3,enum=rate_service.v1.CandleInterval" json:"interval,omitempty"`
+	// Начало интервала (включительно)
+	From *timestamp.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// Конец интервала (не включительно), по умолчанию - текущее время
+	To            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesRequest) Reset() {
+	*x = GetCandlesRequest{}
+	mi := &file_rate_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesRequest) ProtoMessage() {}
+
+func (x *GetCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetCandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+}
+
+func (x *GetCandlesRequest) GetFrom() *timestamp.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetCandlesRequest) GetTo() *timestamp.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type OHLC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Open          float64                `protobuf:"fixed64,1,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,2,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,3,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,4,opt,name=close,proto3" json:"close,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OHLC) Reset() {
+	*x = OHLC{}
+	mi := &file_rate_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OHLC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OHLC) ProtoMessage() {}
+
+func (x *OHLC) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OHLC.ProtoReflect.Descriptor instead.
+func (*OHLC) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *OHLC) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *OHLC) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *OHLC) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *OHLC) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+type Candle struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Начало свечи, выровненное по границе интервала в UTC
+	OpenTime *timestamp.Timestamp `protobuf:"bytes,1,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`
+	Mid      *OHLC                `protobuf:"bytes,2,opt,name=mid,proto3" json:"mid,omitempty"`
+	Ask      *OHLC                `protobuf:"bytes,3,opt,name=ask,proto3" json:"ask,omitempty"`
+	Bid      *OHLC                `protobuf:"bytes,4,opt,name=bid,proto3" json:"bid,omitempty"`
+	// Число сохраненных котировок в свече
+	Count         int64 `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRatesRequest) Reset() {
-	*x = GetRatesRequest{}
-	mi := &file_rate_proto_msgTypes[0]
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_rate_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRatesRequest) String() string {
+func (x *Candle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRatesRequest) ProtoMessage() {}
+func (*Candle) ProtoMessage() {}
 
-func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rate_proto_msgTypes[0]
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -54,42 +1321,187 @@ func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRatesRequest.ProtoReflect.Descriptor instead.
-func (*GetRatesRequest) Descriptor() ([]byte, []int) {
-	return file_rate_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *GetRatesRequest) GetSymbol() string {
+func (x *Candle) GetOpenTime() *timestamp.Timestamp {
+	if x != nil {
+		return x.OpenTime
+	}
+	return nil
+}
+
+func (x *Candle) GetMid() *OHLC {
+	if x != nil {
+		return x.Mid
+	}
+	return nil
+}
+
+func (x *Candle) GetAsk() *OHLC {
+	if x != nil {
+		return x.Ask
+	}
+	return nil
+}
+
+func (x *Candle) GetBid() *OHLC {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *Candle) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetCandlesResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Symbol   string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval CandleInterval         `protobuf:"varint,2,opt,name=interval,proto3,enum=rate_service.v1.CandleInterval" json:"interval,omitempty"`
+	// Свечи упорядочены по open_time. Интервалы без котировок пропускаются:
+	// свеча есть только там, где была хотя бы одна котировка, пустые свечи не дополняются.
+	Candles       []*Candle `protobuf:"bytes,3,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesResponse) Reset() {
+	*x = GetCandlesResponse{}
+	mi := &file_rate_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesResponse) ProtoMessage() {}
+
+func (x *GetCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetCandlesResponse) GetSymbol() string {
 	if x != nil {
 		return x.Symbol
 	}
 	return ""
 }
 
-type GetRatesResponse struct {
+func (x *GetCandlesResponse) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+}
+
+func (x *GetCandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type ListSymbolsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Фильтр по базовой валюте (например, BTC); пусто - без фильтра
+	BaseCurrency string `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	// Фильтр по котируемой валюте (например, USDT); пусто - без фильтра
+	QuoteCurrency string `protobuf:"bytes,2,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsRequest) Reset() {
+	*x = ListSymbolsRequest{}
+	mi := &file_rate_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsRequest) ProtoMessage() {}
+
+func (x *ListSymbolsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsRequest.ProtoReflect.Descriptor instead.
+func (*ListSymbolsRequest) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListSymbolsRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *ListSymbolsRequest) GetQuoteCurrency() string {
+	if x != nil {
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
+// Торгуемая пара из справочника биржи
+type SymbolInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ask           float64                `protobuf:"fixed64,1,opt,name=ask,proto3" json:"ask,omitempty"`
-	Bid           float64                `protobuf:"fixed64,2,opt,name=bid,proto3" json:"bid,omitempty"`
-	Timestamp     *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	BaseCurrency  string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	QuoteCurrency string                 `protobuf:"bytes,3,opt,name=quote_currency,json=quoteCurrency,proto3" json:"quote_currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRatesResponse) Reset() {
-	*x = GetRatesResponse{}
-	mi := &file_rate_proto_msgTypes[1]
+func (x *SymbolInfo) Reset() {
+	*x = SymbolInfo{}
+	mi := &file_rate_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRatesResponse) String() string {
+func (x *SymbolInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRatesResponse) ProtoMessage() {}
+func (*SymbolInfo) ProtoMessage() {}
 
-func (x *GetRatesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rate_proto_msgTypes[1]
+func (x *SymbolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,28 +1512,81 @@ func (x *GetRatesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRatesResponse.ProtoReflect.Descriptor instead.
-func (*GetRatesResponse) Descriptor() ([]byte, []int) {
-	return file_rate_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use SymbolInfo.ProtoReflect.Descriptor instead.
+func (*SymbolInfo) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetRatesResponse) GetAsk() float64 {
+func (x *SymbolInfo) GetSymbol() string {
 	if x != nil {
-		return x.Ask
+		return x.Symbol
 	}
-	return 0
+	return ""
 }
 
-func (x *GetRatesResponse) GetBid() float64 {
+func (x *SymbolInfo) GetBaseCurrency() string {
 	if x != nil {
-		return x.Bid
+		return x.BaseCurrency
 	}
-	return 0
+	return ""
 }
 
-func (x *GetRatesResponse) GetTimestamp() *timestamp.Timestamp {
+func (x *SymbolInfo) GetQuoteCurrency() string {
 	if x != nil {
-		return x.Timestamp
+		return x.QuoteCurrency
+	}
+	return ""
+}
+
+type ListSymbolsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Symbols []*SymbolInfo          `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// Время последнего обновления справочника
+	UpdatedAt     *timestamp.Timestamp `protobuf:"bytes,2,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSymbolsResponse) Reset() {
+	*x = ListSymbolsResponse{}
+	mi := &file_rate_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSymbolsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSymbolsResponse) ProtoMessage() {}
+
+func (x *ListSymbolsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSymbolsResponse.ProtoReflect.Descriptor instead.
+func (*ListSymbolsResponse) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListSymbolsResponse) GetSymbols() []*SymbolInfo {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *ListSymbolsResponse) GetUpdatedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
 	}
 	return nil
 }
@@ -134,7 +1599,7 @@ type HealthCheckRequest struct {
 
 func (x *HealthCheckRequest) Reset() {
 	*x = HealthCheckRequest{}
-	mi := &file_rate_proto_msgTypes[2]
+	mi := &file_rate_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +1611,7 @@ func (x *HealthCheckRequest) String() string {
 func (*HealthCheckRequest) ProtoMessage() {}
 
 func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_rate_proto_msgTypes[2]
+	mi := &file_rate_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,19 +1624,99 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
 func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
-	return file_rate_proto_rawDescGZIP(), []int{2}
+	return file_rate_proto_rawDescGZIP(), []int{22}
+}
+
+// Состояние зависимости сервиса по результатам последней проверки
+type ComponentHealth struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Healthy bool                   `protobuf:"varint,2,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// Неисправность критичного компонента делает сервис неработоспособным
+	Critical bool `protobuf:"varint,3,opt,name=critical,proto3" json:"critical,omitempty"`
+	// Ошибка последней проверки; пусто, если компонент исправен
+	Error         string               `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	CheckedAt     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentHealth) Reset() {
+	*x = ComponentHealth{}
+	mi := &file_rate_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentHealth) ProtoMessage() {}
+
+func (x *ComponentHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_rate_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentHealth.ProtoReflect.Descriptor instead.
+func (*ComponentHealth) Descriptor() ([]byte, []int) {
+	return file_rate_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ComponentHealth) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ComponentHealth) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *ComponentHealth) GetCritical() bool {
+	if x != nil {
+		return x.Critical
+	}
+	return false
+}
+
+func (x *ComponentHealth) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ComponentHealth) GetCheckedAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
 }
 
 type HealthCheckResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Healthy       bool                   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Components    []*ComponentHealth     `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HealthCheckResponse) Reset() {
 	*x = HealthCheckResponse{}
-	mi := &file_rate_proto_msgTypes[3]
+	mi := &file_rate_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -183,7 +1728,7 @@ func (x *HealthCheckResponse) String() string {
 func (*HealthCheckResponse) ProtoMessage() {}
 
 func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_rate_proto_msgTypes[3]
+	mi := &file_rate_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -196,7 +1741,7 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
 func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_rate_proto_rawDescGZIP(), []int{3}
+	return file_rate_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *HealthCheckResponse) GetHealthy() bool {
@@ -206,24 +1751,161 @@ func (x *HealthCheckResponse) GetHealthy() bool {
 	return false
 }
 
+func (x *HealthCheckResponse) GetComponents() []*ComponentHealth {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
 var File_rate_proto protoreflect.FileDescriptor
 
 const file_rate_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"rate.proto\x12\x0frate_service.v1\x1a\x1fgoogle/protobuf/timestamp.proto\")\n" +
+	"rate.proto\x12\x0frate_service.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/rpc/status.proto\"E\n" +
 	"\x0fGetRatesRequest\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"p\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bnotional\x18\x02 \x01(\x01R\bnotional\"\x98\x03\n" +
 	"\x10GetRatesResponse\x12\x10\n" +
 	"\x03ask\x18\x01 \x01(\x01R\x03ask\x12\x10\n" +
 	"\x03bid\x18\x02 \x01(\x01R\x03bid\x128\n" +
-	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x14\n" +
-	"\x12HealthCheckRequest\"/\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x123\n" +
+	"\x06source\x18\x04 \x01(\x0e2\x1b.rate_service.v1.RateSourceR\x06source\x12\x14\n" +
+	"\x05stale\x18\x05 \x01(\bR\x05stale\x12\x10\n" +
+	"\x03mid\x18\x06 \x01(\x01R\x03mid\x12\x16\n" +
+	"\x06spread\x18\a \x01(\x01R\x06spread\x12\x1d\n" +
+	"\n" +
+	"spread_bps\x18\b \x01(\x01R\tspreadBps\x120\n" +
+	"\bbuy_vwap\x18\t \x01(\v2\x15.rate_service.v1.VWAPR\abuyVwap\x122\n" +
+	"\tsell_vwap\x18\n" +
+	" \x01(\v2\x15.rate_service.v1.VWAPR\bsellVwap\x12,\n" +
+	"\x04path\x18\v \x03(\v2\x18.rate_service.v1.PathLegR\x04path\"=\n" +
+	"\aPathLeg\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binverted\x18\x02 \x01(\bR\binverted\"y\n" +
+	"\x04VWAP\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12#\n" +
+	"\rbase_quantity\x18\x02 \x01(\x01R\fbaseQuantity\x12\x1a\n" +
+	"\bnotional\x18\x03 \x01(\x01R\bnotional\x12\x1a\n" +
+	"\bcomplete\x18\x04 \x01(\bR\bcomplete\"0\n" +
+	"\x14GetRatesBatchRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"\x93\x01\n" +
+	"\n" +
+	"RateResult\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x127\n" +
+	"\x04rate\x18\x02 \x01(\v2!.rate_service.v1.GetRatesResponseH\x00R\x04rate\x12*\n" +
+	"\x05error\x18\x03 \x01(\v2\x12.google.rpc.StatusH\x00R\x05errorB\b\n" +
+	"\x06result\"N\n" +
+	"\x15GetRatesBatchResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.rate_service.v1.RateResultR\aresults\"C\n" +
+	"\x13GetOrderBookRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"_\n" +
+	"\n" +
+	"PriceLevel\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x01R\x04size\x12'\n" +
+	"\x0fcumulative_size\x18\x03 \x01(\x01R\x0ecumulativeSize\"\x82\x02\n" +
+	"\x14GetOrderBookResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x1a\n" +
+	"\bsequence\x18\x03 \x01(\x03R\bsequence\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12/\n" +
+	"\x04asks\x18\x05 \x03(\v2\x1b.rate_service.v1.PriceLevelR\x04asks\x12/\n" +
+	"\x04bids\x18\x06 \x03(\v2\x1b.rate_service.v1.PriceLevelR\x04bids\"1\n" +
+	"\x15SubscribeRatesRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"\x8e\x01\n" +
+	"\x16SubscribeRatesResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x10\n" +
+	"\x03ask\x18\x02 \x01(\x01R\x03ask\x12\x10\n" +
+	"\x03bid\x18\x03 \x01(\x01R\x03bid\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xc7\x01\n" +
+	"\x15GetRateHistoryRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"j\n" +
+	"\n" +
+	"RateRecord\x12\x10\n" +
+	"\x03ask\x18\x01 \x01(\x01R\x03ask\x12\x10\n" +
+	"\x03bid\x18\x02 \x01(\x01R\x03bid\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x8b\x01\n" +
+	"\x16GetRateHistoryResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x121\n" +
+	"\x05rates\x18\x02 \x03(\v2\x1b.rate_service.v1.RateRecordR\x05rates\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xc4\x01\n" +
+	"\x11GetCandlesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12;\n" +
+	"\binterval\x18\x02 \x01(\x0e2\x1f.rate_service.v1.CandleIntervalR\binterval\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"V\n" +
+	"\x04OHLC\x12\x12\n" +
+	"\x04open\x18\x01 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x02 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x03 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x04 \x01(\x01R\x05close\"\xd2\x01\n" +
+	"\x06Candle\x127\n" +
+	"\topen_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\bopenTime\x12'\n" +
+	"\x03mid\x18\x02 \x01(\v2\x15.rate_service.v1.OHLCR\x03mid\x12'\n" +
+	"\x03ask\x18\x03 \x01(\v2\x15.rate_service.v1.OHLCR\x03ask\x12'\n" +
+	"\x03bid\x18\x04 \x01(\v2\x15.rate_service.v1.OHLCR\x03bid\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x03R\x05count\"\x9c\x01\n" +
+	"\x12GetCandlesResponse\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12;\n" +
+	"\binterval\x18\x02 \x01(\x0e2\x1f.rate_service.v1.CandleIntervalR\binterval\x121\n" +
+	"\acandles\x18\x03 \x03(\v2\x17.rate_service.v1.CandleR\acandles\"`\n" +
+	"\x12ListSymbolsRequest\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12%\n" +
+	"\x0equote_currency\x18\x02 \x01(\tR\rquoteCurrency\"p\n" +
+	"\n" +
+	"SymbolInfo\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12%\n" +
+	"\x0equote_currency\x18\x03 \x01(\tR\rquoteCurrency\"\x87\x01\n" +
+	"\x13ListSymbolsResponse\x125\n" +
+	"\asymbols\x18\x01 \x03(\v2\x1b.rate_service.v1.SymbolInfoR\asymbols\x129\n" +
+	"\n" +
+	"updated_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x14\n" +
+	"\x12HealthCheckRequest\"\xac\x01\n" +
+	"\x0fComponentHealth\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\ahealthy\x18\x02 \x01(\bR\ahealthy\x12\x1a\n" +
+	"\bcritical\x18\x03 \x01(\bR\bcritical\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"checked_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\"q\n" +
 	"\x13HealthCheckResponse\x12\x18\n" +
-	"\ahealthy\x18\x01 \x01(\bR\ahealthy2\xb8\x01\n" +
-	"\vRateService\x12O\n" +
-	"\bGetRates\x12 .rate_service.v1.GetRatesRequest\x1a!.rate_service.v1.GetRatesResponse\x12X\n" +
-	"\vHealthCheck\x12#.rate_service.v1.HealthCheckRequest\x1a$.rate_service.v1.HealthCheckResponseBUZSstudentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1b\x06proto3"
+	"\ahealthy\x18\x01 \x01(\bR\ahealthy\x12@\n" +
+	"\n" +
+	"components\x18\x02 \x03(\v2 .rate_service.v1.ComponentHealthR\n" +
+	"components*\x8b\x01\n" +
+	"\n" +
+	"RateSource\x12\x1b\n" +
+	"\x17RATE_SOURCE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14RATE_SOURCE_EXCHANGE\x10\x01\x12\x16\n" +
+	"\x12RATE_SOURCE_STREAM\x10\x02\x12\x15\n" +
+	"\x11RATE_SOURCE_CACHE\x10\x03\x12\x17\n" +
+	"\x13RATE_SOURCE_STORAGE\x10\x04*\x91\x01\n" +
+	"\x0eCandleInterval\x12\x1f\n" +
+	"\x1bCANDLE_INTERVAL_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1M\x10\x01\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_5M\x10\x02\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1H\x10\x03\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1D\x10\x042\xce\a\n" +
+	"\vRateService\x12k\n" +
+	"\bGetRates\x12 .rate_service.v1.GetRatesRequest\x1a!.rate_service.v1.GetRatesResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/rates/{symbol}\x12q\n" +
+	"\rGetRatesBatch\x12%.rate_service.v1.GetRatesBatchRequest\x1a&.rate_service.v1.GetRatesBatchResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/rates\x12|\n" +
+	"\fGetOrderBook\x12$.rate_service.v1.GetOrderBookRequest\x1a%.rate_service.v1.GetOrderBookResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/orderbooks/{symbol}\x12\x80\x01\n" +
+	"\x0eSubscribeRates\x12&.rate_service.v1.SubscribeRatesRequest\x1a'.rate_service.v1.SubscribeRatesResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/rates:subscribe0\x01\x12\x85\x01\n" +
+	"\x0eGetRateHistory\x12&.rate_service.v1.GetRateHistoryRequest\x1a'.rate_service.v1.GetRateHistoryResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/rates/{symbol}/history\x12y\n" +
+	"\n" +
+	"GetCandles\x12\".rate_service.v1.GetCandlesRequest\x1a#.rate_service.v1.GetCandlesResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/rates/{symbol}/candles\x12m\n" +
+	"\vListSymbols\x12#.rate_service.v1.ListSymbolsRequest\x1a$.rate_service.v1.ListSymbolsResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/symbols\x12l\n" +
+	"\vHealthCheck\x12#.rate_service.v1.HealthCheckRequest\x1a$.rate_service.v1.HealthCheckResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/healthBUZSstudentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/grpc/rate_service_v1b\x06proto3"
 
 var (
 	file_rate_proto_rawDescOnce sync.Once
@@ -237,25 +1919,90 @@ func file_rate_proto_rawDescGZIP() []byte {
 	return file_rate_proto_rawDescData
 }
 
-var file_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_rate_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_rate_proto_goTypes = []any{
-	(*GetRatesRequest)(nil),     // 0: rate_service.v1.GetRatesRequest
-	(*GetRatesResponse)(nil),    // 1: rate_service.v1.GetRatesResponse
-	(*HealthCheckRequest)(nil),  // 2: rate_service.v1.HealthCheckRequest
-	(*HealthCheckResponse)(nil), // 3: rate_service.v1.HealthCheckResponse
-	(*timestamp.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(RateSource)(0),                // 0: rate_service.v1.RateSource
+	(CandleInterval)(0),            // 1: rate_service.v1.CandleInterval
+	(*GetRatesRequest)(nil),        // 2: rate_service.v1.GetRatesRequest
+	(*GetRatesResponse)(nil),       // 3: rate_service.v1.GetRatesResponse
+	(*PathLeg)(nil),                // 4: rate_service.v1.PathLeg
+	(*VWAP)(nil),                   // 5: rate_service.v1.VWAP
+	(*GetRatesBatchRequest)(nil),   // 6: rate_service.v1.GetRatesBatchRequest
+	(*RateResult)(nil),             // 7: rate_service.v1.RateResult
+	(*GetRatesBatchResponse)(nil),  // 8: rate_service.v1.GetRatesBatchResponse
+	(*GetOrderBookRequest)(nil),    // 9: rate_service.v1.GetOrderBookRequest
+	(*PriceLevel)(nil),             // 10: rate_service.v1.PriceLevel
+	(*GetOrderBookResponse)(nil),   // 11: rate_service.v1.GetOrderBookResponse
+	(*SubscribeRatesRequest)(nil),  // 12: rate_service.v1.SubscribeRatesRequest
+	(*SubscribeRatesResponse)(nil), // 13: rate_service.v1.SubscribeRatesResponse
+	(*GetRateHistoryRequest)(nil),  // 14: rate_service.v1.GetRateHistoryRequest
+	(*RateRecord)(nil),             // 15: rate_service.v1.RateRecord
+	(*GetRateHistoryResponse)(nil), // 16: rate_service.v1.GetRateHistoryResponse
+	(*GetCandlesRequest)(nil),      // 17: rate_service.v1.GetCandlesRequest
+	(*OHLC)(nil),                   // 18: rate_service.v1.OHLC
+	(*Candle)(nil),                 // 19: rate_service.v1.Candle
+	(*GetCandlesResponse)(nil),     // 20: rate_service.v1.GetCandlesResponse
+	(*ListSymbolsRequest)(nil),     // 21: rate_service.v1.ListSymbolsRequest
+	(*SymbolInfo)(nil),             // 22: rate_service.v1.SymbolInfo
+	(*ListSymbolsResponse)(nil),    // 23: rate_service.v1.ListSymbolsResponse
+	(*HealthCheckRequest)(nil),     // 24: rate_service.v1.HealthCheckRequest
+	(*ComponentHealth)(nil),        // 25: rate_service.v1.ComponentHealth
+	(*HealthCheckResponse)(nil),    // 26: rate_service.v1.HealthCheckResponse
+	(*timestamp.Timestamp)(nil),    // 27: google.protobuf.Timestamp
+	(*status.Status)(nil),          // 28: google.rpc.Status
 }
 var file_rate_proto_depIdxs = []int32{
-	4, // 0: rate_service.v1.GetRatesResponse.timestamp:type_name -> google.protobuf.Timestamp
-	0, // 1: rate_service.v1.RateService.GetRates:input_type -> rate_service.v1.GetRatesRequest
-	2, // 2: rate_service.v1.RateService.HealthCheck:input_type -> rate_service.v1.HealthCheckRequest
-	1, // 3: rate_service.v1.RateService.GetRates:output_type -> rate_service.v1.GetRatesResponse
-	3, // 4: rate_service.v1.RateService.HealthCheck:output_type -> rate_service.v1.HealthCheckResponse
-	3, // [3:5] is the sub-list for method output_type
-	1, // [1:3] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	27, // 0: rate_service.v1.GetRatesResponse.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 1: rate_service.v1.GetRatesResponse.source:type_name -> rate_service.v1.RateSource
+	5,  // 2: rate_service.v1.GetRatesResponse.buy_vwap:type_name -> rate_service.v1.VWAP
+	5,  // 3: rate_service.v1.GetRatesResponse.sell_vwap:type_name -> rate_service.v1.VWAP
+	4,  // 4: rate_service.v1.GetRatesResponse.path:type_name -> rate_service.v1.PathLeg
+	3,  // 5: rate_service.v1.RateResult.rate:type_name -> rate_service.v1.GetRatesResponse
+	28, // 6: rate_service.v1.RateResult.error:type_name -> google.rpc.Status
+	7,  // 7: rate_service.v1.GetRatesBatchResponse.results:type_name -> rate_service.v1.RateResult
+	27, // 8: rate_service.v1.GetOrderBookResponse.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 9: rate_service.v1.GetOrderBookResponse.asks:type_name -> rate_service.v1.PriceLevel
+	10, // 10: rate_service.v1.GetOrderBookResponse.bids:type_name -> rate_service.v1.PriceLevel
+	27, // 11: rate_service.v1.SubscribeRatesResponse.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 12: rate_service.v1.GetRateHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	27, // 13: rate_service.v1.GetRateHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	27, // 14: rate_service.v1.RateRecord.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 15: rate_service.v1.GetRateHistoryResponse.rates:type_name -> rate_service.v1.RateRecord
+	1,  // 16: rate_service.v1.GetCandlesRequest.interval:type_name -> rate_service.v1.CandleInterval
+	27, // 17: rate_service.v1.GetCandlesRequest.from:type_name -> google.protobuf.Timestamp
+	27, // 18: rate_service.v1.GetCandlesRequest.to:type_name -> google.protobuf.Timestamp
+	27, // 19: rate_service.v1.Candle.open_time:type_name -> google.protobuf.Timestamp
+	18, // 20: rate_service.v1.Candle.mid:type_name -> rate_service.v1.OHLC
+	18, // 21: rate_service.v1.Candle.ask:type_name -> rate_service.v1.OHLC
+	18, // 22: rate_service.v1.Candle.bid:type_name -> rate_service.v1.OHLC
+	1,  // 23: rate_service.v1.GetCandlesResponse.interval:type_name -> rate_service.v1.CandleInterval
+	19, // 24: rate_service.v1.GetCandlesResponse.candles:type_name -> rate_service.v1.Candle
+	22, // 25: rate_service.v1.ListSymbolsResponse.symbols:type_name -> rate_service.v1.SymbolInfo
+	27, // 26: rate_service.v1.ListSymbolsResponse.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 27: rate_service.v1.ComponentHealth.checked_at:type_name -> google.protobuf.Timestamp
+	25, // 28: rate_service.v1.HealthCheckResponse.components:type_name -> rate_service.v1.ComponentHealth
+	2,  // 29: rate_service.v1.RateService.GetRates:input_type -> rate_service.v1.GetRatesRequest
+	6,  // 30: rate_service.v1.RateService.GetRatesBatch:input_type -> rate_service.v1.GetRatesBatchRequest
+	9,  // 31: rate_service.v1.RateService.GetOrderBook:input_type -> rate_service.v1.GetOrderBookRequest
+	12, // 32: rate_service.v1.RateService.SubscribeRates:input_type -> rate_service.v1.SubscribeRatesRequest
+	14, // 33: rate_service.v1.RateService.GetRateHistory:input_type -> rate_service.v1.GetRateHistoryRequest
+	17, // 34: rate_service.v1.RateService.GetCandles:input_type -> rate_service.v1.GetCandlesRequest
+	21, // 35: rate_service.v1.RateService.ListSymbols:input_type -> rate_service.v1.ListSymbolsRequest
+	24, // 36: rate_service.v1.RateService.HealthCheck:input_type -> rate_service.v1.HealthCheckRequest
+	3,  // 37: rate_service.v1.RateService.GetRates:output_type -> rate_service.v1.GetRatesResponse
+	8,  // 38: rate_service.v1.RateService.GetRatesBatch:output_type -> rate_service.v1.GetRatesBatchResponse
+	11, // 39: rate_service.v1.RateService.GetOrderBook:output_type -> rate_service.v1.GetOrderBookResponse
+	13, // 40: rate_service.v1.RateService.SubscribeRates:output_type -> rate_service.v1.SubscribeRatesResponse
+	16, // 41: rate_service.v1.RateService.GetRateHistory:output_type -> rate_service.v1.GetRateHistoryResponse
+	20, // 42: rate_service.v1.RateService.GetCandles:output_type -> rate_service.v1.GetCandlesResponse
+	23, // 43: rate_service.v1.RateService.ListSymbols:output_type -> rate_service.v1.ListSymbolsResponse
+	26, // 44: rate_service.v1.RateService.HealthCheck:output_type -> rate_service.v1.HealthCheckResponse
+	37, // [37:45] is the sub-list for method output_type
+	29, // [29:37] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_rate_proto_init() }
@@ -263,18 +2010,23 @@ func file_rate_proto_init() {
 	if File_rate_proto != nil {
 		return
 	}
+	file_rate_proto_msgTypes[5].OneofWrappers = []any{
+		(*RateResult_Rate)(nil),
+		(*RateResult_Error)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rate_proto_rawDesc), len(file_rate_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_rate_proto_goTypes,
 		DependencyIndexes: file_rate_proto_depIdxs,
+		EnumInfos:         file_rate_proto_enumTypes,
 		MessageInfos:      file_rate_proto_msgTypes,
 	}.Build()
 	File_rate_proto = out.File
@@ -19,8 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RateService_GetRates_FullMethodName    = "/rate_service.v1.RateService/GetRates"
-	RateService_HealthCheck_FullMethodName = "/rate_service.v1.RateService/HealthCheck"
+	RateService_GetRates_FullMethodName       = "/rate_service.v1.RateService/GetRates"
+	RateService_GetRatesBatch_FullMethodName  = "/rate_service.v1.RateService/GetRatesBatch"
+	RateService_GetOrderBook_FullMethodName   = "/rate_service.v1.RateService/GetOrderBook"
+	RateService_SubscribeRates_FullMethodName = "/rate_service.v1.RateService/SubscribeRates"
+	RateService_GetRateHistory_FullMethodName = "/rate_service.v1.RateService/GetRateHistory"
+	RateService_GetCandles_FullMethodName     = "/rate_service.v1.RateService/GetCandles"
+	RateService_ListSymbols_FullMethodName    = "/rate_service.v1.RateService/ListSymbols"
+	RateService_HealthCheck_FullMethodName    = "/rate_service.v1.RateService/HealthCheck"
 )
 
 // RateServiceClient is the client API for RateService service.
@@ -28,6 +34,12 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RateServiceClient interface {
 	GetRates(ctx context.Context, in *GetRatesRequest, opts ...grpc.CallOption) (*GetRatesResponse, error)
+	GetRatesBatch(ctx context.Context, in *GetRatesBatchRequest, opts ...grpc.CallOption) (*GetRatesBatchResponse, error)
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error)
+	SubscribeRates(ctx context.Context, in *SubscribeRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeRatesResponse], error)
+	GetRateHistory(ctx context.Context, in *GetRateHistoryRequest, opts ...grpc.CallOption) (*GetRateHistoryResponse, error)
+	GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error)
+	ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsResponse, error)
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 }
 
@@ -49,6 +61,75 @@ func (c *rateServiceClient) GetRates(ctx context.Context, in *GetRatesRequest, o
 	return out, nil
 }
 
+func (c *rateServiceClient) GetRatesBatch(ctx context.Context, in *GetRatesBatchRequest, opts ...grpc.CallOption) (*GetRatesBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRatesBatchResponse)
+	err := c.cc.Invoke(ctx, RateService_GetRatesBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderBookResponse)
+	err := c.cc.Invoke(ctx, RateService_GetOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateServiceClient) SubscribeRates(ctx context.Context, in *SubscribeRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeRatesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RateService_ServiceDesc.Streams[0], RateService_SubscribeRates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRatesRequest, SubscribeRatesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RateService_SubscribeRatesClient = grpc.ServerStreamingClient[SubscribeRatesResponse]
+
+func (c *rateServiceClient) GetRateHistory(ctx context.Context, in *GetRateHistoryRequest, opts ...grpc.CallOption) (*GetRateHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRateHistoryResponse)
+	err := c.cc.Invoke(ctx, RateService_GetRateHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateServiceClient) GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCandlesResponse)
+	err := c.cc.Invoke(ctx, RateService_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rateServiceClient) ListSymbols(ctx context.Context, in *ListSymbolsRequest, opts ...grpc.CallOption) (*ListSymbolsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSymbolsResponse)
+	err := c.cc.Invoke(ctx, RateService_ListSymbols_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *rateServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthCheckResponse)
@@ -64,6 +145,12 @@ func (c *rateServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequ
 // for forward compatibility.
 type RateServiceServer interface {
 	GetRates(context.Context, *GetRatesRequest) (*GetRatesResponse, error)
+	GetRatesBatch(context.Context, *GetRatesBatchRequest) (*GetRatesBatchResponse, error)
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error)
+	SubscribeRates(*SubscribeRatesRequest, grpc.ServerStreamingServer[SubscribeRatesResponse]) error
+	GetRateHistory(context.Context, *GetRateHistoryRequest) (*GetRateHistoryResponse, error)
+	GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error)
+	ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsResponse, error)
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	mustEmbedUnimplementedRateServiceServer()
 }
@@ -78,6 +165,24 @@ type UnimplementedRateServiceServer struct{}
 func (UnimplementedRateServiceServer) GetRates(context.Context, *GetRatesRequest) (*GetRatesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRates not implemented")
 }
+func (UnimplementedRateServiceServer) GetRatesBatch(context.Context, *GetRatesBatchRequest) (*GetRatesBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatesBatch not implemented")
+}
+func (UnimplementedRateServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedRateServiceServer) SubscribeRates(*SubscribeRatesRequest, grpc.ServerStreamingServer[SubscribeRatesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeRates not implemented")
+}
+func (UnimplementedRateServiceServer) GetRateHistory(context.Context, *GetRateHistoryRequest) (*GetRateHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateHistory not implemented")
+}
+func (UnimplementedRateServiceServer) GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
+func (UnimplementedRateServiceServer) ListSymbols(context.Context, *ListSymbolsRequest) (*ListSymbolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSymbols not implemented")
+}
 func (UnimplementedRateServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -120,6 +225,107 @@ func _RateService_GetRates_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RateService_GetRatesBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatesBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateServiceServer).GetRatesBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateService_GetRatesBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateServiceServer).GetRatesBatch(ctx, req.(*GetRatesBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateService_SubscribeRates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RateServiceServer).SubscribeRates(m, &grpc.GenericServerStream[SubscribeRatesRequest, SubscribeRatesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RateService_SubscribeRatesServer = grpc.ServerStreamingServer[SubscribeRatesResponse]
+
+func _RateService_GetRateHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateServiceServer).GetRateHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateService_GetRateHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateServiceServer).GetRateHistory(ctx, req.(*GetRateHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateService_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateServiceServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateService_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateServiceServer).GetCandles(ctx, req.(*GetCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RateService_ListSymbols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSymbolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RateServiceServer).ListSymbols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RateService_ListSymbols_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RateServiceServer).ListSymbols(ctx, req.(*ListSymbolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _RateService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthCheckRequest)
 	if err := dec(in); err != nil {
@@ -149,11 +355,37 @@ var RateService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRates",
 			Handler:    _RateService_GetRates_Handler,
 		},
+		{
+			MethodName: "GetRatesBatch",
+			Handler:    _RateService_GetRatesBatch_Handler,
+		},
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _RateService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "GetRateHistory",
+			Handler:    _RateService_GetRateHistory_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _RateService_GetCandles_Handler,
+		},
+		{
+			MethodName: "ListSymbols",
+			Handler:    _RateService_ListSymbols_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _RateService_HealthCheck_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeRates",
+			Handler:       _RateService_SubscribeRates_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "rate.proto",
 }
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID сохраняет идентификатор запроса клиента в контексте
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса клиента
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// ContextFields возвращает поля trace_id и span_id активного спана и request_id
+// запроса, чтобы запись лога можно было найти по трассе и наоборот
+func ContextFields(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", spanContext.TraceID().String()),
+			zap.String("span_id", spanContext.SpanID().String()))
+	}
+	if requestID, ok := RequestIDFromContext(ctx); ok {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	return fields
+}
+
+// WithContext возвращает логгер с полями ContextFields. Если в контексте нет
+// ни спана, ни идентификатора запроса, возвращается исходный логгер.
+func WithContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
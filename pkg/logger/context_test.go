@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithContext(t *testing.T) {
+	// Arrange
+	core, logs := observer.New(zapcore.DebugLevel)
+	base := zap.New(core)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = ContextWithRequestID(ctx, "req-1")
+
+	// Act
+	WithContext(ctx, base).Info("traced")
+	WithContext(context.Background(), base).Info("untraced")
+
+	// Assert
+	entries := logs.All()
+	assert.Equal(t, map[string]interface{}{
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":    "00f067aa0ba902b7",
+		"request_id": "req-1",
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap())
+	assert.Same(t, base, WithContext(context.Background(), base))
+}
@@ -1,49 +1,188 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 const (
-	LevelDebug = "DEBUG"
-	LevelInfo  = "INFO"
+	LevelDebug  = "DEBUG"
+	LevelInfo   = "INFO"
+	LevelWarn   = "WARN"
+	LevelError  = "ERROR"
+	LevelDPanic = "DPANIC"
+	LevelPanic  = "PANIC"
+	LevelFatal  = "FATAL"
 )
 
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+)
+
+// samplingTick - интервал, в котором считаются одинаковые записи при выборке
+const samplingTick = time.Second
+
+var levelNames = []string{LevelDebug, LevelInfo, LevelWarn, LevelError, LevelDPanic, LevelPanic, LevelFatal}
+
 var (
 	logger      *zap.Logger
 	once        sync.Once
-	atomicLevel zap.AtomicLevel
+	atomicLevel = zap.NewAtomicLevel()
+	components  = newComponentLevels()
 )
 
-func BuildLogger(logLevel string) {
+type options struct {
+	encoding           string
+	componentLevels    string
+	samplingInitial    int
+	samplingThereafter int
+}
+
+// Option настраивает логгер в BuildLogger
+type Option func(*options)
+
+// WithEncoding задает формат записей: json (по умолчанию) или console для локальной разработки
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithComponentLevels задает уровни компонентов вида "kucoin=debug,repository=warn"
+func WithComponentLevels(spec string) Option {
+	return func(o *options) {
+		o.componentLevels = spec
+	}
+}
+
+// WithSampling включает выборку одинаковых записей: в течение секунды пишутся
+// первые initial записей с одним сообщением и уровнем, затем каждая thereafter-я.
+// initial <= 0 отключает выборку.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.samplingInitial = initial
+		o.samplingThereafter = thereafter
+	}
+}
+
+// BuildLogger создает глобальный логгер. Некорректный уровень не останавливает
+// сервис: используется INFO, а ошибка записывается в лог.
+func BuildLogger(logLevel string, opts ...Option) {
 	once.Do(func() {
-		atomicLevel = zap.NewAtomicLevel()
-		SetLevel(logLevel)
+		o := options{encoding: EncodingJSON}
+		for _, opt := range opts {
+			opt(&o)
+		}
+
+		var configErrors []error
+		if err := SetLevel(logLevel); err != nil {
+			atomicLevel.SetLevel(zapcore.InfoLevel)
+			configErrors = append(configErrors, err)
+		}
+		if err := SetComponentLevels(o.componentLevels); err != nil {
+			configErrors = append(configErrors, err)
+		}
+
 		encoderCfg := zap.NewProductionEncoderConfig()
-		logger = zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), os.Stdout, atomicLevel), zap.AddCaller())
+		var encoder zapcore.Encoder
+		switch strings.ToLower(o.encoding) {
+		case EncodingConsole:
+			encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+			encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder
+			encoder = zapcore.NewConsoleEncoder(encoderCfg)
+		case EncodingJSON, "":
+			encoder = zapcore.NewJSONEncoder(encoderCfg)
+		default:
+			encoder = zapcore.NewJSONEncoder(encoderCfg)
+			configErrors = append(configErrors, fmt.Errorf("invalid log encoding %q, using json", o.encoding))
+		}
+
+		// Уровень проверяет componentCore, поэтому нижний core пропускает все записи
+		var core zapcore.Core = zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zap.DebugLevel)
+		if o.samplingInitial > 0 {
+			core = zapcore.NewSamplerWithOptions(core, samplingTick, o.samplingInitial, o.samplingThereafter)
+		}
+		logger = zap.New(&componentCore{Core: core}, zap.AddCaller())
+
+		for _, err := range configErrors {
+			logger.Error("Invalid logger configuration", zap.Error(err))
+		}
 	})
 }
 
-func SetLevel(logLevel string) {
-	switch strings.ToUpper(logLevel) {
-	case LevelDebug:
-		atomicLevel.SetLevel(zapcore.DebugLevel)
-	case LevelInfo:
-		atomicLevel.SetLevel(zapcore.InfoLevel)
-	default:
-		panic("invalid log level specified for logger")
+// SetLevel меняет общий уровень логирования
+func SetLevel(logLevel string) error {
+	level, err := parseLevel(logLevel)
+	if err != nil {
+		return err
 	}
+	atomicLevel.SetLevel(level)
+	return nil
 }
 
+// CurrentLevel возвращает общий уровень логирования
 func CurrentLevel() string {
 	return atomicLevel.String()
 }
 
+// SetComponentLevel задает уровень компонента - логгера, созданного через
+// Named(component). Пустой уровень возвращает компонент к общему уровню.
+func SetComponentLevel(component, logLevel string) error {
+	component = strings.TrimSpace(component)
+	if component == "" {
+		return fmt.Errorf("component name is required")
+	}
+	if strings.TrimSpace(logLevel) == "" {
+		components.remove(component)
+		return nil
+	}
+
+	level, err := parseLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	components.set(component, level)
+	return nil
+}
+
+// SetComponentLevels заменяет уровни всех компонентов уровнями вида
+// "kucoin=debug,repository=warn"; пустая строка сбрасывает их
+func SetComponentLevels(spec string) error {
+	levels := make(map[string]zapcore.Level)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		component, value, ok := strings.Cut(item, "=")
+		component = strings.TrimSpace(component)
+		if !ok || component == "" {
+			return fmt.Errorf("invalid component level %q: expected component=level", item)
+		}
+		level, err := parseLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid component level %q: %w", item, err)
+		}
+		levels[component] = level
+	}
+
+	components.replace(levels)
+	return nil
+}
+
+// ComponentLevels возвращает уровни компонентов, заданные отдельно от общего
+func ComponentLevels() map[string]string {
+	return components.snapshot()
+}
+
 // Logger returns a global logger defined in this package.
 // If logger is nil function returns a logger with DEBUG level.
 func Logger() *zap.Logger {
@@ -53,3 +192,113 @@ func Logger() *zap.Logger {
 
 	return logger
 }
+
+func parseLevel(logLevel string) (zapcore.Level, error) {
+	value := strings.ToLower(strings.TrimSpace(logLevel))
+	if value == "" {
+		return zapcore.InfoLevel, fmt.Errorf("log level is empty")
+	}
+
+	level, err := zapcore.ParseLevel(value)
+	if err != nil {
+		return zapcore.InfoLevel, fmt.Errorf("invalid log level %q: expected one of %s", logLevel, strings.Join(levelNames, ", "))
+	}
+	return level, nil
+}
+
+// componentLevels хранит уровни компонентов, заданные отдельно от общего
+type componentLevels struct {
+	mu     sync.RWMutex
+	levels map[string]zapcore.Level
+}
+
+func newComponentLevels() *componentLevels {
+	return &componentLevels{levels: make(map[string]zapcore.Level)}
+}
+
+func (c *componentLevels) set(component string, level zapcore.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.levels[component] = level
+}
+
+func (c *componentLevels) remove(component string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.levels, component)
+}
+
+func (c *componentLevels) replace(levels map[string]zapcore.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.levels = levels
+}
+
+func (c *componentLevels) snapshot() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[string]string, len(c.levels))
+	for component, level := range c.levels {
+		result[component] = strings.ToUpper(level.String())
+	}
+	return result
+}
+
+// level возвращает уровень логгера с именем вида "main.kucoin": берется уровень
+// ближайшего к концу имени компонента, для которого он задан, иначе общий
+func (c *componentLevels) level(loggerName string) zapcore.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.levels) > 0 {
+		// Проверка выполняется на каждую запись, поэтому имя не разбивается на срез
+		for name := loggerName; name != ""; {
+			i := strings.LastIndexByte(name, '.')
+			if level, ok := c.levels[name[i+1:]]; ok {
+				return level
+			}
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	return atomicLevel.Level()
+}
+
+// minLevel возвращает самый подробный из общего уровня и уровней компонентов
+func (c *componentLevels) minLevel() zapcore.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	minimum := atomicLevel.Level()
+	for _, level := range c.levels {
+		if level < minimum {
+			minimum = level
+		}
+	}
+	return minimum
+}
+
+// componentCore пропускает запись, если ее уровень не ниже уровня компонента логгера
+type componentCore struct {
+	zapcore.Core
+}
+
+// Enabled отвечает на вопрос, может ли хоть один логгер писать на этом уровне:
+// имя логгера здесь неизвестно, окончательно уровень проверяется в Check
+func (c *componentCore) Enabled(level zapcore.Level) bool {
+	return level >= components.minLevel()
+}
+
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{Core: c.Core.With(fields)}
+}
+
+func (c *componentCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < components.level(entry.LoggerName) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedLogger возвращает логгер с проверкой уровней компонентов и записанные им сообщения
+func newObservedLogger(t *testing.T) (*zap.Logger, *observer.ObservedLogs) {
+	t.Cleanup(func() {
+		atomicLevel.SetLevel(zapcore.InfoLevel)
+		components.replace(make(map[string]zapcore.Level))
+	})
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(&componentCore{Core: core}), logs
+}
+
+func TestSetLevel(t *testing.T) {
+	_, _ = newObservedLogger(t)
+
+	for _, level := range []string{"debug", "INFO", "Warn", "ERROR", "dpanic", "panic", "fatal"} {
+		assert.NoError(t, SetLevel(level), level)
+	}
+	assert.Equal(t, "fatal", CurrentLevel())
+
+	assert.Error(t, SetLevel("verbose"))
+	assert.Error(t, SetLevel(""))
+	assert.Equal(t, "fatal", CurrentLevel())
+}
+
+func TestComponentLevels(t *testing.T) {
+	// Arrange
+	log, logs := newObservedLogger(t)
+	require.NoError(t, SetLevel(LevelInfo))
+	require.NoError(t, SetComponentLevels("kucoin=debug, repository=warn"))
+	kucoin := log.Named("main").Named("kucoin")
+	repository := log.Named("main").Named("repository")
+	service := log.Named("main").Named("service")
+
+	// Act
+	kucoin.Debug("kucoin debug")
+	repository.Info("repository info")
+	repository.Warn("repository warn")
+	service.Debug("service debug")
+	service.Info("service info")
+
+	// Assert
+	var messages []string
+	for _, entry := range logs.All() {
+		messages = append(messages, entry.Message)
+	}
+	assert.Equal(t, []string{"kucoin debug", "repository warn", "service info"}, messages)
+	assert.Equal(t, map[string]string{"kucoin": "DEBUG", "repository": "WARN"}, ComponentLevels())
+}
+
+func TestSetComponentLevel(t *testing.T) {
+	log, logs := newObservedLogger(t)
+	require.NoError(t, SetLevel(LevelWarn))
+
+	require.NoError(t, SetComponentLevel("kucoin", "debug"))
+	log.Named("kucoin").Debug("enabled")
+	require.NoError(t, SetComponentLevel("kucoin", ""))
+	log.Named("kucoin").Debug("disabled")
+
+	assert.Equal(t, 1, logs.Len())
+	assert.Error(t, SetComponentLevel("kucoin", "loud"))
+	assert.Error(t, SetComponentLevel("", "debug"))
+}
+
+func TestSetComponentLevels_Invalid(t *testing.T) {
+	_, _ = newObservedLogger(t)
+
+	assert.Error(t, SetComponentLevels("kucoin"))
+	assert.Error(t, SetComponentLevels("kucoin=loud"))
+}
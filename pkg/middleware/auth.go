@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Principal - аутентифицированный клиент
+type Principal struct {
+	// Subject - sub токена или имя API-ключа
+	Subject string
+	Scopes  []string
+	// Method - способ аутентификации: api_key или jwt
+	Method string
+}
+
+// HasScope сообщает, выдан ли клиенту scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext возвращает клиента, аутентифицированного перехватчиком Auth
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
+
+// APIKey - статический ключ клиента
+type APIKey struct {
+	// Name используется вместо ключа в логах и как Subject клиента
+	Name   string
+	Key    string
+	Scopes []string
+}
+
+// AuthConfig содержит настройки аутентификации
+type AuthConfig struct {
+	APIKeys []APIKey
+	// APIKeyHeader - ключ метаданных с API-ключом
+	APIKeyHeader string
+	// JWKS - ключи проверки JWT из заголовка authorization: Bearer; nil - JWT не принимаются
+	JWKS       *JWKS
+	Validation TokenValidation
+	// PublicMethods - методы (полное или короткое имя), доступные без аутентификации
+	PublicMethods []string
+	// MethodScopes - scope, необходимый для вызова метода (полное или короткое имя)
+	MethodScopes map[string]string
+	// DefaultScope требуется для методов, не перечисленных в MethodScopes, в том
+	// числе для новых RPC и reflection: доступ к ним нужно выдавать явно
+	DefaultScope string
+}
+
+// Auth аутентифицирует вызовы по API-ключу или JWT и проверяет scope метода
+type Auth struct {
+	config AuthConfig
+	logger *zap.Logger
+	now    func() time.Time
+	// keys - SHA-256 API-ключей: сравнение хешей одинаковой длины не раскрывает длину ключа
+	keys []hashedKey
+}
+
+type hashedKey struct {
+	hash   [sha256.Size]byte
+	name   string
+	scopes []string
+}
+
+// NewAuth создает аутентификацию
+func NewAuth(logger *zap.Logger, config AuthConfig) *Auth {
+	if config.APIKeyHeader == "" {
+		config.APIKeyHeader = "x-api-key"
+	}
+
+	keys := make([]hashedKey, 0, len(config.APIKeys))
+	for _, key := range config.APIKeys {
+		keys = append(keys, hashedKey{hash: sha256.Sum256([]byte(key.Key)), name: key.Name, scopes: key.Scopes})
+	}
+
+	return &Auth{
+		config: config,
+		logger: logger,
+		now:    time.Now,
+		keys:   keys,
+	}
+}
+
+// UnaryServerInterceptor аутентифицирует унарные вызовы
+func (a *Auth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor аутентифицирует открытие потоков
+func (a *Auth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authorize возвращает контекст с клиентом или ошибку Unauthenticated/PermissionDenied
+func (a *Auth) authorize(ctx context.Context, method string) (context.Context, error) {
+	if matchMethod(a.config.PublicMethods, method) {
+		return ctx, nil
+	}
+
+	principal, err := a.authenticate(ctx)
+	if err != nil {
+		a.logger.Debug("Authentication failed", zap.String("method", method), zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	scope := a.requiredScope(method)
+	if scope != "" && !principal.HasScope(scope) {
+		a.logger.Debug("Permission denied",
+			zap.String("method", method),
+			zap.String("subject", principal.Subject),
+			zap.String("scope", scope))
+		return nil, status.Errorf(codes.PermissionDenied, "scope %q is required", scope)
+	}
+
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+// authenticate проверяет JWT из authorization или API-ключ из метаданных
+func (a *Auth) authenticate(ctx context.Context) (*Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("authorization"); len(values) > 0 {
+		token, ok := bearerToken(values[0])
+		if !ok {
+			return nil, fmt.Errorf("authorization must use the Bearer scheme")
+		}
+		if a.config.JWKS == nil {
+			return nil, fmt.Errorf("bearer tokens are not accepted")
+		}
+
+		claims, err := a.config.JWKS.Verify(token, a.config.Validation, a.now())
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{Subject: claims.Subject, Scopes: claims.Scopes, Method: "jwt"}, nil
+	}
+
+	if values := md.Get(a.config.APIKeyHeader); len(values) > 0 {
+		hash := sha256.Sum256([]byte(values[0]))
+		for _, key := range a.keys {
+			if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 {
+				return &Principal{Subject: key.name, Scopes: key.scopes, Method: "api_key"}, nil
+			}
+		}
+		return nil, fmt.Errorf("invalid API key")
+	}
+
+	return nil, fmt.Errorf("missing credentials")
+}
+
+func (a *Auth) requiredScope(method string) string {
+	if scope, ok := a.config.MethodScopes[method]; ok {
+		return scope
+	}
+	if scope, ok := a.config.MethodScopes[shortMethod(method)]; ok {
+		return scope
+	}
+	return a.config.DefaultScope
+}
+
+func bearerToken(value string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// matchMethod сообщает, есть ли метод в списке по полному или короткому имени
+func matchMethod(methods []string, method string) bool {
+	short := shortMethod(method)
+	for _, m := range methods {
+		if m == method || m == short {
+			return true
+		}
+	}
+	return false
+}
+
+// shortMethod возвращает имя метода без сервиса: /pkg.Service/Method -> Method
+func shortMethod(method string) string {
+	return method[strings.LastIndex(method, "/")+1:]
+}
+
+// ParseAPIKeys разбирает ключи вида "name:key=scope1 scope2,name2:key2=scope3"
+func ParseAPIKeys(value string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		credentials, scopes, _ := strings.Cut(item, "=")
+		name, key, ok := strings.Cut(credentials, ":")
+		if !ok || name == "" || key == "" {
+			return nil, fmt.Errorf("invalid API key entry: expected name:key=scopes")
+		}
+		keys = append(keys, APIKey{Name: name, Key: key, Scopes: strings.Fields(scopes)})
+	}
+	return keys, nil
+}
+
+// ParseMethodScopes разбирает scope методов вида "GetRates=rates:read,GetCandles=history:read"
+func ParseMethodScopes(value string) (map[string]string, error) {
+	scopes := make(map[string]string)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		method, scope, ok := strings.Cut(item, "=")
+		method, scope = strings.TrimSpace(method), strings.TrimSpace(scope)
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method scope %q: expected method=scope", item)
+		}
+		scopes[method] = scope
+	}
+	return scopes, nil
+}
+
+// contextStream подменяет контекст потока
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,249 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testNow = time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	signed := encodeSegment(t, map[string]string{"alg": "ES256", "typ": "JWT", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// writeJWKS сохраняет публичные ключи в файл JWKS и загружает его
+func writeJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) *JWKS {
+	document := map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa-1", "alg": "RS256", "use": "sig",
+				"n": encodeBigInt(rsaKey.N), "e": encodeBigInt(big.NewInt(int64(rsaKey.E))),
+			},
+			{
+				"kty": "EC", "kid": "ec-1", "crv": "P-256",
+				"x": encodeBigInt(ecKey.X), "y": encodeBigInt(ecKey.Y),
+			},
+			// Симметричные ключи и ключи шифрования пропускаются
+			{"kty": "oct", "kid": "hmac-1", "k": "c2VjcmV0"},
+			{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		},
+	}
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	jwks, err := LoadJWKS(path)
+	require.NoError(t, err)
+	return jwks
+}
+
+func newTestAuth(t *testing.T) (*Auth, *rsa.PrivateKey, *ecdsa.PrivateKey) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	auth := NewAuth(zap.NewNop(), AuthConfig{
+		APIKeys: []APIKey{
+			{Name: "reader", Key: "reader-secret", Scopes: []string{"rates:read"}},
+			{Name: "nobody", Key: "nobody-secret"},
+		},
+		JWKS:          writeJWKS(t, rsaKey, ecKey),
+		Validation:    TokenValidation{Issuer: "https://auth.example.com", Audience: "rate-service"},
+		PublicMethods: []string{"HealthCheck"},
+		MethodScopes:  map[string]string{"GetRates": "rates:read", "GetRateHistory": "history:read"},
+		DefaultScope:  "admin",
+	})
+	auth.now = func() time.Time { return testNow }
+	return auth, rsaKey, ecKey
+}
+
+func validClaims(scope string) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "consumer-a",
+		"iss":   "https://auth.example.com",
+		"aud":   []string{"rate-service", "other"},
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func callWithMetadata(auth *Auth, method string, pairs ...string) (*Principal, error) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+
+	var principal *Principal
+	_, err := auth.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			principal, _ = PrincipalFromContext(ctx)
+			return nil, nil
+		})
+	return principal, err
+}
+
+func TestAuth_APIKey(t *testing.T) {
+	// Arrange
+	auth, _, _ := newTestAuth(t)
+
+	// Act
+	principal, err := callWithMetadata(auth, getRatesMethod, "x-api-key", "reader-secret")
+	_, invalidErr := callWithMetadata(auth, getRatesMethod, "x-api-key", "wrong")
+	_, missingErr := callWithMetadata(auth, getRatesMethod)
+	_, deniedErr := callWithMetadata(auth, getRatesMethod, "x-api-key", "nobody-secret")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, &Principal{Subject: "reader", Scopes: []string{"rates:read"}, Method: "api_key"}, principal)
+	assert.Equal(t, codes.Unauthenticated, status.Code(invalidErr))
+	assert.Equal(t, codes.Unauthenticated, status.Code(missingErr))
+	assert.Equal(t, codes.PermissionDenied, status.Code(deniedErr))
+}
+
+func TestAuth_JWT(t *testing.T) {
+	auth, rsaKey, ecKey := newTestAuth(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	expired := validClaims("rates:read")
+	expired["exp"] = testNow.Add(-time.Hour).Unix()
+	wrongAudience := validClaims("rates:read")
+	wrongAudience["aud"] = "other"
+	noExpiry := validClaims("rates:read")
+	delete(noExpiry, "exp")
+
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "rsa token", token: signRS256(t, rsaKey, "rsa-1", validClaims("rates:read")), method: getRatesMethod, wantCode: codes.OK},
+		{name: "ec token", token: signES256(t, ecKey, "ec-1", validClaims("rates:read history:read")), method: "/rate_service.v1.RateService/GetRateHistory", wantCode: codes.OK},
+		{name: "missing scope", token: signRS256(t, rsaKey, "rsa-1", validClaims("rates:read")), method: "/rate_service.v1.RateService/GetRateHistory", wantCode: codes.PermissionDenied},
+		{name: "unlisted method requires default scope", token: signRS256(t, rsaKey, "rsa-1", validClaims("rates:read")), method: "/rate_service.v1.RateService/Purge", wantCode: codes.PermissionDenied},
+		{name: "unknown signing key", token: signRS256(t, otherKey, "rsa-1", validClaims("rates:read")), method: getRatesMethod, wantCode: codes.Unauthenticated},
+		{name: "expired", token: signRS256(t, rsaKey, "rsa-1", expired), method: getRatesMethod, wantCode: codes.Unauthenticated},
+		{name: "no expiry", token: signRS256(t, rsaKey, "rsa-1", noExpiry), method: getRatesMethod, wantCode: codes.Unauthenticated},
+		{name: "wrong audience", token: signRS256(t, rsaKey, "rsa-1", wrongAudience), method: getRatesMethod, wantCode: codes.Unauthenticated},
+		{name: "malformed", token: "not-a-token", method: getRatesMethod, wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := callWithMetadata(auth, tt.method, "authorization", "Bearer "+tt.token)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				require.NotNil(t, principal)
+				assert.Equal(t, "consumer-a", principal.Subject)
+				assert.Equal(t, "jwt", principal.Method)
+			}
+		})
+	}
+}
+
+func TestAuth_AlgorithmNoneIsRejected(t *testing.T) {
+	auth, _, _ := newTestAuth(t)
+	token := encodeSegment(t, map[string]string{"alg": "none"}) + "." + encodeSegment(t, validClaims("rates:read")) + "."
+
+	_, err := callWithMetadata(auth, getRatesMethod, "authorization", "Bearer "+token)
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuth_PublicMethodIsAnonymous(t *testing.T) {
+	auth, _, _ := newTestAuth(t)
+
+	principal, err := callWithMetadata(auth, "/rate_service.v1.RateService/HealthCheck")
+
+	assert.NoError(t, err)
+	assert.Nil(t, principal)
+}
+
+func TestAuth_Stream(t *testing.T) {
+	// Arrange
+	auth, _, _ := newTestAuth(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "reader-secret"))
+	info := &grpc.StreamServerInfo{FullMethod: "/rate_service.v1.RateService/SubscribeRates", IsServerStream: true}
+	auth.config.MethodScopes["SubscribeRates"] = "rates:read"
+
+	// Act
+	var principal *Principal
+	err := auth.StreamServerInterceptor()(nil, &testStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+		principal, _ = PrincipalFromContext(stream.Context())
+		return nil
+	})
+	_, anonymousErr := callWithMetadata(auth, info.FullMethod)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "reader", principal.Subject)
+	assert.Equal(t, codes.Unauthenticated, status.Code(anonymousErr))
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("reader:secret-1=rates:read history:read, admin:secret-2=admin")
+	require.NoError(t, err)
+	assert.Equal(t, []APIKey{
+		{Name: "reader", Key: "secret-1", Scopes: []string{"rates:read", "history:read"}},
+		{Name: "admin", Key: "secret-2", Scopes: []string{"admin"}},
+	}, keys)
+
+	_, err = ParseAPIKeys("secret-without-name=admin")
+	assert.Error(t, err)
+}
+
+func TestParseMethodScopes(t *testing.T) {
+	scopes, err := ParseMethodScopes("GetRates=rates:read, GetCandles=history:read")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"GetRates": "rates:read", "GetCandles": "history:read"}, scopes)
+
+	_, err = ParseMethodScopes("GetRates")
+	assert.Error(t, err)
+}
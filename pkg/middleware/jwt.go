@@ -0,0 +1,323 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // Регистрирует SHA-256 для RS256 и ES256
+	_ "crypto/sha512" // Регистрирует SHA-384 и SHA-512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken - токен поврежден, подписан неизвестным ключом или не прошел проверку подписи
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenExpired - срок действия токена истек или еще не наступил
+	ErrTokenExpired = errors.New("token is expired or not yet valid")
+	// ErrTokenClaims - токен выдан другим издателем или для другой аудитории
+	ErrTokenClaims = errors.New("token issuer or audience mismatch")
+)
+
+// tokenLeeway - допустимое расхождение часов с издателем токенов
+const tokenLeeway = 30 * time.Second
+
+// JWKS - набор публичных ключей для проверки подписи JWT (RFC 7517)
+type JWKS struct {
+	keys []jwk
+}
+
+type jwk struct {
+	id        string
+	algorithm string
+	key       crypto.PublicKey
+}
+
+type jwkJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LoadJWKS читает набор ключей из файла в формате JWKS
+func LoadJWKS(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS разбирает набор ключей. Поддерживаются ключи RSA и EC (P-256, P-384, P-521)
+// для подписи; ключи для шифрования и других типов пропускаются.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var document struct {
+		Keys []jwkJSON `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	jwks := &JWKS{}
+	for i, raw := range document.Keys {
+		if raw.Use != "" && raw.Use != "sig" || raw.Kty != "RSA" && raw.Kty != "EC" {
+			continue
+		}
+
+		key, err := raw.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %d (kid %q): %w", i, raw.Kid, err)
+		}
+		jwks.keys = append(jwks.keys, jwk{id: raw.Kid, algorithm: raw.Alg, key: key})
+	}
+
+	if len(jwks.keys) == 0 {
+		return nil, errors.New("JWKS contains no signing keys")
+	}
+	return jwks, nil
+}
+
+func (k jwkJSON) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// TokenClaims - проверенные утверждения JWT
+type TokenClaims struct {
+	Subject string
+	Scopes  []string
+}
+
+// TokenValidation - ожидаемые издатель и аудитория токена; пустое значение не проверяется
+type TokenValidation struct {
+	Issuer   string
+	Audience string
+}
+
+// Verify проверяет подпись, срок действия, издателя и аудиторию токена
+func (s *JWKS) Verify(token string, validation TokenValidation, now time.Time) (TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return TokenClaims{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: invalid header: %v", ErrInvalidToken, err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: invalid signature encoding", ErrInvalidToken)
+	}
+	if err := s.verifySignature(header.Alg, header.Kid, parts[0]+"."+parts[1], signature); err != nil {
+		return TokenClaims{}, err
+	}
+
+	var claims struct {
+		Subject   string          `json:"sub"`
+		Issuer    string          `json:"iss"`
+		Audience  json.RawMessage `json:"aud"`
+		ExpiresAt *json.Number    `json:"exp"`
+		NotBefore *json.Number    `json:"nbf"`
+		Scope     string          `json:"scope"`
+		Scp       []string        `json:"scp"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: invalid claims: %v", ErrInvalidToken, err)
+	}
+
+	// Токен без срока действия не принимается
+	if claims.ExpiresAt == nil {
+		return TokenClaims{}, fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	expiresAt, err := claims.ExpiresAt.Int64()
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("%w: invalid exp claim", ErrInvalidToken)
+	}
+	if now.After(time.Unix(expiresAt, 0).Add(tokenLeeway)) {
+		return TokenClaims{}, ErrTokenExpired
+	}
+	if claims.NotBefore != nil {
+		notBefore, err := claims.NotBefore.Int64()
+		if err != nil {
+			return TokenClaims{}, fmt.Errorf("%w: invalid nbf claim", ErrInvalidToken)
+		}
+		if now.Add(tokenLeeway).Before(time.Unix(notBefore, 0)) {
+			return TokenClaims{}, ErrTokenExpired
+		}
+	}
+
+	if validation.Issuer != "" && claims.Issuer != validation.Issuer {
+		return TokenClaims{}, ErrTokenClaims
+	}
+	if validation.Audience != "" && !hasAudience(claims.Audience, validation.Audience) {
+		return TokenClaims{}, ErrTokenClaims
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	scopes = append(scopes, claims.Scp...)
+	return TokenClaims{Subject: claims.Subject, Scopes: scopes}, nil
+}
+
+// verifySignature ищет ключ по kid (без kid - перебирает подходящие ключи) и проверяет подпись
+func (s *JWKS) verifySignature(algorithm, kid, signed string, signature []byte) error {
+	hash, ok := signatureHash(algorithm)
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, algorithm)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	for _, key := range s.keys {
+		if kid != "" && key.id != kid {
+			continue
+		}
+		if key.algorithm != "" && key.algorithm != algorithm {
+			continue
+		}
+		if verifyWithKey(key.key, algorithm, hash, digest, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+}
+
+func signatureHash(algorithm string) (crypto.Hash, bool) {
+	switch algorithm {
+	case "RS256", "ES256":
+		return crypto.SHA256, true
+	case "RS384", "ES384":
+		return crypto.SHA384, true
+	case "RS512", "ES512":
+		return crypto.SHA512, true
+	default:
+		return 0, false
+	}
+}
+
+func verifyWithKey(key crypto.PublicKey, algorithm string, hash crypto.Hash, digest, signature []byte) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "RS") {
+			return false
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "ES") {
+			return false
+		}
+		// Подпись ES* - конкатенация r и s фиксированной длины
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		sValue := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, sValue)
+
+	default:
+		return false
+	}
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// hasAudience проверяет aud, который может быть строкой или массивом строк
+func hasAudience(raw json.RawMessage, audience string) bool {
+	if len(raw) == 0 {
+		return false
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return false
+	}
+	for _, item := range list {
+		if item == audience {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+// Package middleware содержит перехватчики gRPC-сервера, не связанные с телеметрией
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/telemetry"
+)
+
+// Limit - квота клиента на метод: Rate запросов в секунду с всплеском до Burst.
+// Нулевой Rate - без ограничения.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// QuotaConfig содержит настройки квот клиентов
+type QuotaConfig struct {
+	// Default - квота для методов без отдельной настройки
+	Default Limit
+	// Methods - квоты по полному (/rate_service.v1.RateService/GetRates)
+	// или короткому (GetRates) имени метода
+	Methods map[string]Limit
+	// IdleTTL - время, после которого квота неактивного клиента забывается
+	IdleTTL time.Duration
+}
+
+// Quota ограничивает частоту запросов каждого клиента к каждому методу, чтобы
+// один клиент не мог израсходовать общий бюджет запросов к биржам
+type Quota struct {
+	config QuotaConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	client string
+	method string
+}
+
+// bucket - token bucket клиента для одного метода
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	lastSeen time.Time
+}
+
+// NewQuota создает квоты клиентов
+func NewQuota(config QuotaConfig) *Quota {
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = 10 * time.Minute
+	}
+
+	return &Quota{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[bucketKey]*bucket),
+	}
+}
+
+// UnaryServerInterceptor отклоняет унарные запросы сверх квоты клиента
+func (q *Quota) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := q.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor отклоняет открытие потоков сверх квоты клиента
+func (q *Quota) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := q.allow(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// allow расходует квоту клиента на метод или возвращает ResourceExhausted с RetryInfo
+func (q *Quota) allow(ctx context.Context, method string) error {
+	limit := q.limit(method)
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	retryAfter, ok := q.take(q.clientKey(ctx), method, limit)
+	if ok {
+		return nil
+	}
+
+	telemetry.QuotaRejectedCounter.WithLabelValues(method).Inc()
+	return quotaError(method, retryAfter)
+}
+
+// limit возвращает квоту метода: сначала по полному имени, затем по короткому
+func (q *Quota) limit(method string) Limit {
+	if limit, ok := q.config.Methods[method]; ok {
+		return limit
+	}
+	if limit, ok := q.config.Methods[shortMethod(method)]; ok {
+		return limit
+	}
+	return q.config.Default
+}
+
+// clientKey определяет клиента, аутентифицированного перехватчиком Auth, иначе
+// по адресу без порта. Непроверенный API-ключ из метаданных не используется:
+// подставляя новый ключ в каждый запрос, клиент получал бы новую квоту.
+func (q *Quota) clientKey(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.Subject != "" {
+		return principal.Method + ":" + principal.Subject
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		// Запросы REST-шлюза приходят с локального адреса: клиент определяется
+		// по адресу, который шлюз добавил последним в x-forwarded-for
+		if ip := net.ParseIP(addr); ip != nil && ip.IsLoopback() {
+			if forwarded := lastForwardedFor(md); forwarded != "" {
+				addr = forwarded
+			}
+		}
+		return "addr:" + addr
+	}
+	return "unknown"
+}
+
+// lastForwardedFor возвращает последний адрес x-forwarded-for. Предыдущие адреса
+// передает сам клиент, поэтому им нельзя доверять.
+func lastForwardedFor(md metadata.MD) string {
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return ""
+	}
+	addrs := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(addrs[len(addrs)-1])
+}
+
+// take списывает один запрос из корзины клиента. Если квота исчерпана,
+// возвращает время до появления следующего запроса.
+func (q *Quota) take(client, method string, limit Limit) (time.Duration, bool) {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	q.sweep(now)
+
+	key := bucketKey{client: client, method: method}
+	b, ok := q.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		q.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * limit.Rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.last = now
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)), false
+}
+
+// sweep удаляет корзины клиентов, не обращавшихся к сервису дольше IdleTTL
+func (q *Quota) sweep(now time.Time) {
+	if now.Sub(q.lastSweep) < q.config.IdleTTL {
+		return
+	}
+	q.lastSweep = now
+
+	for key, b := range q.buckets {
+		if now.Sub(b.lastSeen) >= q.config.IdleTTL {
+			delete(q.buckets, key)
+		}
+	}
+}
+
+func quotaError(method string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, "client request quota exceeded")
+	detailed, err := st.WithDetails(
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{{
+				Subject:     "method:" + method,
+				Description: "client request quota exceeded",
+			}},
+		},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// ParseLimits разбирает квоты методов вида "GetRates=5:10,SubscribeRates=1:2",
+// где 5 - запросов в секунду, 10 - всплеск
+func ParseLimits(value string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		method, spec, ok := strings.Cut(item, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid method quota %q: expected method=rate:burst", item)
+		}
+
+		limit, err := ParseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid method quota %q: %w", item, err)
+		}
+		limits[strings.TrimSpace(method)] = limit
+	}
+	return limits, nil
+}
+
+// ParseLimit разбирает квоту вида "rate:burst" или "rate" (всплеск равен rate)
+func ParseLimit(spec string) (Limit, error) {
+	rateValue, burstValue, hasBurst := strings.Cut(strings.TrimSpace(spec), ":")
+
+	rate, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil || rate < 0 {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateValue)
+	}
+
+	limit := Limit{Rate: rate, Burst: int(rate)}
+	if hasBurst {
+		burst, err := strconv.Atoi(burstValue)
+		if err != nil || burst < 0 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstValue)
+		}
+		limit.Burst = burst
+	}
+	return limit, nil
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const getRatesMethod = "/rate_service.v1.RateService/GetRates"
+
+func newTestQuota(config QuotaConfig) (*Quota, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	quota := NewQuota(config)
+	quota.now = func() time.Time { return now }
+	return quota, &now
+}
+
+func peerContext(addr string) context.Context {
+	tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+}
+
+func callUnary(interceptor grpc.UnaryServerInterceptor, ctx context.Context, method string) error {
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		})
+	return err
+}
+
+func TestQuota_RejectsOverLimitWithRetryInfo(t *testing.T) {
+	// Arrange
+	quota, now := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 2}})
+	interceptor := quota.UnaryServerInterceptor()
+	ctx := peerContext("10.0.0.1:5000")
+
+	// Act
+	first := callUnary(interceptor, ctx, getRatesMethod)
+	second := callUnary(interceptor, ctx, getRatesMethod)
+	rejected := callUnary(interceptor, ctx, getRatesMethod)
+
+	// Assert
+	assert.NoError(t, first)
+	assert.NoError(t, second)
+
+	st := status.Convert(rejected)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = info
+		}
+	}
+	require.NotNil(t, retryInfo)
+	assert.Equal(t, time.Second, retryInfo.RetryDelay.AsDuration())
+
+	// Квота восстанавливается со временем
+	*now = now.Add(time.Second)
+	assert.NoError(t, callUnary(interceptor, ctx, getRatesMethod))
+}
+
+func TestQuota_KeysClientsByPrincipalOrAddress(t *testing.T) {
+	// Arrange
+	quota, _ := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 1}})
+	interceptor := quota.UnaryServerInterceptor()
+	first := peerContext("10.0.0.1:5000")
+	// Тот же адрес с другого порта - тот же клиент
+	samePeer := peerContext("10.0.0.1:6000")
+	authenticated := context.WithValue(first, principalKey{}, &Principal{Subject: "consumer-a", Method: "api_key"})
+
+	// Act & Assert
+	assert.NoError(t, callUnary(interceptor, first, getRatesMethod))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callUnary(interceptor, samePeer, getRatesMethod)))
+	assert.NoError(t, callUnary(interceptor, authenticated, getRatesMethod))
+	assert.NoError(t, callUnary(interceptor, peerContext("10.0.0.2:5000"), getRatesMethod))
+}
+
+func TestQuota_UnverifiedAPIKeysDoNotGrantNewQuota(t *testing.T) {
+	// Arrange
+	quota, _ := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 1}})
+	interceptor := quota.UnaryServerInterceptor()
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(peerContext("10.0.0.1:5000"), metadata.Pairs("x-api-key", key))
+	}
+
+	// Act
+	first := callUnary(interceptor, withKey("random-1"), getRatesMethod)
+	var rejected int
+	for _, key := range []string{"random-2", "random-3", "random-4"} {
+		if status.Code(callUnary(interceptor, withKey(key), getRatesMethod)) == codes.ResourceExhausted {
+			rejected++
+		}
+	}
+
+	// Assert
+	assert.NoError(t, first)
+	assert.Equal(t, 3, rejected)
+	assert.Len(t, quota.buckets, 1)
+}
+
+func TestQuota_KeysGatewayClientsByForwardedAddress(t *testing.T) {
+	// Arrange
+	quota, _ := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 1}})
+	interceptor := quota.UnaryServerInterceptor()
+	gateway := peerContext("127.0.0.1:5000")
+	viaGateway := func(forwarded string) context.Context {
+		return metadata.NewIncomingContext(gateway, metadata.Pairs("x-forwarded-for", forwarded))
+	}
+
+	// Act & Assert
+	assert.NoError(t, callUnary(interceptor, viaGateway("203.0.113.1"), getRatesMethod))
+	assert.NoError(t, callUnary(interceptor, viaGateway("203.0.113.2"), getRatesMethod))
+	// Адрес, подставленный клиентом перед реальным, не дает новой квоты
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callUnary(interceptor, viaGateway("198.51.100.7, 203.0.113.1"), getRatesMethod)))
+	// Удаленный клиент не может выдать себя за другого через x-forwarded-for
+	remote := metadata.NewIncomingContext(peerContext("10.0.0.1:5000"), metadata.Pairs("x-forwarded-for", "203.0.113.3"))
+	assert.NoError(t, callUnary(interceptor, remote, getRatesMethod))
+	remote = metadata.NewIncomingContext(peerContext("10.0.0.1:5000"), metadata.Pairs("x-forwarded-for", "203.0.113.4"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callUnary(interceptor, remote, getRatesMethod)))
+}
+
+func TestQuota_MethodLimits(t *testing.T) {
+	// Arrange
+	quota, _ := newTestQuota(QuotaConfig{
+		Default: Limit{Rate: 1, Burst: 1},
+		Methods: map[string]Limit{
+			"GetRates":                     {Rate: 1, Burst: 3},
+			"/grpc.health.v1.Health/Check": {},
+		},
+	})
+	interceptor := quota.UnaryServerInterceptor()
+	ctx := peerContext("10.0.0.1:5000")
+
+	// Act & Assert
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, callUnary(interceptor, ctx, getRatesMethod))
+	}
+	assert.Error(t, callUnary(interceptor, ctx, getRatesMethod))
+
+	// Квота считается отдельно для каждого метода
+	assert.NoError(t, callUnary(interceptor, ctx, "/rate_service.v1.RateService/GetOrderBook"))
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, callUnary(interceptor, ctx, "/grpc.health.v1.Health/Check"))
+	}
+}
+
+func TestQuota_Stream(t *testing.T) {
+	// Arrange
+	quota, _ := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 1}})
+	interceptor := quota.StreamServerInterceptor()
+	stream := &testStream{ctx: peerContext("10.0.0.1:5000")}
+	info := &grpc.StreamServerInfo{FullMethod: "/rate_service.v1.RateService/SubscribeRates", IsServerStream: true}
+	handler := func(srv interface{}, stream grpc.ServerStream) error { return nil }
+
+	// Act
+	first := interceptor(nil, stream, info, handler)
+	second := interceptor(nil, stream, info, handler)
+
+	// Assert
+	assert.NoError(t, first)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(second))
+}
+
+func TestQuota_ForgetsIdleClients(t *testing.T) {
+	quota, now := newTestQuota(QuotaConfig{Default: Limit{Rate: 1, Burst: 1}, IdleTTL: time.Minute})
+	interceptor := quota.UnaryServerInterceptor()
+
+	assert.NoError(t, callUnary(interceptor, peerContext("10.0.0.1:5000"), getRatesMethod))
+	*now = now.Add(2 * time.Minute)
+	assert.NoError(t, callUnary(interceptor, peerContext("10.0.0.2:5000"), getRatesMethod))
+
+	assert.Len(t, quota.buckets, 1)
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("GetRates=5:10, SubscribeRates=0.5,/grpc.health.v1.Health/Check=0")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"GetRates":                     {Rate: 5, Burst: 10},
+		"SubscribeRates":               {Rate: 0.5},
+		"/grpc.health.v1.Health/Check": {},
+	}, limits)
+
+	empty, err := ParseLimits("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, value := range []string{"GetRates", "GetRates=fast", "GetRates=5:many", "=5"} {
+		_, err := ParseLimits(value)
+		assert.Error(t, err, value)
+	}
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
+)
+
+// RequestIDHeader - ключ метаданных с идентификатором запроса
+const RequestIDHeader = "x-request-id"
+
+// maxRequestIDLength ограничивает идентификатор клиента, чтобы он не раздувал логи
+const maxRequestIDLength = 128
+
+// RequestIDUnaryServerInterceptor берет идентификатор запроса из метаданных
+// x-request-id или создает новый, сохраняет его в контексте для логов и
+// трассы и возвращает клиенту в заголовке ответа
+func RequestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withRequestID(ctx)
+		return handler(ctx, req)
+	}
+}
+
+// RequestIDStreamServerInterceptor делает то же для потоков
+func RequestIDStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withRequestID(stream.Context())
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var requestID string
+	if values := md.Get(RequestIDHeader); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+		requestID = values[0]
+	}
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", requestID))
+	// Ошибка возможна, только если заголовки уже отправлены, а перехватчик работает до обработчика
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+	return logger.ContextWithRequestID(ctx, requestID)
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
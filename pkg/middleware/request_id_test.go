@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"studentgit.kata.academy/KonstantinDolgov/grpc-rate-service/pkg/logger"
+)
+
+func requestIDFromHandler(t *testing.T, ctx context.Context) string {
+	t.Helper()
+	var requestID string
+	_, err := RequestIDUnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: getRatesMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			requestID, _ = logger.RequestIDFromContext(ctx)
+			return "ok", nil
+		})
+	assert.NoError(t, err)
+	return requestID
+}
+
+func TestRequestID_UsesIncomingMetadata(t *testing.T) {
+	// Arrange
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, "req-42"))
+
+	// Act
+	requestID := requestIDFromHandler(t, ctx)
+
+	// Assert
+	assert.Equal(t, "req-42", requestID)
+}
+
+func TestRequestID_GeneratesMissingOrTooLong(t *testing.T) {
+	// Arrange
+	tooLong := make([]byte, maxRequestIDLength+1)
+	for i := range tooLong {
+		tooLong[i] = 'a'
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeader, string(tooLong)))
+
+	// Act
+	generated := requestIDFromHandler(t, context.Background())
+	replaced := requestIDFromHandler(t, ctx)
+
+	// Assert
+	assert.Len(t, generated, 32)
+	assert.Len(t, replaced, 32)
+	assert.NotEqual(t, generated, replaced)
+}
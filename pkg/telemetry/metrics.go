@@ -51,6 +51,64 @@ var (
 		},
 		[]string{"symbol", "status"},
 	)
+
+	QuoteCacheHitCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "quote_cache_hits_total",
+			Help: "Total number of GetRates requests served from the quote cache",
+		},
+		[]string{"symbol"},
+	)
+
+	QuoteCacheMissCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "quote_cache_misses_total",
+			Help: "Total number of GetRates requests not served from the quote cache",
+		},
+		[]string{"symbol"},
+	)
+)
+
+// Метрики фонового сбора курсов
+var (
+	CollectorFetchCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "collector_fetch_total",
+			Help: "Total number of scheduled rate collections by result",
+		},
+		[]string{"symbol", "status"},
+	)
+
+	CollectorLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "collector_last_success_timestamp_seconds",
+			Help: "Unix time of the last successfully collected rate",
+		},
+		[]string{"symbol"},
+	)
+)
+
+// Метрики клиентов бирж
+var (
+	// ExchangeCircuitState - состояние автомата отключения биржи: 0 - замкнут, 1 - пробный запрос, 2 - разомкнут
+	ExchangeCircuitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "exchange_circuit_breaker_state",
+			Help: "Exchange circuit breaker state: 0 - closed, 1 - half-open, 2 - open",
+		},
+		[]string{"exchange"},
+	)
+)
+
+// Метрики квот клиентов
+var (
+	QuotaRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_quota_rejected_total",
+			Help: "Total number of gRPC requests rejected by the per-client quota",
+		},
+		[]string{"method"},
+	)
 )
 
 func init() {
@@ -58,6 +116,12 @@ func init() {
 	prometheus.MustRegister(RequestCounter)
 	prometheus.MustRegister(RequestDuration)
 	prometheus.MustRegister(RateFetchCounter)
+	prometheus.MustRegister(QuoteCacheHitCounter)
+	prometheus.MustRegister(QuoteCacheMissCounter)
+	prometheus.MustRegister(CollectorFetchCounter)
+	prometheus.MustRegister(CollectorLastSuccess)
+	prometheus.MustRegister(ExchangeCircuitState)
+	prometheus.MustRegister(QuotaRejectedCounter)
 }
 
 // InitMetrics инициализирует метрики с использованием Prometheus и OpenTelemetry
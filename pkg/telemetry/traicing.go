@@ -2,64 +2,100 @@ package telemetry
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
+)
+
+// Экспортеры трассировки
+const (
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+	// ExporterStdout и ExporterFile пишут спаны в JSON для локальной отладки и тестов
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
 )
 
 type TracingConfig struct {
 	ServiceName    string
 	ServiceVersion string
 	Environment    string
-	OTLPEndpoint   string
+	// Exporter - куда отправляются спаны; пустое значение - ExporterOTLPGRPC
+	Exporter string
+	// SamplingRatio - доля записываемых корневых трасс от 0 до 1. Дочерние спаны
+	// следуют решению родителя, в том числе пришедшему от клиента.
+	SamplingRatio float64
+	// FilePath - файл для ExporterFile, спаны дописываются в конец
+	FilePath     string
+	OTLPEndpoint string
+	// OTLPInsecure отключает TLS при подключении к коллектору (только для локальной разработки)
+	OTLPInsecure bool
+	// OTLPTLS - настройки TLS подключения к коллектору; nil - системные CA
+	OTLPTLS *tls.Config
 }
 
-// InitTracing инициализирует трассировку с использованием OpenTelemetry
+// InitTracing инициализирует трассировку с использованием OpenTelemetry.
+// Атрибуты ресурса дополняются из OTEL_RESOURCE_ATTRIBUTES и OTEL_SERVICE_NAME.
 func InitTracing(ctx context.Context, config TracingConfig, logger *zap.Logger) (func(context.Context) error, error) {
+	if config.SamplingRatio < 0 || config.SamplingRatio > 1 {
+		return nil, fmt.Errorf("invalid sampling ratio %v: expected a value from 0 to 1", config.SamplingRatio)
+	}
+	config.Exporter = strings.ToLower(strings.TrimSpace(config.Exporter))
+	if config.Exporter == "" {
+		config.Exporter = ExporterOTLPGRPC
+	}
+
 	logger.Info("Initializing OpenTelemetry tracing",
 		zap.String("service", config.ServiceName),
-		zap.String("endpoint", config.OTLPEndpoint))
+		zap.String("exporter", config.Exporter),
+		zap.String("endpoint", config.OTLPEndpoint),
+		zap.Float64("sampling_ratio", config.SamplingRatio))
 
-	// Создаем ресурс с информацией о сервисе
+	// Создаем ресурс с информацией о сервисе; атрибуты из окружения добавляются
+	// последними, чтобы можно было указать, например, имя пода
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceName(config.ServiceName),
 			semconv.ServiceVersion(config.ServiceVersion),
 			semconv.DeploymentEnvironment(config.Environment),
 		),
+		resource.WithFromEnv(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OpenTelemetry resource: %w", err)
 	}
 
-	// Настраиваем коннект к коллектору OTLP (например, Jaeger или Collector)
-	opts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(config.OTLPEndpoint),
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
-		otlptracegrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+	exporter, err := newExporter(ctx, config, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	client := otlptracegrpc.NewClient(opts...)
-	exporter, err := otlptrace.New(ctx, client)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	// Локальные экспортеры пишут спаны сразу, чтобы их можно было проверить
+	// без остановки сервиса
+	spanProcessor := sdktrace.WithBatcher(exporter)
+	if config.Exporter == ExporterStdout || config.Exporter == ExporterFile {
+		spanProcessor = sdktrace.WithSyncer(exporter)
 	}
 
 	// Создаем трасировщик
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+		spanProcessor,
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SamplingRatio))),
 	)
 
 	// Устанавливаем глобальный трасировщик
@@ -77,3 +113,87 @@ func InitTracing(ctx context.Context, config TracingConfig, logger *zap.Logger)
 		return tp.Shutdown(ctx)
 	}, nil
 }
+
+// newExporter создает экспортер, выбранный в config.Exporter
+func newExporter(ctx context.Context, config TracingConfig, logger *zap.Logger) (sdktrace.SpanExporter, error) {
+	switch config.Exporter {
+	case ExporterOTLPGRPC:
+		// Настраиваем коннект к коллектору OTLP (например, Jaeger или Collector)
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(config.OTLPEndpoint),
+		}
+		if config.OTLPInsecure {
+			logger.Warn("OTLP exporter uses a plaintext connection", zap.String("endpoint", config.OTLPEndpoint))
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(otlpTLSConfig(config))))
+		}
+
+		exporter, err := otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, nil
+
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(config.OTLPEndpoint),
+		}
+		if config.OTLPInsecure {
+			logger.Warn("OTLP exporter uses a plaintext connection", zap.String("endpoint", config.OTLPEndpoint))
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(otlpTLSConfig(config)))
+		}
+
+		exporter, err := otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP HTTP exporter: %w", err)
+		}
+		return exporter, nil
+
+	case ExporterStdout:
+		exporter, err := stdouttrace.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		return exporter, nil
+
+	case ExporterFile:
+		if config.FilePath == "" {
+			return nil, errors.New("trace file path is required for the file exporter")
+		}
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to create file exporter: %w", err)
+		}
+		return &fileExporter{SpanExporter: exporter, file: file}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q: expected one of %s", config.Exporter,
+			strings.Join([]string{ExporterOTLPGRPC, ExporterOTLPHTTP, ExporterStdout, ExporterFile}, ", "))
+	}
+}
+
+// otlpTLSConfig возвращает настройки TLS подключения к коллектору
+func otlpTLSConfig(config TracingConfig) *tls.Config {
+	if config.OTLPTLS != nil {
+		return config.OTLPTLS
+	}
+	return &tls.Config{MinVersion: tls.VersionTLS12}
+}
+
+// fileExporter закрывает файл после остановки экспортера
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.SpanExporter.Shutdown(ctx), e.file.Close())
+}
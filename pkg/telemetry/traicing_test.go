@@ -0,0 +1,58 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestInitTracing_FileExporterAndSampling(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "traces.json")
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "region=eu")
+	shutdown, err := InitTracing(context.Background(), TracingConfig{
+		ServiceName: "rate-service",
+		Exporter:    ExporterFile,
+		FilePath:    path,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	tracer := otel.Tracer("test")
+
+	// Родитель с флагом выборки записывает дочерний спан даже при нулевой доле
+	parent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	// Act
+	_, root := tracer.Start(context.Background(), "root-span")
+	root.End()
+	_, child := tracer.Start(parent, "child-span")
+	child.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	// Assert
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "root-span")
+	assert.Contains(t, string(data), "child-span")
+	assert.Contains(t, string(data), "region")
+}
+
+func TestInitTracing_InvalidConfig(t *testing.T) {
+	_, ratioErr := InitTracing(context.Background(), TracingConfig{SamplingRatio: 1.5}, zap.NewNop())
+	_, exporterErr := InitTracing(context.Background(), TracingConfig{Exporter: "jaeger"}, zap.NewNop())
+	_, fileErr := InitTracing(context.Background(), TracingConfig{Exporter: ExporterFile}, zap.NewNop())
+
+	assert.Error(t, ratioErr)
+	assert.Error(t, exporterErr)
+	assert.Error(t, fileErr)
+}
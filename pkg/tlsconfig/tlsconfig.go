@@ -0,0 +1,243 @@
+// Package tlsconfig собирает настройки TLS для gRPC-сервера и исходящих соединений
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNoCertificates возвращается, если в файле CA нет ни одного сертификата
+var ErrNoCertificates = errors.New("no certificates found in CA file")
+
+// ServerConfig содержит настройки TLS сервера
+type ServerConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile - сертификаты CA для проверки клиентов; пусто - клиентские сертификаты не проверяются
+	ClientCAFile string
+	// RequireClientCert включает mTLS: соединение без сертификата, подписанного ClientCAFile, отклоняется
+	RequireClientCert bool
+	// ReloadInterval - как часто проверяются изменения файлов; 0 - файлы не перечитываются
+	ReloadInterval time.Duration
+}
+
+// Reloader хранит сертификат сервера и CA клиентов и перечитывает их при
+// изменении файлов, не прерывая установленные соединения
+type Reloader struct {
+	config ServerConfig
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReloader загружает сертификаты сервера
+func NewReloader(logger *zap.Logger, config ServerConfig) (*Reloader, error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, errors.New("TLS certificate and key files are required")
+	}
+	if config.RequireClientCert && config.ClientCAFile == "" {
+		return nil, errors.New("client CA file is required to verify client certificates")
+	}
+
+	r := &Reloader{
+		config: config,
+		logger: logger,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig возвращает настройки для сервера. Сертификат и CA клиентов
+// берутся при каждом рукопожатии, поэтому перезагрузка действует сразу.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+			}
+			if r.clientCA != nil {
+				config.ClientCAs = r.clientCA
+				config.ClientAuth = tls.VerifyClientCertIfGiven
+				if r.config.RequireClientCert {
+					config.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return config, nil
+		},
+	}
+}
+
+// Reload перечитывает файлы. При ошибке продолжают действовать прежние сертификаты.
+func (r *Reloader) Reload() error {
+	// Время изменения запоминается до чтения: если файл заменят во время
+	// загрузки, следующая проверка перечитает его еще раз
+	modTimes, err := r.modTimesOfFiles()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var clientCA *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		clientCA, err = LoadCertPool(r.config.ClientCAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = clientCA
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+// Start запускает проверку изменений файлов раз в ReloadInterval
+func (r *Reloader) Start(ctx context.Context) {
+	if r.config.ReloadInterval <= 0 {
+		return
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.ReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.reloadIfChanged()
+			}
+		}
+	}()
+}
+
+// Stop останавливает проверку изменений файлов
+func (r *Reloader) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+		r.wg.Wait()
+	}
+}
+
+func (r *Reloader) reloadIfChanged() {
+	modTimes, err := r.modTimesOfFiles()
+	if err != nil {
+		r.logger.Error("Failed to check TLS files", zap.Error(err))
+		return
+	}
+
+	r.mu.RLock()
+	changed := false
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := r.Reload(); err != nil {
+		r.logger.Error("Failed to reload TLS certificates, keeping previous ones", zap.Error(err))
+		return
+	}
+	r.logger.Info("TLS certificates reloaded", zap.String("cert_file", r.config.CertFile))
+}
+
+func (r *Reloader) modTimesOfFiles() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// ClientConfig содержит настройки TLS исходящего соединения
+type ClientConfig struct {
+	// CAFile - сертификаты CA сервера; пусто - системные
+	CAFile string
+	// CertFile и KeyFile - сертификат клиента для mTLS; пусто - без сертификата
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// NewClientTLSConfig собирает настройки TLS для подключения к серверу
+func NewClientTLSConfig(config ClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.ServerName,
+	}
+
+	if config.CAFile != "" {
+		pool, err := LoadCertPool(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// LoadCertPool читает сертификаты CA в формате PEM
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", ErrNoCertificates, file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,200 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue выпускает сертификат и возвращает его и ключ в формате PEM
+func (ca *testCA) issue(t *testing.T, name string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// handshake подключается к серверу с настройками server и возвращает серийный номер его сертификата
+func handshake(t *testing.T, server *tls.Config, client *tls.Config) (int64, error) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.(*tls.Conn).Handshake()
+		// Ждем, пока клиент прочитает результат рукопожатия
+		buf := make([]byte, 1)
+		_, _ = conn.Read(buf)
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	// В TLS 1.3 отказ в клиентском сертификате приходит после рукопожатия
+	_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		if netErr, ok := err.(net.Error); !ok || !netErr.Timeout() {
+			return 0, err
+		}
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestReloader_MutualTLSAndReload(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	ca := newTestCA(t, "test-ca")
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	modTime := time.Now().Add(-time.Minute)
+	serverCert, serverKey := ca.issue(t, "localhost", 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, serverCert, modTime)
+	writeFile(t, keyFile, serverKey, modTime)
+	writeFile(t, caFile, ca.pem, modTime)
+
+	reloader, err := NewReloader(zap.NewNop(), ServerConfig{
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		ClientCAFile:      caFile,
+		RequireClientCert: true,
+	})
+	require.NoError(t, err)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, "client", 20, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	withCert := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{clientCert}}
+	withoutCert := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	// Act & Assert: клиент с сертификатом подключается, без сертификата - нет
+	serial, err := handshake(t, reloader.TLSConfig(), withCert)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), serial)
+
+	_, err = handshake(t, reloader.TLSConfig(), withoutCert)
+	assert.Error(t, err)
+
+	// Новый сертификат подхватывается без перезапуска сервера
+	serverCert, serverKey = ca.issue(t, "localhost", 11, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, serverCert, modTime.Add(30*time.Second))
+	writeFile(t, keyFile, serverKey, modTime.Add(30*time.Second))
+	reloader.reloadIfChanged()
+
+	serial, err = handshake(t, reloader.TLSConfig(), withCert)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), serial)
+}
+
+func TestReloader_KeepsPreviousCertificateOnBrokenFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "test-ca")
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+
+	modTime := time.Now().Add(-time.Minute)
+	serverCert, serverKey := ca.issue(t, "localhost", 10, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, serverCert, modTime)
+	writeFile(t, keyFile, serverKey, modTime)
+
+	reloader, err := NewReloader(zap.NewNop(), ServerConfig{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+
+	writeFile(t, certFile, []byte("broken"), modTime.Add(time.Second))
+	reloader.reloadIfChanged()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	serial, err := handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), serial)
+}
+
+func TestNewReloader_Validation(t *testing.T) {
+	_, err := NewReloader(zap.NewNop(), ServerConfig{CertFile: "server.crt"})
+	assert.Error(t, err)
+
+	_, err = NewReloader(zap.NewNop(), ServerConfig{CertFile: "server.crt", KeyFile: "server.key", RequireClientCert: true})
+	assert.Error(t, err)
+}
+
+func TestLoadCertPool_NoCertificates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+	_, err := LoadCertPool(path)
+
+	assert.ErrorIs(t, err, ErrNoCertificates)
+}